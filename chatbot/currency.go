@@ -0,0 +1,65 @@
+package chatbot
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Money is the amount and ISO 4217 currency code of a "currency" entity
+type Money struct {
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// currencySymbols maps the currency symbols recognized before an amount to their codes
+var currencySymbols = map[string]string{
+	"$": "USD",
+	"€": "EUR",
+	"£": "GBP",
+	"¥": "JPY",
+}
+
+// currencyPattern matches amounts written with a leading symbol ("$19.99", "€5") or a
+// trailing code ("20 USD"), allowing thousands separators ("$1,299")
+var currencyPattern = regexp.MustCompile(
+	`[$€£¥]\s?\d{1,3}(?:,\d{3})+(?:\.\d+)?(?:\s?(?i:usd|eur|gbp|jpy|cad|aud|chf|cny|inr)\b)?` +
+		`|[$€£¥]\s?\d+(?:\.\d+)?(?:\s?(?i:usd|eur|gbp|jpy|cad|aud|chf|cny|inr)\b)?` +
+		`|\b(?:\d{1,3}(?:,\d{3})+|\d+)(?:\.\d+)?\s?(?i:usd|eur|gbp|jpy|cad|aud|chf|cny|inr)\b`)
+
+// parseMoney converts a currencyPattern match into a Money value. A trailing code takes
+// precedence over the symbol, so "$20 CAD" is in Canadian dollars.
+func parseMoney(value string) (Money, bool) {
+	var money Money
+	rest := value
+	for symbol, code := range currencySymbols {
+		if strings.HasPrefix(rest, symbol) {
+			money.Currency = code
+			rest = strings.TrimSpace(rest[len(symbol):])
+			break
+		}
+	}
+
+	// Split the amount from a trailing code
+	end := strings.LastIndexFunc(rest, func(r rune) bool {
+		return r >= '0' && r <= '9'
+	})
+	if end < 0 {
+		return Money{}, false
+	}
+	if code := strings.TrimSpace(rest[end+1:]); code != "" {
+		money.Currency = strings.ToUpper(code)
+	}
+
+	amount, err := strconv.ParseFloat(strings.ReplaceAll(rest[:end+1], ",", ""), 64)
+	if err != nil || money.Currency == "" {
+		return Money{}, false
+	}
+	money.Amount = amount
+	return money, true
+}
+
+// String formats the amount in its canonical form, such as "19.99 USD"
+func (m Money) String() string {
+	return strconv.FormatFloat(m.Amount, 'f', -1, 64) + " " + m.Currency
+}
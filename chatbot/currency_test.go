@@ -0,0 +1,52 @@
+package chatbot
+
+import (
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Money
+		valid bool
+	}{
+		{"$19.99", Money{Amount: 19.99, Currency: "USD"}, true},
+		{"€ 5", Money{Amount: 5, Currency: "EUR"}, true},
+		{"$1,299", Money{Amount: 1299, Currency: "USD"}, true},
+		{"20 usd", Money{Amount: 20, Currency: "USD"}, true},
+		{"$20 CAD", Money{Amount: 20, Currency: "CAD"}, true},
+		{"20", Money{}, false},
+	}
+
+	for _, test := range tests {
+		got, valid := parseMoney(test.value)
+		if got != test.want || valid != test.valid {
+			t.Errorf("parseMoney(%q) = %+v, %v, want %+v, %v", test.value, got, valid, test.want, test.valid)
+		}
+	}
+}
+
+func TestEntityExtractorCurrency(t *testing.T) {
+	ctx := core.NewContext(Message{Text: "It costs £1,250.50 or 1400 eur"})
+	if err := NewEntityExtractorPlugin().Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	entities, _ := core.Value[[]Entity](ctx, "entities")
+	var normalized []string
+	for _, entity := range entities {
+		if entity.Type != "currency" {
+			continue
+		}
+		if entity.Money == nil {
+			t.Errorf("currency entity %q has no Money", entity.Value)
+			continue
+		}
+		normalized = append(normalized, entity.Normalized)
+	}
+	if len(normalized) != 2 || normalized[0] != "1250.5 GBP" || normalized[1] != "1400 EUR" {
+		t.Errorf("normalized currencies = %v, want [1250.5 GBP 1400 EUR]", normalized)
+	}
+}
@@ -0,0 +1,101 @@
+package chatbot
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// IntentClassifier determines the intent of a message text. IntentClassifierPlugin is the
+// keyword-based implementation.
+type IntentClassifier interface {
+	Classify(text string) Intent
+}
+
+// WeightedClassifier is an ensemble member and the weight of its vote
+type WeightedClassifier struct {
+	Classifier IntentClassifier
+	Weight     float64 // Zero or less defaults to 1
+}
+
+// EnsembleClassifierPlugin combines several intent classifiers by weighted confidence voting.
+// Each classifier votes for its intent with its confidence times its weight; the intent with
+// the most votes wins, with the votes divided by the total weight as its confidence.
+// Ties go to the intent whose first vote came from the earliest classifier.
+type EnsembleClassifierPlugin struct {
+	members []WeightedClassifier
+}
+
+// NewEnsembleClassifierPlugin creates an ensemble of the given classifiers
+func NewEnsembleClassifierPlugin(classifiers ...WeightedClassifier) *EnsembleClassifierPlugin {
+	members := make([]WeightedClassifier, len(classifiers))
+	for i, member := range classifiers {
+		if member.Weight <= 0 {
+			member.Weight = 1
+		}
+		members[i] = member
+	}
+
+	return &EnsembleClassifierPlugin{
+		members: members,
+	}
+}
+
+// Execute classifies the message text and stores the winning intent in Context metadata.
+// The votes per intent are stored under "intent_votes".
+func (p *EnsembleClassifierPlugin) Execute(ctx *core.Context) error {
+	// Extract message from context
+	msg, ok := ctx.GetData().(Message)
+	if !ok {
+		return fmt.Errorf("expected Message type in context data")
+	}
+
+	intent, votes := p.vote(msg.Text)
+	ctx.Set("intent", intent)
+	ctx.Set("intent_votes", votes)
+
+	return nil
+}
+
+// Classify returns the winning intent of text, so ensembles can be nested
+func (p *EnsembleClassifierPlugin) Classify(text string) Intent {
+	intent, _ := p.vote(text)
+	return intent
+}
+
+// vote runs every classifier on text and returns the winning intent and the weighted
+// votes per intent
+func (p *EnsembleClassifierPlugin) vote(text string) (Intent, map[string]float64) {
+	votes := make(map[string]float64)
+	order := make([]string, 0, len(p.members))
+	totalWeight := 0.0
+
+	for _, member := range p.members {
+		totalWeight += member.Weight
+
+		intent := member.Classifier.Classify(text)
+		if intent.Type == "unknown" || intent.Confidence <= 0 {
+			continue
+		}
+		if _, seen := votes[intent.Type]; !seen {
+			order = append(order, intent.Type)
+		}
+		votes[intent.Type] += intent.Confidence * member.Weight
+	}
+
+	// Stable sort keeps the earliest-voted intent first on ties
+	sort.SliceStable(order, func(a, b int) bool {
+		return votes[order[a]] > votes[order[b]]
+	})
+
+	if len(order) == 0 {
+		return Intent{Type: "unknown", Confidence: 0.0}, votes
+	}
+
+	confidence := votes[order[0]] / totalWeight
+	if confidence > 1.0 {
+		confidence = 1.0
+	}
+	return Intent{Type: order[0], Confidence: confidence}, votes
+}
@@ -0,0 +1,61 @@
+package chatbot
+
+import (
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// fixedClassifier always returns the same intent.
+type fixedClassifier Intent
+
+func (c fixedClassifier) Classify(string) Intent { return Intent(c) }
+
+func TestEnsembleClassifierWeightedVote(t *testing.T) {
+	plugin := NewEnsembleClassifierPlugin(
+		WeightedClassifier{Classifier: fixedClassifier{Type: "question", Confidence: 0.6}},
+		WeightedClassifier{Classifier: fixedClassifier{Type: "command", Confidence: 0.9}, Weight: 3},
+		WeightedClassifier{Classifier: fixedClassifier{Type: "unknown", Confidence: 1}},
+	)
+
+	ctx := core.NewContext(Message{Text: "anything"})
+	if err := plugin.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	intent, _ := core.Value[Intent](ctx, "intent")
+	if intent.Type != "command" {
+		t.Errorf("intent = %q, want the heavier command vote", intent.Type)
+	}
+	if want := 0.9 * 3 / 5; intent.Confidence != want {
+		t.Errorf("confidence = %v, want %v", intent.Confidence, want)
+	}
+	votes, _ := core.Value[map[string]float64](ctx, "intent_votes")
+	if len(votes) != 2 || votes["question"] != 0.6 {
+		t.Errorf("intent_votes = %v, want question and command only", votes)
+	}
+}
+
+func TestEnsembleClassifierTiesAndUnknown(t *testing.T) {
+	tie := NewEnsembleClassifierPlugin(
+		WeightedClassifier{Classifier: fixedClassifier{Type: "greeting", Confidence: 0.5}},
+		WeightedClassifier{Classifier: fixedClassifier{Type: "farewell", Confidence: 0.5}},
+	)
+	if intent := tie.Classify("x"); intent.Type != "greeting" {
+		t.Errorf("tie = %q, want the earliest classifier's intent", intent.Type)
+	}
+
+	none := NewEnsembleClassifierPlugin(WeightedClassifier{Classifier: fixedClassifier{Type: "unknown"}})
+	if intent := none.Classify("x"); intent.Type != "unknown" || intent.Confidence != 0 {
+		t.Errorf("no votes = %+v, want unknown with zero confidence", intent)
+	}
+}
+
+func TestEnsembleClassifierWithKeywordClassifier(t *testing.T) {
+	nested := NewEnsembleClassifierPlugin(WeightedClassifier{Classifier: NewIntentClassifierPlugin()})
+	plugin := NewEnsembleClassifierPlugin(WeightedClassifier{Classifier: nested})
+
+	if intent := plugin.Classify("hello there"); intent.Type != "greeting" {
+		t.Errorf("intent = %q, want greeting from the nested keyword classifier", intent.Type)
+	}
+}
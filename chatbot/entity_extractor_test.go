@@ -0,0 +1,216 @@
+package chatbot
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+func TestEntityExtractorAddPattern(t *testing.T) {
+	extractor := NewEntityExtractorPlugin()
+	if err := extractor.AddPattern("order_id", `\bord-\d{6}\b`, "i"); err != nil {
+		t.Fatalf("AddPattern: %v", err)
+	}
+
+	var orders []string
+	for _, entity := range extractor.Extract("Where is ORD-123456?") {
+		if entity.Type == "order_id" {
+			orders = append(orders, entity.Value)
+			if entity.Confidence != DefaultPatternConfidence {
+				t.Errorf("confidence = %v, want %v", entity.Confidence, DefaultPatternConfidence)
+			}
+		}
+	}
+	if len(orders) != 1 || orders[0] != "ORD-123456" {
+		t.Errorf("order ids = %v, want [ORD-123456]", orders)
+	}
+
+	if err := extractor.AddPattern("bad", `\d+`, "x"); err == nil {
+		t.Error("AddPattern with unsupported flags succeeded, want an error")
+	}
+	if err := extractor.AddPattern("bad", `(`, ""); err == nil {
+		t.Error("AddPattern with an invalid pattern succeeded, want an error")
+	}
+}
+
+func TestEntityExtractorPatternTimeout(t *testing.T) {
+	text := strings.Repeat("a ", 20000) + "mail me at john@example.com"
+
+	extractor := NewEntityExtractorPlugin().WithPatternTimeout(time.Nanosecond)
+	if err := extractor.AddPattern("slow", `(?:a )+z`, ""); err != nil {
+		t.Fatalf("AddPattern: %v", err)
+	}
+
+	ctx := core.NewContext(Message{Text: text})
+	if err := extractor.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	// Only the custom pattern is abandoned; built-in entities are still extracted
+	warnings, _ := core.Value[[]string](ctx, "entity_warnings")
+	if len(warnings) != 1 || !strings.Contains(warnings[0], `"slow"`) {
+		t.Errorf("entity_warnings = %v, want one warning for the slow pattern", warnings)
+	}
+	entities, _ := core.Value[[]Entity](ctx, "entities")
+	if !hasEntity(entities, "email", "john@example.com") {
+		t.Errorf("entities = %v, want the email", entities)
+	}
+}
+
+func TestEntityExtractorNoTimeoutByDefault(t *testing.T) {
+	extractor := NewEntityExtractorPlugin()
+	if err := extractor.AddPattern("slow", `(?:a )+z`, ""); err != nil {
+		t.Fatalf("AddPattern: %v", err)
+	}
+
+	ctx := core.NewContext(Message{Text: "a a a z"})
+	if err := extractor.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if warnings, ok := core.Value[[]string](ctx, "entity_warnings"); ok {
+		t.Errorf("entity_warnings = %v, want none", warnings)
+	}
+	entities, _ := core.Value[[]Entity](ctx, "entities")
+	if !hasEntity(entities, "slow", "a a a z") {
+		t.Errorf("entities = %v, want the slow pattern's match", entities)
+	}
+}
+
+func TestEntityExtractorAddPatternConcurrent(t *testing.T) {
+	extractor := NewEntityExtractorPlugin().WithMaxEntities(0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if err := extractor.AddPattern(fmt.Sprintf("custom_%d_%d", i, j), `\bx\d\b`, ""); err != nil {
+					t.Errorf("AddPattern: %v", err)
+				}
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				extractor.Extract("call x1 at 555-123-4567")
+			}
+		}()
+	}
+	wg.Wait()
+
+	entities := extractor.Extract("x1")
+	if got := len(entities); got != 200 {
+		t.Errorf("got %d entities after adding 200 patterns, want 200", got)
+	}
+}
+
+// hasEntity reports whether entities include one of entityType with value
+func hasEntity(entities []Entity, entityType, value string) bool {
+	for _, entity := range entities {
+		if entity.Type == entityType && entity.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestEntityExtractorConfidence(t *testing.T) {
+	extractor := NewEntityExtractorPluginWithConfig(EntityExtractorConfig{Gazetteer: []string{"John"}}).
+		WithConfidence("name", 0.8)
+
+	want := map[string]float64{
+		"john@example.com": 0.95,
+		"John Smith":       0.8,
+		"42":               0.7,
+	}
+	for _, entity := range extractor.Extract("John Smith has 42 orders, email john@example.com") {
+		if confidence, ok := want[entity.Value]; ok && entity.Confidence != confidence {
+			t.Errorf("confidence of %s %q = %v, want %v", entity.Type, entity.Value, entity.Confidence, confidence)
+		}
+		delete(want, entity.Value)
+	}
+	if len(want) > 0 {
+		t.Errorf("entities not found: %v", want)
+	}
+}
+
+func TestEntityExtractorDefaultCap(t *testing.T) {
+	numbers := make([]string, 150)
+	for i := range numbers {
+		numbers[i] = fmt.Sprint(i)
+	}
+	ctx := core.NewContext(Message{Text: strings.Join(numbers, " ")})
+	if err := NewEntityExtractorPlugin().Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	entities, _ := core.Value[[]Entity](ctx, "entities")
+	if len(entities) != DefaultMaxEntities {
+		t.Fatalf("got %d entities, want %d", len(entities), DefaultMaxEntities)
+	}
+	if last := entities[len(entities)-1]; last.Value != "99" {
+		t.Errorf("last entity = %q, want the first entities in the text kept", last.Value)
+	}
+	if truncated, _ := core.Value[bool](ctx, "entities_truncated"); !truncated {
+		t.Error("entities_truncated = false, want true")
+	}
+	if warnings, _ := core.Value[[]string](ctx, "entity_warnings"); len(warnings) == 0 {
+		t.Error("no warning for the exceeded cap")
+	}
+}
+
+func TestEntityExtractorCaps(t *testing.T) {
+	text := "1 2 3 4 5 mail a@example.com"
+	tests := []struct {
+		name      string
+		extractor *EntityExtractorPlugin
+		numbers   int
+		truncated bool
+	}{
+		{"default", NewEntityExtractorPlugin(), 5, false},
+		{"per type", NewEntityExtractorPlugin().WithMaxEntitiesPerType("number", 2), 2, true},
+		{"per type zero", NewEntityExtractorPlugin().WithMaxEntitiesPerType("number", 0), 5, false},
+		{"total", NewEntityExtractorPlugin().WithMaxEntities(3), 3, true},
+		{"total zero", NewEntityExtractorPlugin().WithMaxEntities(0), 5, false},
+	}
+	for _, tt := range tests {
+		ctx := core.NewContext(Message{Text: text})
+		if err := tt.extractor.Execute(ctx); err != nil {
+			t.Fatalf("%s: Execute: %v", tt.name, err)
+		}
+		entities, _ := core.Value[[]Entity](ctx, "entities")
+		numbers := 0
+		for _, entity := range entities {
+			if entity.Type == "number" {
+				numbers++
+			}
+		}
+		truncated, _ := core.Value[bool](ctx, "entities_truncated")
+		if numbers != tt.numbers || truncated != tt.truncated {
+			t.Errorf("%s: got %d numbers, truncated %v, want %d, %v", tt.name, numbers, truncated, tt.numbers, tt.truncated)
+		}
+	}
+}
+
+func TestEntityExtractorOrder(t *testing.T) {
+	extractor := NewEntityExtractorPlugin()
+	if err := extractor.AddPattern("code", `\b\d{3}\b`, ""); err != nil {
+		t.Fatalf("AddPattern: %v", err)
+	}
+
+	want := []string{"code 123", "number 123", "email a@example.com", "number 7"}
+	for i := 0; i < 10; i++ {
+		var got []string
+		for _, entity := range extractor.Extract("123 a@example.com 7") {
+			got = append(got, entity.Type+" "+entity.Value)
+		}
+		if strings.Join(got, ", ") != strings.Join(want, ", ") {
+			t.Fatalf("entities = %v, want %v", got, want)
+		}
+	}
+}
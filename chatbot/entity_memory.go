@@ -0,0 +1,107 @@
+package chatbot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// EntityMemoryPlugin remembers the entities mentioned in a conversation so downstream
+// plugins can tell new information from repeats and avoid asking for it again.
+// Entities are compared by type and by normalized value when set (see
+// PhoneNormalizerPlugin), otherwise by case-insensitive value. Seen entities are kept in
+// the conversation store, so the plugin should share the store of ContextManagerPlugin
+// and run after it and after EntityExtractorPlugin.
+type EntityMemoryPlugin struct {
+	store ConversationStore
+}
+
+// NewEntityMemoryPlugin creates an entity memory backed by store. A nil store defaults to
+// a new in-memory store.
+func NewEntityMemoryPlugin(store ConversationStore) *EntityMemoryPlugin {
+	if store == nil {
+		store = NewMemoryConversationStore()
+	}
+	return &EntityMemoryPlugin{
+		store: store,
+	}
+}
+
+// Execute splits this message's entities into "new_entities", mentioned for the first
+// time in the session, and "seen_entities", mentioned in an earlier message
+func (p *EntityMemoryPlugin) Execute(ctx *core.Context) error {
+	// Extract message from context
+	msg, ok := ctx.GetData().(Message)
+	if !ok {
+		return fmt.Errorf("expected Message type in context data")
+	}
+
+	convState, exists, err := p.store.Load(msg.SessionID)
+	if err != nil {
+		return fmt.Errorf("failed to load conversation %q: %w", msg.SessionID, err)
+	}
+	if !exists {
+		convState = ConversationState{
+			History:   make([]Message, 0),
+			UserPrefs: make(map[string]any),
+		}
+	}
+
+	var entities []Entity
+	if entitiesData, exists := ctx.Get("entities"); exists {
+		if e, ok := entitiesData.([]Entity); ok {
+			entities = e
+		}
+	}
+
+	seenBefore := make(map[string]bool, len(convState.SeenEntities))
+	for _, key := range convState.SeenEntities {
+		seenBefore[key] = true
+	}
+
+	newEntities := make([]Entity, 0)
+	seenEntities := make([]Entity, 0)
+	for _, entity := range entities {
+		key := entityKey(entity)
+		if seenBefore[key] {
+			seenEntities = append(seenEntities, entity)
+			continue
+		}
+		// Repeats within this message count as new once
+		seenBefore[key] = true
+		convState.SeenEntities = append(convState.SeenEntities, key)
+		newEntities = append(newEntities, entity)
+	}
+
+	ctx.Set("new_entities", newEntities)
+	ctx.Set("seen_entities", seenEntities)
+
+	if len(newEntities) == 0 {
+		return nil
+	}
+
+	// Persist the newly seen entities
+	if err := p.store.Save(msg.SessionID, convState); err != nil {
+		return fmt.Errorf("failed to save conversation %q: %w", msg.SessionID, err)
+	}
+
+	ctx.SetState(fmt.Sprintf("conversation:%s", msg.SessionID), convState)
+	ctx.Set("conversation_state", convState)
+
+	return nil
+}
+
+// HealthCheck reports the health of the conversation store
+func (p *EntityMemoryPlugin) HealthCheck() error {
+	return storeHealth(p.store)
+}
+
+// entityKey identifies an entity across messages
+func entityKey(entity Entity) string {
+	value := entity.Normalized
+	if value == "" {
+		value = strings.ToLower(entity.Value)
+	}
+	return entity.Type + ":" + value
+}
@@ -0,0 +1,53 @@
+package chatbot
+
+import (
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// remember runs plugin on a message with entities and returns the new and seen entities.
+func remember(t *testing.T, plugin *EntityMemoryPlugin, session string, entities ...Entity) ([]Entity, []Entity) {
+	t.Helper()
+	ctx := core.NewContext(Message{Text: "text", SessionID: session})
+	ctx.Set("entities", entities)
+	if err := plugin.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	newEntities, _ := core.Value[[]Entity](ctx, "new_entities")
+	seenEntities, _ := core.Value[[]Entity](ctx, "seen_entities")
+	return newEntities, seenEntities
+}
+
+func TestEntityMemory(t *testing.T) {
+	plugin := NewEntityMemoryPlugin(nil)
+	email := Entity{Type: "email", Value: "Ann@Example.com"}
+	phone := Entity{Type: "phone", Value: "555-123-4567", Normalized: "+15551234567"}
+
+	newEntities, seen := remember(t, plugin, "s1", email, phone, email)
+	if len(newEntities) != 2 || len(seen) != 1 {
+		t.Errorf("first message: new = %v, seen = %v, want 2 new and the repeat seen", newEntities, seen)
+	}
+
+	// Same email in another case, same phone in another format
+	newEntities, seen = remember(t, plugin, "s1",
+		Entity{Type: "email", Value: "ann@example.com"},
+		Entity{Type: "phone", Value: "(555) 123 4567", Normalized: "+15551234567"})
+	if len(newEntities) != 0 || len(seen) != 2 {
+		t.Errorf("second message: new = %v, seen = %v, want both seen", newEntities, seen)
+	}
+
+	if newEntities, _ = remember(t, plugin, "s2", email); len(newEntities) != 1 {
+		t.Errorf("other session: new = %v, want the email new", newEntities)
+	}
+}
+
+func TestEntityMemorySharesStore(t *testing.T) {
+	store := NewMemoryConversationStore()
+	remember(t, NewEntityMemoryPlugin(store), "s1", Entity{Type: "email", Value: "a@example.com"})
+
+	_, seen := remember(t, NewEntityMemoryPlugin(store), "s1", Entity{Type: "email", Value: "a@example.com"})
+	if len(seen) != 1 {
+		t.Errorf("seen = %v, want the entity remembered in the shared store", seen)
+	}
+}
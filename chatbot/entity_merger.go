@@ -0,0 +1,161 @@
+package chatbot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// DefaultMergeGap is the most characters allowed between two entities merged by a rule
+// without a MaxGap
+const DefaultMergeGap = 1
+
+// MergeRule merges an entity of type First directly followed by an entity of type Second.
+// An entity merged by the rule can in turn be followed by another of type Second.
+type MergeRule struct {
+	First  string // type of the earlier entity
+	Second string // type of the later entity
+	Result string // type of the merged entity (empty keeps First)
+	// MaxGap is the most characters allowed between the two entities, such as 4 to merge
+	// "tomorrow at 3pm" (default: DefaultMergeGap). The gap may only hold spaces and
+	// punctuation unless Connectors lists the words allowed in it.
+	MaxGap int
+	// Connectors are words allowed in the gap, such as "at" or "and"
+	Connectors []string
+}
+
+// DefaultMergeRules returns rules that join single-word names, as found by custom name
+// patterns, into full names
+func DefaultMergeRules() []MergeRule {
+	return []MergeRule{
+		{First: "name", Second: "name"},
+	}
+}
+
+// EntityMergerPlugin merges adjacent entities, such as a first and a last name found
+// separately, into one entity spanning both. Merges chain, so three adjacent names become
+// one. It replaces "entities" with the merged list, sorted by position, and keeps the
+// entities as extracted in "original_entities". A merged entity has the lower confidence
+// of its parts. Place it after EntityExtractorPlugin.
+type EntityMergerPlugin struct {
+	rules []MergeRule
+}
+
+// NewEntityMergerPlugin creates an entity merger with the given rules. With no rules it
+// uses DefaultMergeRules.
+func NewEntityMergerPlugin(rules ...MergeRule) *EntityMergerPlugin {
+	if len(rules) == 0 {
+		rules = DefaultMergeRules()
+	}
+	return &EntityMergerPlugin{
+		rules: rules,
+	}
+}
+
+// Execute merges the adjacent entities in the "entities" metadata
+func (p *EntityMergerPlugin) Execute(ctx *core.Context) error {
+	msg, ok := ctx.GetData().(Message)
+	if !ok {
+		return fmt.Errorf("expected Message type in context data")
+	}
+
+	entitiesData, exists := ctx.Get("entities")
+	if !exists {
+		return nil
+	}
+	entities, ok := entitiesData.([]Entity)
+	if !ok {
+		return nil
+	}
+
+	ctx.Set("original_entities", entities)
+	ctx.Set("entities", p.Merge(msg.Text, entities))
+	return nil
+}
+
+// Merge returns entities, found in text, with adjacent entities merged by the rules. The
+// result is sorted by position; entities is not modified.
+func (p *EntityMergerPlugin) Merge(text string, entities []Entity) []Entity {
+	sorted := make([]Entity, len(entities))
+	copy(sorted, entities)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Start != sorted[j].Start {
+			return sorted[i].Start < sorted[j].Start
+		}
+		return sorted[i].End < sorted[j].End
+	})
+
+	merged := make([]Entity, 0, len(sorted))
+	for _, entity := range sorted {
+		if last := len(merged) - 1; last >= 0 {
+			if rule, ok := p.rule(text, merged[last], entity); ok {
+				merged[last] = combine(text, merged[last], entity, rule)
+				continue
+			}
+		}
+		merged = append(merged, entity)
+	}
+	return merged
+}
+
+// rule returns the first rule that merges first with second
+func (p *EntityMergerPlugin) rule(text string, first, second Entity) (MergeRule, bool) {
+	if second.Start < first.End || second.End > len(text) {
+		return MergeRule{}, false
+	}
+	gap := text[first.End:second.Start]
+
+	for _, rule := range p.rules {
+		// An entity this rule already merged can be extended, joining a third name
+		extends := rule.Result != "" && first.Type == rule.Result
+		if (rule.First != first.Type && !extends) || rule.Second != second.Type {
+			continue
+		}
+		maxGap := rule.MaxGap
+		if maxGap <= 0 {
+			maxGap = DefaultMergeGap
+		}
+		if len(gap) <= maxGap && onlyConnectors(gap, rule.Connectors) {
+			return rule, true
+		}
+	}
+	return MergeRule{}, false
+}
+
+// onlyConnectors reports whether gap holds nothing but spaces, punctuation and the
+// connector words
+func onlyConnectors(gap string, connectors []string) bool {
+	words := strings.FieldsFunc(gap, func(r rune) bool {
+		return strings.ContainsRune(" \t\n,.-/", r)
+	})
+	for _, word := range words {
+		allowed := false
+		for _, connector := range connectors {
+			if strings.EqualFold(word, connector) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	return true
+}
+
+// combine returns the entity spanning first and second
+func combine(text string, first, second Entity, rule MergeRule) Entity {
+	entityType := rule.Result
+	if entityType == "" {
+		entityType = first.Type
+	}
+	return Entity{
+		Type:       entityType,
+		Value:      text[first.Start:second.End],
+		Start:      first.Start,
+		End:        second.End,
+		Confidence: min(first.Confidence, second.Confidence),
+	}
+}
@@ -0,0 +1,74 @@
+package chatbot
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+func TestEntityMergerChainsNames(t *testing.T) {
+	text := "I am John Ronald Tolkien"
+	entities := []Entity{
+		{Type: "name", Value: "Tolkien", Start: 17, End: 24, Confidence: 0.6},
+		{Type: "name", Value: "John", Start: 5, End: 9, Confidence: 0.5},
+		{Type: "name", Value: "Ronald", Start: 10, End: 16, Confidence: 0.7},
+	}
+
+	merged := NewEntityMergerPlugin(MergeRule{First: "name", Second: "name", Result: "person"}).Merge(text, entities)
+	want := []Entity{{Type: "person", Value: "John Ronald Tolkien", Start: 5, End: 24, Confidence: 0.5}}
+	if !reflect.DeepEqual(merged, want) {
+		t.Errorf("Merge = %+v, want %+v", merged, want)
+	}
+	if entities[0].Value != "Tolkien" {
+		t.Error("Merge modified its input")
+	}
+}
+
+func TestEntityMergerConnectors(t *testing.T) {
+	text := "see you tomorrow at 3pm or tomorrow then 3pm"
+	entities := []Entity{
+		{Type: "date", Value: "tomorrow", Start: 8, End: 16},
+		{Type: "time", Value: "3pm", Start: 20, End: 23},
+		{Type: "date", Value: "tomorrow", Start: 27, End: 35},
+		{Type: "time", Value: "3pm", Start: 41, End: 44},
+	}
+	merger := NewEntityMergerPlugin(MergeRule{First: "date", Second: "time", Result: "datetime", MaxGap: 6, Connectors: []string{"at"}})
+
+	if got := values(merger.Merge(text, entities)); !reflect.DeepEqual(got, []string{"tomorrow at 3pm", "tomorrow", "3pm"}) {
+		t.Errorf("Merge = %v, want only the gap with a connector merged", got)
+	}
+
+	// The default gap of one character doesn't cover " at "
+	if got := values(NewEntityMergerPlugin(MergeRule{First: "date", Second: "time", Connectors: []string{"at"}}).Merge(text, entities)); len(got) != 4 {
+		t.Errorf("Merge with the default gap = %v, want nothing merged", got)
+	}
+}
+
+func TestEntityMergerExecute(t *testing.T) {
+	ctx := core.NewContext(Message{Text: "Hi, Jane Doe here"})
+	original := []Entity{
+		{Type: "name", Value: "Jane", Start: 4, End: 8},
+		{Type: "name", Value: "Doe", Start: 9, End: 12},
+	}
+	ctx.Set("entities", original)
+
+	if err := NewEntityMergerPlugin().Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if got, _ := core.Value[[]Entity](ctx, "entities"); len(got) != 1 || got[0].Value != "Jane Doe" || got[0].Type != "name" {
+		t.Errorf("entities = %+v, want one name, Jane Doe", got)
+	}
+	if got, _ := core.Value[[]Entity](ctx, "original_entities"); !reflect.DeepEqual(got, original) {
+		t.Errorf("original_entities = %+v, want %+v", got, original)
+	}
+}
+
+// values returns the value of each entity
+func values(entities []Entity) []string {
+	result := make([]string, 0, len(entities))
+	for _, entity := range entities {
+		result = append(result, entity.Value)
+	}
+	return result
+}
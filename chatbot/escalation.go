@@ -0,0 +1,61 @@
+package chatbot
+
+import (
+	"fmt"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// Escalation defaults used for zero values in EscalationConfig
+const (
+	DefaultEscalationThreshold = 0.1
+	DefaultClarificationPrompt = "I'm not sure I understood. Could you tell me a bit more about what you need?"
+)
+
+// EscalationConfig defines when and how the bot asks for clarification
+type EscalationConfig struct {
+	Threshold float64 // Intents with a lower confidence are escalated (0 uses DefaultEscalationThreshold)
+	Prompt    string  // Response text used instead of the generated one ("" uses DefaultClarificationPrompt)
+}
+
+// EscalationPlugin replaces the response to a message whose intent is unknown or has a
+// low confidence with a clarifying question, and sets "escalate" so the application can
+// hand the conversation to a human. Place it after ResponseGeneratorPlugin.
+type EscalationPlugin struct {
+	threshold float64
+	prompt    string
+}
+
+// NewEscalationPlugin creates an escalation plugin with the given configuration
+func NewEscalationPlugin(config EscalationConfig) *EscalationPlugin {
+	if config.Threshold <= 0 {
+		config.Threshold = DefaultEscalationThreshold
+	}
+	if config.Prompt == "" {
+		config.Prompt = DefaultClarificationPrompt
+	}
+
+	return &EscalationPlugin{
+		threshold: config.Threshold,
+		prompt:    config.Prompt,
+	}
+}
+
+// Execute checks the intent confidence and stores the result under "escalate"
+func (p *EscalationPlugin) Execute(ctx *core.Context) error {
+	// Extract response from context
+	response, ok := ctx.GetData().(Response)
+	if !ok {
+		return fmt.Errorf("expected Response type in context data")
+	}
+
+	escalate := response.Intent.Type == "unknown" || response.Intent.Confidence < p.threshold
+	ctx.Set("escalate", escalate)
+
+	if escalate {
+		response.Text = p.prompt
+		ctx.SetData(response)
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package chatbot
+
+import (
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+func TestEscalation(t *testing.T) {
+	plugin := NewEscalationPlugin(EscalationConfig{Threshold: 0.5, Prompt: "Could you rephrase?"})
+	tests := []struct {
+		intent   Intent
+		escalate bool
+	}{
+		{Intent{Type: "greeting", Confidence: 0.8}, false},
+		{Intent{Type: "greeting", Confidence: 0.5}, false},
+		{Intent{Type: "greeting", Confidence: 0.3}, true},
+		{Intent{Type: "unknown", Confidence: 0.9}, true},
+	}
+
+	for _, test := range tests {
+		ctx := core.NewContext(Response{Text: "Hello!", Intent: test.intent})
+		if err := plugin.Execute(ctx); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		escalate, _ := core.Value[bool](ctx, "escalate")
+		text := ctx.GetData().(Response).Text
+		wantText := "Hello!"
+		if test.escalate {
+			wantText = "Could you rephrase?"
+		}
+		if escalate != test.escalate || text != wantText {
+			t.Errorf("intent %+v: escalate = %v, text = %q, want %v, %q", test.intent, escalate, text, test.escalate, wantText)
+		}
+	}
+}
+
+func TestEscalationDefaults(t *testing.T) {
+	plugin := NewEscalationPlugin(EscalationConfig{})
+	ctx := core.NewContext(Response{Text: "Hello!", Intent: Intent{Type: "greeting", Confidence: 0.05}})
+	if err := plugin.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if text := ctx.GetData().(Response).Text; text != DefaultClarificationPrompt {
+		t.Errorf("text = %q, want the default prompt below the default threshold", text)
+	}
+}
@@ -4,10 +4,19 @@ import "time"
 
 // Message represents an input message from a user
 type Message struct {
-	Text      string    `json:"text"`
-	UserID    string    `json:"user_id"`
-	SessionID string    `json:"session_id"`
-	Timestamp time.Time `json:"timestamp"`
+	Text        string       `json:"text"`
+	UserID      string       `json:"user_id"`
+	SessionID   string       `json:"session_id"`
+	Timestamp   time.Time    `json:"timestamp"`
+	Attachments []Attachment `json:"attachments,omitempty"`
+	Sentiment   *float64     `json:"sentiment,omitempty"` // sentiment score kept in the history by ContextManagerPlugin
+}
+
+// Attachment is a file or image sent with a message
+type Attachment struct {
+	Type     string `json:"type"`      // image, file, audio, etc.
+	URL      string `json:"url"`       // where the attachment can be fetched
+	MimeType string `json:"mime_type"` // e.g. image/png
 }
 
 // Intent represents the classification result of a user's message
@@ -18,10 +27,13 @@ type Intent struct {
 
 // Entity represents an extracted piece of information from a message
 type Entity struct {
-	Type  string `json:"type"`  // person, date, location, number, etc.
-	Value string `json:"value"` // the extracted value
-	Start int    `json:"start"` // start position in the text
-	End   int    `json:"end"`   // end position in the text
+	Type       string  `json:"type"`                 // person, date, location, number, etc.
+	Value      string  `json:"value"`                // the extracted value
+	Start      int     `json:"start"`                // start position in the text
+	End        int     `json:"end"`                  // end position in the text
+	Normalized string  `json:"normalized,omitempty"` // canonical form of the value, such as an E.164 phone number
+	Money      *Money  `json:"money,omitempty"`      // amount and currency of a "currency" entity
+	Confidence float64 `json:"confidence"`           // how reliable the pattern that found the entity is (0-1)
 }
 
 // Response represents the bot's response to a user message
@@ -34,7 +46,19 @@ type Response struct {
 
 // ConversationState maintains state across multiple message exchanges
 type ConversationState struct {
-	History    []Message      `json:"history"`
-	UserPrefs  map[string]any `json:"user_prefs"`
-	LastIntent Intent         `json:"last_intent"`
+	History      []Message            `json:"history"`
+	UserPrefs    map[string]any       `json:"user_prefs"`
+	LastIntent   Intent               `json:"last_intent"`
+	FormIntent   string               `json:"form_intent,omitempty"`   // intent whose slots are being filled
+	Slots        map[string]string    `json:"slots,omitempty"`         // slot values collected so far
+	SeenEntities []string             `json:"seen_entities,omitempty"` // keys of entities mentioned so far (EntityMemoryPlugin)
+	Summary      *ConversationSummary `json:"summary,omitempty"`       // condensed messages dropped from History (SummarizerPlugin)
+}
+
+// ConversationSummary condenses the messages a SummarizerPlugin removed from the history
+type ConversationSummary struct {
+	Text     string         `json:"text"`     // short readable summary
+	Messages int            `json:"messages"` // number of messages summarized
+	Intents  map[string]int `json:"intents"`  // number of summarized messages per intent type
+	Entities []string       `json:"entities"` // "value (type)" of the entities mentioned, oldest first
 }
@@ -0,0 +1,27 @@
+package chatbot
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMessageAttachmentsJSON(t *testing.T) {
+	var msg Message
+	body := `{"text": "see file", "attachments": [{"type": "file", "url": "https://example.com/a.pdf", "mime_type": "application/pdf"}]}`
+	if err := json.Unmarshal([]byte(body), &msg); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	want := Attachment{Type: "file", URL: "https://example.com/a.pdf", MimeType: "application/pdf"}
+	if len(msg.Attachments) != 1 || msg.Attachments[0] != want {
+		t.Errorf("attachments = %+v, want [%+v]", msg.Attachments, want)
+	}
+
+	encoded, err := json.Marshal(Message{Text: "plain"})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if strings.Contains(string(encoded), "attachments") {
+		t.Errorf("encoded = %s, want attachments omitted when empty", encoded)
+	}
+}
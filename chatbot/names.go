@@ -0,0 +1,114 @@
+package chatbot
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// DefaultNameStopwords returns capitalized words that commonly start or end phrases that
+// look like person names but are not, such as places, days, months, and greetings
+func DefaultNameStopwords() []string {
+	return []string{
+		"new", "york", "san", "los", "las", "angeles", "united", "states", "kingdom",
+		"north", "south", "east", "west", "saint", "mount", "lake", "city", "street",
+		"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday",
+		"january", "february", "march", "april", "june", "july", "august",
+		"september", "october", "november", "december",
+		"good", "happy", "merry", "thank", "thanks", "dear", "hello", "please", "the",
+	}
+}
+
+// nameWordPattern matches a single alphabetic word
+var nameWordPattern = regexp.MustCompile(`\p{L}+`)
+
+// nameDetector finds two-word person names using an optional gazetteer and stopword list
+type nameDetector struct {
+	firstNames      map[string]bool
+	stopwords       map[string]bool
+	caseInsensitive bool
+}
+
+// newNameDetector creates a name detector from the extractor configuration
+func newNameDetector(config EntityExtractorConfig) *nameDetector {
+	stopwords := config.Stopwords
+	if stopwords == nil {
+		stopwords = DefaultNameStopwords()
+	}
+
+	return &nameDetector{
+		firstNames:      lowercaseSet(config.Gazetteer),
+		stopwords:       lowercaseSet(stopwords),
+		caseInsensitive: config.CaseInsensitive,
+	}
+}
+
+// find returns the names in text as entities
+func (d *nameDetector) find(text string) []Entity {
+	entities := make([]Entity, 0)
+	words := nameWordPattern.FindAllStringIndex(text, -1)
+
+	for i := 0; i+1 < len(words); i++ {
+		first, last := words[i], words[i+1]
+
+		// The two words must be separated by exactly one space
+		if text[first[1]:last[0]] != " " {
+			continue
+		}
+		if !d.isName(text[first[0]:first[1]], text[last[0]:last[1]]) {
+			continue
+		}
+
+		entities = append(entities, Entity{
+			Type:  "name",
+			Value: text[first[0]:last[1]],
+			Start: first[0],
+			End:   last[1],
+		})
+		i++ // The last name can't also start another name
+	}
+
+	return entities
+}
+
+// isName reports whether two adjacent words form a person name
+func (d *nameDetector) isName(first, last string) bool {
+	if len([]rune(first)) < 2 || len([]rune(last)) < 2 {
+		return false
+	}
+	if d.stopwords[strings.ToLower(first)] || d.stopwords[strings.ToLower(last)] {
+		return false
+	}
+
+	if len(d.firstNames) > 0 {
+		if !d.firstNames[strings.ToLower(first)] {
+			return false
+		}
+		if d.caseInsensitive {
+			return true
+		}
+	}
+	return isTitleCase(first) && isTitleCase(last)
+}
+
+// isTitleCase reports whether word is an uppercase letter followed by lowercase letters
+func isTitleCase(word string) bool {
+	for i, r := range word {
+		if i == 0 && !unicode.IsUpper(r) {
+			return false
+		}
+		if i > 0 && !unicode.IsLower(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// lowercaseSet builds a set of the lowercased values
+func lowercaseSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[strings.ToLower(value)] = true
+	}
+	return set
+}
@@ -0,0 +1,67 @@
+package chatbot
+
+import (
+	"reflect"
+	"testing"
+)
+
+// names returns the values of the name entities plugin finds in text.
+func names(plugin *EntityExtractorPlugin, text string) []string {
+	values := make([]string, 0)
+	for _, entity := range plugin.Extract(text) {
+		if entity.Type == "name" {
+			values = append(values, entity.Value)
+		}
+	}
+	return values
+}
+
+func TestNameDetectionStopwords(t *testing.T) {
+	plugin := NewEntityExtractorPluginWithConfig(EntityExtractorConfig{})
+
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"I met John Smith yesterday", []string{"John Smith"}},
+		{"I flew to New York on Monday Morning", []string{}},
+		{"Thanks Alice", []string{}},
+		{"ask Mary Jane Watson", []string{"Mary Jane"}},
+		{"john smith called", []string{}},
+	}
+	for _, tt := range tests {
+		if got := names(plugin, tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("names(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestNameDetectionGazetteer(t *testing.T) {
+	plugin := NewEntityExtractorPluginWithConfig(EntityExtractorConfig{
+		Gazetteer:       []string{"John", "Émile"},
+		CaseInsensitive: true,
+	})
+
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"john smith called", []string{"john smith"}},
+		{"Émile Zola wrote it", []string{"Émile Zola"}},
+		{"Acme Corp called", []string{}},
+		{"john new called", []string{}},
+	}
+	for _, tt := range tests {
+		if got := names(plugin, tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("names(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestNameDetectionWithoutStopwords(t *testing.T) {
+	plugin := NewEntityExtractorPluginWithConfig(EntityExtractorConfig{Stopwords: []string{}})
+
+	if got := names(plugin, "I flew to New York"); !reflect.DeepEqual(got, []string{"New York"}) {
+		t.Errorf("names = %v, want New York with the stopword filter disabled", got)
+	}
+}
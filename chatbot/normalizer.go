@@ -0,0 +1,47 @@
+package chatbot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// NormalizerPlugin cleans up message text before classification by collapsing runs of
+// whitespace (spaces, tabs, newlines) into single spaces and trimming the ends.
+// Place it first in the pipeline: entity offsets computed downstream refer to the normalized text.
+type NormalizerPlugin struct {
+	lowercase bool
+}
+
+// NewNormalizerPlugin creates a new normalizer. When lowercase is set the text is also
+// lowercased, which disables capitalization-based name detection in EntityExtractorPlugin.
+func NewNormalizerPlugin(lowercase bool) *NormalizerPlugin {
+	return &NormalizerPlugin{
+		lowercase: lowercase,
+	}
+}
+
+// Execute normalizes the message text and stores the original under "original_text"
+func (p *NormalizerPlugin) Execute(ctx *core.Context) error {
+	// Extract message from context
+	msg, ok := ctx.GetData().(Message)
+	if !ok {
+		return fmt.Errorf("expected Message type in context data")
+	}
+
+	// Keep the earliest original if another plugin already rewrote the text
+	if _, exists := ctx.Get("original_text"); !exists {
+		ctx.Set("original_text", msg.Text)
+	}
+
+	text := strings.Join(strings.Fields(msg.Text), " ")
+	if p.lowercase {
+		text = strings.ToLower(text)
+	}
+
+	msg.Text = text
+	ctx.SetData(msg)
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package chatbot
+
+import (
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+func TestNormalizer(t *testing.T) {
+	tests := []struct {
+		text      string
+		lowercase bool
+		want      string
+	}{
+		{"  Hello\t\tthere\n\nJohn   Smith  ", false, "Hello there John Smith"},
+		{" Hi　there ", false, "Hi there"}, // Unicode spaces count as whitespace
+		{"Hello THERE", true, "hello there"},
+		{"   ", false, ""},
+	}
+	for _, test := range tests {
+		ctx := core.NewContext(Message{Text: test.text, SessionID: "s1"})
+		if err := NewNormalizerPlugin(test.lowercase).Execute(ctx); err != nil {
+			t.Fatalf("Execute(%q): %v", test.text, err)
+		}
+		msg := ctx.GetData().(Message)
+		if msg.Text != test.want || msg.SessionID != "s1" {
+			t.Errorf("normalized %q = %+v, want text %q", test.text, msg, test.want)
+		}
+		if original, _ := core.Value[string](ctx, "original_text"); original != test.text {
+			t.Errorf("original_text = %q, want %q", original, test.text)
+		}
+	}
+}
+
+func TestNormalizerKeepsEarliestOriginal(t *testing.T) {
+	ctx := core.NewContext(Message{Text: "rewritten  text"})
+	ctx.Set("original_text", "the original")
+	NewNormalizerPlugin(false).Execute(ctx)
+
+	if original, _ := core.Value[string](ctx, "original_text"); original != "the original" {
+		t.Errorf("original_text = %q, want the earlier original kept", original)
+	}
+}
+
+func TestNormalizerEntityOffsets(t *testing.T) {
+	ctx := core.NewContext(Message{Text: "  mail   me at john@example.com  "})
+	pipeline := core.NewPipeline(core.AbortOnError).
+		Use(NewNormalizerPlugin(false)).
+		Use(NewEntityExtractorPlugin())
+	if err := pipeline.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	text := ctx.GetData().(Message).Text
+	entities, _ := core.Value[[]Entity](ctx, "entities")
+	for _, entity := range entities {
+		if text[entity.Start:entity.End] != entity.Value {
+			t.Errorf("entity %+v doesn't match the normalized text %q", entity, text)
+		}
+	}
+}
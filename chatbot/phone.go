@@ -0,0 +1,102 @@
+package chatbot
+
+import (
+	"strings"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// Phone number length limits, in digits. E.164 numbers have at most 15 digits including
+// the country code; national numbers shorter than 7 digits are not dialable, and longer
+// than 10 digits are assumed to include the country code.
+const (
+	minNationalDigits = 7
+	maxNationalDigits = 10
+	maxPhoneDigits    = 15
+)
+
+// PhoneNormalizerPlugin normalizes the phone entities found by EntityExtractorPlugin to
+// E.164 form ("+15551234567") and stores it in each entity's Normalized field.
+// Phone entities that can't be a valid number are removed from the entity list.
+// Place it after EntityExtractorPlugin.
+type PhoneNormalizerPlugin struct {
+	countryCode string
+}
+
+// NewPhoneNormalizerPlugin creates a phone normalizer that assumes defaultCountryCode
+// (such as "1" or "+44") for numbers written without one. With an empty default, only
+// numbers written with a country code are valid.
+func NewPhoneNormalizerPlugin(defaultCountryCode string) *PhoneNormalizerPlugin {
+	return &PhoneNormalizerPlugin{
+		countryCode: digitsOnly(defaultCountryCode),
+	}
+}
+
+// Execute normalizes the phone entities in the "entities" metadata
+func (p *PhoneNormalizerPlugin) Execute(ctx *core.Context) error {
+	entitiesData, exists := ctx.Get("entities")
+	if !exists {
+		return nil
+	}
+	entities, ok := entitiesData.([]Entity)
+	if !ok {
+		return nil
+	}
+
+	normalized := make([]Entity, 0, len(entities))
+	for _, entity := range entities {
+		if entity.Type == "phone" {
+			number, valid := p.Normalize(entity.Value)
+			if !valid {
+				continue
+			}
+			entity.Normalized = number
+		}
+		normalized = append(normalized, entity)
+	}
+
+	ctx.Set("entities", normalized)
+	return nil
+}
+
+// Normalize returns the E.164 form of a phone number and whether it is plausibly valid.
+// Numbers starting with "+" or "00" carry their own country code; other numbers get the
+// default country code unless they are longer than a national number and already start
+// with it.
+func (p *PhoneNormalizerPlugin) Normalize(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	digits := digitsOnly(value)
+
+	international := strings.HasPrefix(value, "+")
+	if !international && strings.HasPrefix(digits, "00") {
+		digits = digits[2:]
+		international = true
+	}
+
+	if !international {
+		if len(digits) < minNationalDigits || p.countryCode == "" {
+			return "", false
+		}
+		if !(len(digits) > maxNationalDigits && strings.HasPrefix(digits, p.countryCode)) {
+			digits = p.countryCode + digits
+		}
+	}
+
+	// A country code is 1-3 digits, so an international number needs at least
+	// one more digit than the shortest national number
+	if len(digits) < minNationalDigits+1 || len(digits) > maxPhoneDigits || digits[0] == '0' {
+		return "", false
+	}
+	return "+" + digits, true
+}
+
+// digitsOnly returns the ASCII digits in value
+func digitsOnly(value string) string {
+	var digits strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	return digits.String()
+}
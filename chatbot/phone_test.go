@@ -0,0 +1,66 @@
+package chatbot
+
+import (
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+func TestPhoneNormalize(t *testing.T) {
+	plugin := NewPhoneNormalizerPlugin("+1")
+	tests := []struct {
+		value string
+		want  string
+		valid bool
+	}{
+		{"(555) 123-4567", "+15551234567", true},
+		{"555.123.4567", "+15551234567", true},
+		{"1 555 123 4567", "+15551234567", true},
+		{"+44 20 7946 0958", "+442079460958", true},
+		{"0044 20 7946 0958", "+442079460958", true},
+		{"123-4567", "+11234567", true},
+		{"12345", "", false},
+		{"+0 555 123 4567", "", false},
+		{"+1234567890123456", "", false},
+	}
+
+	for _, tt := range tests {
+		got, valid := plugin.Normalize(tt.value)
+		if got != tt.want || valid != tt.valid {
+			t.Errorf("Normalize(%q) = %q, %v, want %q, %v", tt.value, got, valid, tt.want, tt.valid)
+		}
+	}
+}
+
+func TestPhoneNormalizeWithoutDefaultCountry(t *testing.T) {
+	plugin := NewPhoneNormalizerPlugin("")
+	if _, valid := plugin.Normalize("555-123-4567"); valid {
+		t.Error("national number accepted without a default country code")
+	}
+	if got, valid := plugin.Normalize("+1 555 123 4567"); !valid || got != "+15551234567" {
+		t.Errorf("Normalize = %q, %v, want +15551234567", got, valid)
+	}
+}
+
+func TestPhoneNormalizerExecute(t *testing.T) {
+	ctx := core.NewContext(Message{Text: "call me"})
+	ctx.Set("entities", []Entity{
+		{Type: "phone", Value: "555-123-4567"},
+		{Type: "email", Value: "a@example.com"},
+		{Type: "phone", Value: "+0 12"},
+	})
+
+	if err := NewPhoneNormalizerPlugin("1").Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	entities, _ := core.Value[[]Entity](ctx, "entities")
+	if len(entities) != 2 {
+		t.Fatalf("entities = %+v, want the invalid phone removed", entities)
+	}
+	if entities[0].Normalized != "+15551234567" {
+		t.Errorf("normalized = %q, want +15551234567", entities[0].Normalized)
+	}
+	if entities[1].Type != "email" || entities[1].Normalized != "" {
+		t.Errorf("entity = %+v, want the email untouched", entities[1])
+	}
+}
@@ -1,28 +1,109 @@
 package chatbot
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
 
 	"github.com/dvictor357/pipeline-plugin-system/core"
 )
 
 // IntentClassifierPlugin analyzes message text to determine user intent using keyword-based classification
 type IntentClassifierPlugin struct {
-	keywords map[string][]string
+	keywords      map[string][]string
+	intentTypes   []string // sorted, so ties resolve the same way on every run
+	minConfidence float64
+	streaming     bool
+	positionDecay func(position float64) float64
+	streamIdle    time.Duration
+	now           func() time.Time
+
+	mu        sync.Mutex
+	streams   map[string]*intentStream
+	lastSweep time.Time
+}
+
+// IntentClassifierConfig defines optional behavior for the intent classifier
+type IntentClassifierConfig struct {
+	// MinConfidence downgrades intents with a lower confidence to "unknown" (0 disables)
+	MinConfidence float64
+
+	// Streaming treats each message as the next chunk of one growing text per session,
+	// such as a voice transcript, and classifies the text received so far. Keyword matches
+	// are kept between chunks so earlier chunks are not rescanned; the result equals
+	// classifying the concatenated chunks at once. Call ResetStream when a transcript ends.
+	Streaming bool
+
+	// StreamIdleTimeout discards the streaming state of sessions that sent no chunk for
+	// this long, so abandoned transcripts don't accumulate. Zero uses
+	// DefaultStreamIdleTimeout.
+	StreamIdleTimeout time.Duration
+
+	// PositionDecay weights each keyword by where it first appears, so "Hi, I have a
+	// complaint" is a stronger greeting than a "hi" buried mid-sentence. It receives the
+	// position relative to the text length, from 0 at the start to 1 at the end, and returns
+	// the keyword's weight in place of 1, such as DefaultPositionDecay. Nil disables
+	// positional weighting. It does not apply to Streaming, where the text keeps growing.
+	PositionDecay func(position float64) float64
+}
+
+// DefaultPositionDecay weights a keyword linearly from 1 at the start of the text down to
+// 0.5 at the end
+func DefaultPositionDecay(position float64) float64 {
+	return 1 - position/2
+}
+
+// DefaultStreamIdleTimeout is how long the intent classifier keeps the streaming state of
+// a session that sends no chunks
+const DefaultStreamIdleTimeout = 10 * time.Minute
+
+// intentStream is the running classification state of one streamed transcript
+type intentStream struct {
+	found    map[string]map[string]bool // intent -> keywords seen so far
+	tail     string                     // end of the text so far, for keywords spanning chunks
+	lastSeen time.Time                  // when the last chunk arrived
 }
 
 // NewIntentClassifierPlugin creates a new intent classifier with predefined keyword patterns
 func NewIntentClassifierPlugin() *IntentClassifierPlugin {
+	return NewIntentClassifierPluginWithConfig(IntentClassifierConfig{})
+}
+
+// NewIntentClassifierPluginWithConfig creates a new intent classifier with predefined keyword patterns
+// and the given configuration
+func NewIntentClassifierPluginWithConfig(config IntentClassifierConfig) *IntentClassifierPlugin {
+	keywords := map[string][]string{
+		"greeting": {"hello", "hi", "hey", "good morning", "good afternoon", "good evening", "greetings"},
+		"farewell": {"bye", "goodbye", "see you", "farewell", "take care", "later"},
+		"question": {"what", "when", "where", "who", "why", "how", "can you", "could you", "would you", "?"},
+		"command":  {"do", "make", "create", "show", "tell", "give", "send", "help"},
+	}
+
+	intentTypes := make([]string, 0, len(keywords))
+	for intentType := range keywords {
+		intentTypes = append(intentTypes, intentType)
+	}
+	sort.Strings(intentTypes)
+
+	streamIdle := config.StreamIdleTimeout
+	if streamIdle <= 0 {
+		streamIdle = DefaultStreamIdleTimeout
+	}
+
 	return &IntentClassifierPlugin{
-		keywords: map[string][]string{
-			"greeting": {"hello", "hi", "hey", "good morning", "good afternoon", "good evening", "greetings"},
-			"farewell": {"bye", "goodbye", "see you", "farewell", "take care", "later"},
-			"question": {"what", "when", "where", "who", "why", "how", "can you", "could you", "would you", "?"},
-			"command":  {"do", "make", "create", "show", "tell", "give", "send", "help"},
-		},
+		keywords:      keywords,
+		intentTypes:   intentTypes,
+		minConfidence: config.MinConfidence,
+		streaming:     config.Streaming,
+		positionDecay: config.PositionDecay,
+		streamIdle:    streamIdle,
+		now:           time.Now,
+		streams:       make(map[string]*intentStream),
 	}
 }
 
@@ -34,65 +115,288 @@ func (p *IntentClassifierPlugin) Execute(ctx *core.Context) error {
 		return fmt.Errorf("expected Message type in context data")
 	}
 
-	text := strings.ToLower(msg.Text)
-
-	// Classify intent based on keyword matching
-	intent := Intent{
-		Type:       "unknown",
-		Confidence: 0.0,
+	var intent Intent
+	if p.streaming {
+		intent = p.classifyMatches(p.streamMatches(msg.SessionID, strings.ToLower(msg.Text)))
+	} else {
+		intent = p.Classify(msg.Text)
 	}
 
-	maxMatches := 0
+	// Store intent in context metadata
+	ctx.Set("intent", intent)
+
+	return nil
+}
+
+// Classify returns the intent of text based on keyword matching. It ignores the Streaming
+// setting, classifying text on its own.
+func (p *IntentClassifierPlugin) Classify(text string) Intent {
+	text = strings.ToLower(text)
+
+	// Count matching keywords per intent, weighted by position if configured
+	matchCounts := make(map[string]float64, len(p.keywords))
 	for intentType, keywords := range p.keywords {
-		matches := 0
 		for _, keyword := range keywords {
-			if strings.Contains(text, keyword) {
-				matches++
+			index := strings.Index(text, keyword)
+			if index < 0 {
+				continue
+			}
+			if p.positionDecay != nil {
+				matchCounts[intentType] += p.positionDecay(float64(index) / float64(len(text)))
+			} else {
+				matchCounts[intentType]++
 			}
 		}
+	}
+
+	return p.classifyMatches(matchCounts)
+}
 
+// classifyMatches picks the intent with the most (weighted) keyword matches
+func (p *IntentClassifierPlugin) classifyMatches(matchCounts map[string]float64) Intent {
+	intent := Intent{
+		Type:       "unknown",
+		Confidence: 0.0,
+	}
+
+	maxMatches := 0.0
+	for _, intentType := range p.intentTypes {
+		matches := matchCounts[intentType]
 		if matches > maxMatches {
 			maxMatches = matches
 			intent.Type = intentType
 			// Calculate confidence based on number of matches
-			intent.Confidence = float64(matches) / float64(len(keywords))
+			intent.Confidence = matches / float64(len(p.keywords[intentType]))
 			if intent.Confidence > 1.0 {
 				intent.Confidence = 1.0
 			}
 		}
 	}
 
-	// If no keywords matched, check for question mark
-	if intent.Type == "unknown" && strings.Contains(text, "?") {
-		intent.Type = "question"
-		intent.Confidence = 0.5
+	// Downgrade weak matches rather than risk a wrong response
+	if intent.Confidence < p.minConfidence {
+		intent.Type = "unknown"
 	}
 
-	// Store intent in context metadata
-	ctx.Set("intent", intent)
+	return intent
+}
 
-	return nil
+// streamMatches adds a lowercased chunk to the session's stream and returns the number of
+// keywords per intent found anywhere in the text streamed so far
+func (p *IntentClassifierPlugin) streamMatches(sessionID, chunk string) map[string]float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	p.sweepStreams(now)
+
+	stream, exists := p.streams[sessionID]
+	if !exists {
+		stream = &intentStream{
+			found: make(map[string]map[string]bool, len(p.keywords)),
+		}
+		p.streams[sessionID] = stream
+	}
+	stream.lastSeen = now
+
+	// Scanning the previous tail with the chunk catches keywords split across chunks
+	window := stream.tail + chunk
+	longest := 0
+	matchCounts := make(map[string]float64, len(p.keywords))
+	for intentType, keywords := range p.keywords {
+		if stream.found[intentType] == nil {
+			stream.found[intentType] = make(map[string]bool)
+		}
+		for _, keyword := range keywords {
+			if len(keyword) > longest {
+				longest = len(keyword)
+			}
+			if !stream.found[intentType][keyword] && strings.Contains(window, keyword) {
+				stream.found[intentType][keyword] = true
+			}
+		}
+		matchCounts[intentType] = float64(len(stream.found[intentType]))
+	}
+
+	// Keep just enough of the end to complete any keyword in the next chunk
+	if keep := longest - 1; len(window) > keep {
+		window = window[len(window)-keep:]
+	}
+	stream.tail = window
+
+	return matchCounts
+}
+
+// sweepStreams discards streams idle for longer than the idle timeout, at most once per
+// timeout. The caller must hold p.mu.
+func (p *IntentClassifierPlugin) sweepStreams(now time.Time) {
+	if now.Sub(p.lastSweep) < p.streamIdle {
+		return
+	}
+	p.lastSweep = now
+
+	for sessionID, stream := range p.streams {
+		if now.Sub(stream.lastSeen) >= p.streamIdle {
+			delete(p.streams, sessionID)
+		}
+	}
+}
+
+// ResetStream discards the streaming state of a session, so its next message starts a new
+// transcript. It has no effect unless the classifier was created with Streaming enabled.
+func (p *IntentClassifierPlugin) ResetStream(sessionID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.streams, sessionID)
 }
 
+// DefaultPatternConfidence is the confidence of entities found by a custom pattern, unless
+// set with WithConfidence
+const DefaultPatternConfidence = 0.5
+
+// DefaultMaxEntities is the most entities extracted from one message, so a crafted message
+// full of number-like tokens can't bloat the context and the response
+const DefaultMaxEntities = 100
+
 // EntityExtractorPlugin identifies and extracts entities from message text using regex patterns
 type EntityExtractorPlugin struct {
-	patterns map[string]*regexp.Regexp
+	mu          sync.RWMutex // guards patterns and custom, replaced on AddPattern
+	patterns    map[string]*regexp.Regexp
+	custom      map[string]bool    // entity types whose pattern was added with AddPattern
+	confidence  map[string]float64 // base confidence of each entity type
+	names       *nameDetector      // replaces the "name" pattern when configured
+	timeout     time.Duration      // time budget of custom patterns; zero disables the guard
+	maxEntities int                // cap on all entities; zero or less disables it
+	maxPerType  map[string]int     // caps on the entities of a type; zero or less disables one
+}
+
+// EntityExtractorConfig configures person name detection in the entity extractor
+type EntityExtractorConfig struct {
+	// Gazetteer lists known first names. When set, a name is only detected if its
+	// first word is in the list.
+	Gazetteer []string
+	// CaseInsensitive also detects names that are not capitalized, such as "john smith".
+	// It only applies to names confirmed by the Gazetteer.
+	CaseInsensitive bool
+	// Stopwords are capitalized words that never form part of a name, such as "New" in
+	// "New York". Nil uses DefaultNameStopwords; an empty slice disables the filter.
+	Stopwords []string
 }
 
 // NewEntityExtractorPlugin creates a new entity extractor with predefined regex patterns
 func NewEntityExtractorPlugin() *EntityExtractorPlugin {
 	return &EntityExtractorPlugin{
-		patterns: map[string]*regexp.Regexp{
-			"date":   regexp.MustCompile(`\b(\d{1,2}[/-]\d{1,2}[/-]\d{2,4}|(?:jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]* \d{1,2}(?:st|nd|rd|th)?(?:,? \d{4})?|today|tomorrow|yesterday)\b`),
-			"number": regexp.MustCompile(`\b\d+(?:\.\d+)?\b`),
-			"email":  regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Z|a-z]{2,}\b`),
-			"phone":  regexp.MustCompile(`\b(?:\+\d{1,3}[-.\s]?)?\(?\d{3}\)?[-.\s]?\d{3}[-.\s]?\d{4}\b`),
-			"name":   regexp.MustCompile(`\b[A-Z][a-z]+ [A-Z][a-z]+\b`),
-		},
+		patterns:    defaultEntityPatterns(),
+		confidence:  DefaultEntityConfidence(),
+		maxEntities: DefaultMaxEntities,
+		maxPerType:  make(map[string]int),
+	}
+}
+
+// NewEntityExtractorPluginWithConfig creates a new entity extractor with predefined regex
+// patterns, detecting person names with the given configuration
+func NewEntityExtractorPluginWithConfig(config EntityExtractorConfig) *EntityExtractorPlugin {
+	return &EntityExtractorPlugin{
+		patterns:    defaultEntityPatterns(),
+		confidence:  DefaultEntityConfidence(),
+		names:       newNameDetector(config),
+		maxEntities: DefaultMaxEntities,
+		maxPerType:  make(map[string]int),
 	}
 }
 
-// Execute identifies entities in the message text and stores them in Context metadata
+// AddPattern adds a custom pattern for an entity type, or replaces the pattern of an
+// existing type. Flags are regexp flags applied to the whole pattern, such as "i" for case
+// insensitive or "is" to also let "." match newlines; they may be empty. It is safe to call
+// while messages are being processed.
+func (p *EntityExtractorPlugin) AddPattern(entityType, expr, flags string) error {
+	if strings.Trim(flags, "imsU") != "" {
+		return fmt.Errorf("entity pattern %q: unsupported flags %q", entityType, flags)
+	}
+	if flags != "" {
+		expr = "(?" + flags + ")" + expr
+	}
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return fmt.Errorf("entity pattern %q: %w", entityType, err)
+	}
+
+	// Copy on write, so extractions in progress keep the patterns they started with
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	patterns := make(map[string]*regexp.Regexp, len(p.patterns)+1)
+	for existing, existingPattern := range p.patterns {
+		patterns[existing] = existingPattern
+	}
+	patterns[entityType] = pattern
+	custom := map[string]bool{entityType: true}
+	for existing := range p.custom {
+		custom[existing] = true
+	}
+	p.patterns, p.custom = patterns, custom
+	return nil
+}
+
+// WithConfidence sets the confidence of the entities of a type, such as a custom pattern's
+// type or "name" when a gazetteer makes name detection more reliable
+func (p *EntityExtractorPlugin) WithConfidence(entityType string, confidence float64) *EntityExtractorPlugin {
+	p.confidence[entityType] = confidence
+	return p
+}
+
+// WithMaxEntities sets the most entities extracted from one message. Beyond it, the
+// entities that come first in the text are kept. Zero or less disables the cap.
+func (p *EntityExtractorPlugin) WithMaxEntities(limit int) *EntityExtractorPlugin {
+	p.maxEntities = limit
+	return p
+}
+
+// WithMaxEntitiesPerType sets the most entities of one type extracted from one message,
+// such as a low cap on "number". Beyond it, the first matches in the text are kept. As
+// with WithMaxEntities, zero or less disables the type's cap, leaving only the total cap.
+func (p *EntityExtractorPlugin) WithMaxEntitiesPerType(entityType string, limit int) *EntityExtractorPlugin {
+	p.maxPerType[entityType] = limit
+	return p
+}
+
+// WithPatternTimeout sets how long each custom pattern may run on one message. A pattern
+// that runs longer, as a costly custom pattern can on adversarial input, is abandoned and
+// its entities are skipped. Built-in patterns always run to completion. Zero or less, the
+// default, disables the guard.
+func (p *EntityExtractorPlugin) WithPatternTimeout(timeout time.Duration) *EntityExtractorPlugin {
+	p.timeout = timeout
+	return p
+}
+
+// defaultEntityPatterns returns the predefined regex pattern for each entity type
+func defaultEntityPatterns() map[string]*regexp.Regexp {
+	return map[string]*regexp.Regexp{
+		"date":     regexp.MustCompile(`\b(\d{1,2}[/-]\d{1,2}[/-]\d{2,4}|(?:jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]* \d{1,2}(?:st|nd|rd|th)?(?:,? \d{4})?|today|tomorrow|yesterday)\b`),
+		"number":   regexp.MustCompile(`\b\d+(?:\.\d+)?\b`),
+		"email":    regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Z|a-z]{2,}\b`),
+		"phone":    regexp.MustCompile(`\b(?:\+\d{1,3}[-.\s]?)?\(?\d{3}\)?[-.\s]?\d{3}[-.\s]?\d{4}\b`),
+		"name":     regexp.MustCompile(`\b[A-Z][a-z]+ [A-Z][a-z]+\b`),
+		"currency": currencyPattern,
+	}
+}
+
+// DefaultEntityConfidence returns the base confidence of each built-in entity type. Strict
+// patterns, like email, score high; the capitalization heuristic for names scores low.
+func DefaultEntityConfidence() map[string]float64 {
+	return map[string]float64{
+		"email":    0.95,
+		"phone":    0.85,
+		"currency": 0.9,
+		"date":     0.8,
+		"number":   0.7,
+		"name":     0.4,
+	}
+}
+
+// Execute identifies entities in the message text and stores them in Context metadata,
+// ordered by position and then by type. "entities_truncated" reports whether entities were dropped for exceeding a cap.
+// Custom patterns abandoned for running over the time budget, and caps exceeded, are listed in
+// "entity_warnings".
 func (p *EntityExtractorPlugin) Execute(ctx *core.Context) error {
 	// Extract message from context
 	msg, ok := ctx.GetData().(Message)
@@ -100,43 +404,254 @@ func (p *EntityExtractorPlugin) Execute(ctx *core.Context) error {
 		return fmt.Errorf("expected Message type in context data")
 	}
 
+	// Store entities in context metadata
+	entities, warnings, truncated := p.extract(msg.Text)
+	ctx.Set("entities", entities)
+	ctx.Set("entities_truncated", truncated)
+	if len(warnings) > 0 {
+		ctx.Set("entity_warnings", warnings)
+	}
+
+	return nil
+}
+
+// Extract returns the entities found in text, ordered by position and then by type
+func (p *EntityExtractorPlugin) Extract(text string) []Entity {
+	entities, _, _ := p.extract(text)
+	return entities
+}
+
+// extract returns the entities found in text, a warning for each pattern abandoned for
+// running over the time budget and each cap exceeded, and whether entities were dropped
+// for exceeding a cap
+func (p *EntityExtractorPlugin) extract(text string) ([]Entity, []string, bool) {
 	entities := make([]Entity, 0)
+	var warnings []string
+	truncated := false
+
+	p.mu.RLock()
+	patterns, custom := p.patterns, p.custom
+	p.mu.RUnlock()
 
 	// Extract entities using regex patterns
-	for entityType, pattern := range p.patterns {
-		matches := pattern.FindAllStringIndex(msg.Text, -1)
+	for entityType, pattern := range patterns {
+		confidence, ok := p.confidence[entityType]
+		if !ok {
+			confidence = DefaultPatternConfidence
+		}
+
+		// Finding one match more than the cap shows whether it was exceeded, without
+		// collecting every match of a crafted message
+		limit := p.typeLimit(entityType)
+
+		if entityType == "name" && p.names != nil {
+			names := p.names.find(text)
+			if limit >= 0 && len(names) > limit {
+				names = names[:limit]
+				warnings = append(warnings, fmt.Sprintf("entity type %q truncated to %d", entityType, limit))
+				truncated = true
+			}
+			for _, entity := range names {
+				entity.Confidence = confidence
+				entities = append(entities, entity)
+			}
+			continue
+		}
+
+		n := -1
+		if limit >= 0 {
+			n = limit + 1
+		}
+		timeout := time.Duration(0)
+		if custom[entityType] {
+			timeout = p.timeout
+		}
+		matches, ok := matchWithin(pattern, text, n, timeout)
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("entity pattern %q exceeded %s and was skipped", entityType, p.timeout))
+			continue
+		}
+		if limit >= 0 && len(matches) > limit {
+			matches = matches[:limit]
+			warnings = append(warnings, fmt.Sprintf("entity type %q truncated to %d", entityType, limit))
+			truncated = true
+		}
 		for _, match := range matches {
 			entity := Entity{
-				Type:  entityType,
-				Value: msg.Text[match[0]:match[1]],
-				Start: match[0],
-				End:   match[1],
+				Type:       entityType,
+				Value:      text[match[0]:match[1]],
+				Start:      match[0],
+				End:        match[1],
+				Confidence: confidence,
+			}
+			if entityType == "currency" {
+				if money, ok := parseMoney(entity.Value); ok {
+					entity.Money = &money
+					entity.Normalized = money.String()
+				}
 			}
 			entities = append(entities, entity)
 		}
 	}
 
-	// Store entities in context metadata
-	ctx.Set("entities", entities)
+	// Pattern order is random, so order the entities by position, and by type for
+	// entities at the same position, to keep the result the same on every run
+	sort.Slice(entities, func(i, j int) bool {
+		if entities[i].Start != entities[j].Start {
+			return entities[i].Start < entities[j].Start
+		}
+		return entities[i].Type < entities[j].Type
+	})
 
-	return nil
+	// Keep the entities that come first in the text
+	if p.maxEntities > 0 && len(entities) > p.maxEntities {
+		entities = entities[:p.maxEntities]
+		warnings = append(warnings, fmt.Sprintf("entities truncated to %d", p.maxEntities))
+		truncated = true
+	}
+
+	return entities, warnings, truncated
+}
+
+// typeLimit returns the most entities of entityType to keep, or -1 for no limit
+func (p *EntityExtractorPlugin) typeLimit(entityType string) int {
+	limit := -1
+	if p.maxEntities > 0 {
+		limit = p.maxEntities
+	}
+	if typeMax, ok := p.maxPerType[entityType]; ok && typeMax > 0 && (limit < 0 || typeMax < limit) {
+		limit = typeMax
+	}
+	return limit
+}
+
+// matchWithin runs pattern on text within timeout, returning at most n matches (all for a
+// negative n) and reporting false if it ran over. A non-positive timeout runs the pattern
+// directly. An abandoned match can't be interrupted, so it finishes in the background and
+// is discarded.
+func matchWithin(pattern *regexp.Regexp, text string, n int, timeout time.Duration) ([][]int, bool) {
+	if timeout <= 0 {
+		return pattern.FindAllStringIndex(text, n), true
+	}
+
+	// Buffered so the goroutine can exit even after the match was abandoned
+	result := make(chan [][]int, 1)
+	go func() {
+		result <- pattern.FindAllStringIndex(text, n)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case matches := <-result:
+		return matches, true
+	case <-timer.C:
+		return nil, false
+	}
 }
 
-// ContextManagerPlugin maintains conversation state across multiple message exchanges
+// DefaultTrendWindow is the number of recent scored messages the sentiment trend covers
+const DefaultTrendWindow = 5
+
+// ContextManagerPlugin maintains conversation state across multiple message exchanges.
+// With a trend window, it requires a sentiment plugin to run before it and set
+// "sentiment_score"; the score is kept with the message in the history, and
+// "sentiment_trend" is set to the change in sentiment per message over the recent scored
+// messages. A negative trend means the user is growing more negative.
 type ContextManagerPlugin struct {
 	maxHistorySize int
+	maxAge         time.Duration
+	trendWindow    int // zero disables the sentiment trend
+	store          ConversationStore
+	prefs          UserPrefsStore
+	now            func() time.Time
+
+	prefsMu sync.Mutex // serializes SetUserPref's read-modify-write
+}
+
+// ContextManagerOptions configures a ContextManagerPlugin
+type ContextManagerOptions struct {
+	MaxHistorySize int               // Maximum number of messages kept; zero or less disables the count limit
+	MaxAge         time.Duration     // Maximum message age kept; zero disables the age limit
+	TrendWindow    int               // Recent scored messages the sentiment trend covers; zero disables the trend, and 1 or less uses DefaultTrendWindow
+	Store          ConversationStore // Conversation persistence (default in-memory)
+	PrefsStore     UserPrefsStore    // User preference persistence (default Store if it is a UserPrefsStore, else in-memory)
+	Now            func() time.Time  // Clock used for age trimming (default time.Now)
 }
 
 // NewContextManagerPlugin creates a new context manager with a maximum history size
+// and an in-memory conversation store
 func NewContextManagerPlugin(maxHistorySize int) *ContextManagerPlugin {
+	return NewContextManagerPluginWithStore(maxHistorySize, NewMemoryConversationStore())
+}
+
+// NewContextManagerPluginWithStore creates a new context manager that persists conversation
+// state in the given store
+func NewContextManagerPluginWithStore(maxHistorySize int, store ConversationStore) *ContextManagerPlugin {
 	if maxHistorySize <= 0 {
 		maxHistorySize = 10
 	}
+	return NewContextManagerPluginWithOptions(ContextManagerOptions{
+		MaxHistorySize: maxHistorySize,
+		Store:          store,
+	})
+}
+
+// NewContextManagerPluginWithAge creates a new context manager that drops messages older
+// than maxAge, in addition to the count limit. A maxHistorySize of zero or less keeps
+// messages based on age alone.
+func NewContextManagerPluginWithAge(maxHistorySize int, maxAge time.Duration) *ContextManagerPlugin {
+	return NewContextManagerPluginWithOptions(ContextManagerOptions{
+		MaxHistorySize: maxHistorySize,
+		MaxAge:         maxAge,
+	})
+}
+
+// NewContextManagerPluginWithOptions creates a new context manager from the given options
+func NewContextManagerPluginWithOptions(opts ContextManagerOptions) *ContextManagerPlugin {
+	if opts.Store == nil {
+		opts.Store = NewMemoryConversationStore()
+	}
+	if opts.PrefsStore == nil {
+		if prefs, ok := opts.Store.(UserPrefsStore); ok {
+			opts.PrefsStore = prefs
+		} else {
+			opts.PrefsStore = NewMemoryConversationStore()
+		}
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	if opts.TrendWindow != 0 && opts.TrendWindow < 2 {
+		opts.TrendWindow = DefaultTrendWindow
+	}
 	return &ContextManagerPlugin{
-		maxHistorySize: maxHistorySize,
+		maxHistorySize: opts.MaxHistorySize,
+		maxAge:         opts.MaxAge,
+		trendWindow:    opts.TrendWindow,
+		store:          opts.Store,
+		prefs:          opts.PrefsStore,
+		now:            opts.Now,
 	}
 }
 
+// ContextManagerConfig is the JSON configuration accepted by ContextManagerPluginFactory
+type ContextManagerConfig struct {
+	MaxHistorySize int `json:"maxHistorySize"`
+}
+
+// ContextManagerPluginFactory creates a context manager from a JSON configuration block.
+// It can be registered with core.Registry.RegisterFactory; a nil config uses the defaults.
+func ContextManagerPluginFactory(config json.RawMessage) (core.Plugin, error) {
+	var cfg ContextManagerConfig
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, &cfg); err != nil {
+			return nil, fmt.Errorf("invalid context manager config: %w", err)
+		}
+	}
+	return NewContextManagerPlugin(cfg.MaxHistorySize), nil
+}
+
 // Execute retrieves and updates conversation history, limiting it to the last N messages
 func (p *ContextManagerPlugin) Execute(ctx *core.Context) error {
 	// Extract message from context
@@ -146,20 +661,13 @@ func (p *ContextManagerPlugin) Execute(ctx *core.Context) error {
 	}
 
 	// Retrieve or initialize conversation state
-	var convState ConversationState
 	stateKey := fmt.Sprintf("conversation:%s", msg.SessionID)
 
-	if stateData, exists := ctx.GetState(stateKey); exists {
-		if state, ok := stateData.(ConversationState); ok {
-			convState = state
-		} else {
-			// Initialize if type assertion fails
-			convState = ConversationState{
-				History:   make([]Message, 0),
-				UserPrefs: make(map[string]any),
-			}
-		}
-	} else {
+	convState, exists, err := p.store.Load(msg.SessionID)
+	if err != nil {
+		return fmt.Errorf("failed to load conversation %q: %w", msg.SessionID, err)
+	}
+	if !exists {
 		// Initialize new conversation state
 		convState = ConversationState{
 			History:   make([]Message, 0),
@@ -167,11 +675,23 @@ func (p *ContextManagerPlugin) Execute(ctx *core.Context) error {
 		}
 	}
 
+	// Keep the message's sentiment, if scored, for the trend
+	if p.trendWindow > 0 {
+		if score, ok := core.Value[float64](ctx, "sentiment_score"); ok {
+			msg.Sentiment = &score
+		}
+	}
+
 	// Append current message to history
 	convState.History = append(convState.History, msg)
 
+	// Drop messages older than the age window
+	if p.maxAge > 0 {
+		convState.History = p.trimByAge(convState.History)
+	}
+
 	// Limit history to last N messages
-	if len(convState.History) > p.maxHistorySize {
+	if p.maxHistorySize > 0 && len(convState.History) > p.maxHistorySize {
 		convState.History = convState.History[len(convState.History)-p.maxHistorySize:]
 	}
 
@@ -182,47 +702,218 @@ func (p *ContextManagerPlugin) Execute(ctx *core.Context) error {
 		}
 	}
 
+	// Merge preferences that follow the user across sessions
+	if msg.UserID != "" {
+		prefs, err := p.UserPrefs(msg.UserID)
+		if err != nil {
+			return err
+		}
+		if convState.UserPrefs == nil {
+			convState.UserPrefs = make(map[string]any)
+		}
+		for key, value := range prefs {
+			convState.UserPrefs[key] = value
+		}
+		ctx.SetState(userPrefsKey(msg.UserID), prefs)
+		ctx.Set("user_prefs", prefs)
+	}
+
+	// Persist updated conversation state
+	if err := p.store.Save(msg.SessionID, convState); err != nil {
+		return fmt.Errorf("failed to save conversation %q: %w", msg.SessionID, err)
+	}
+
 	// Store updated conversation state
 	ctx.SetState(stateKey, convState)
 	ctx.Set("conversation_state", convState)
+	if trend, ok := p.sentimentTrend(convState.History); ok {
+		ctx.Set("sentiment_trend", trend)
+	}
 
 	return nil
 }
 
+// sentimentTrend returns the least-squares slope of the sentiment of the last scored
+// messages in history, in sentiment per message. It needs at least two scored messages.
+func (p *ContextManagerPlugin) sentimentTrend(history []Message) (float64, bool) {
+	scores := make([]float64, 0, p.trendWindow)
+	for i := len(history) - 1; i >= 0 && len(scores) < p.trendWindow; i-- {
+		if history[i].Sentiment != nil {
+			scores = append(scores, *history[i].Sentiment)
+		}
+	}
+	if len(scores) < 2 {
+		return 0, false
+	}
+
+	// Scores were collected newest first; x counts messages from the oldest
+	n := float64(len(scores))
+	var sumX, sumY, sumXY, sumXX float64
+	for i, score := range scores {
+		x := float64(len(scores) - 1 - i)
+		sumX += x
+		sumY += score
+		sumXY += x * score
+		sumXX += x * x
+	}
+	return (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX), true
+}
+
+// Requires returns the metadata keys ContextManagerPlugin reads: "sentiment_score" when
+// the sentiment trend is enabled
+func (p *ContextManagerPlugin) Requires() []string {
+	if p.trendWindow > 0 {
+		return []string{"sentiment_score"}
+	}
+	return nil
+}
+
+// Provides returns the metadata keys ContextManagerPlugin sets
+func (p *ContextManagerPlugin) Provides() []string {
+	keys := []string{"conversation_state", "user_prefs"}
+	if p.trendWindow > 0 {
+		keys = append(keys, "sentiment_trend")
+	}
+	return keys
+}
+
+// SetUserPref stores a preference for a user. Preferences are kept in the UserPrefsStore,
+// separate from any session, so they apply to every session of that user.
+func (p *ContextManagerPlugin) SetUserPref(userID, key string, value any) error {
+	p.prefsMu.Lock()
+	defer p.prefsMu.Unlock()
+
+	prefs, err := p.UserPrefs(userID)
+	if err != nil {
+		return err
+	}
+	prefs[key] = value
+
+	if err := p.prefs.SaveUserPrefs(userID, prefs); err != nil {
+		return fmt.Errorf("failed to save preferences for user %q: %w", userID, err)
+	}
+	return nil
+}
+
+// UserPrefs returns the preferences stored for a user, or an empty map if there are none
+func (p *ContextManagerPlugin) UserPrefs(userID string) (map[string]any, error) {
+	stored, err := p.prefs.LoadUserPrefs(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load preferences for user %q: %w", userID, err)
+	}
+
+	prefs := make(map[string]any, len(stored))
+	for key, value := range stored {
+		prefs[key] = value
+	}
+	return prefs, nil
+}
+
+// HealthCheck reports the health of the conversation and preference stores
+func (p *ContextManagerPlugin) HealthCheck() error {
+	if err := storeHealth(p.store); err != nil {
+		return err
+	}
+	if checker, ok := p.prefs.(core.HealthChecker); ok {
+		return checker.HealthCheck()
+	}
+	return nil
+}
+
+// userPrefsKey returns the state key for a user's preferences
+func userPrefsKey(userID string) string {
+	return fmt.Sprintf("user:%s", userID)
+}
+
+// trimByAge removes messages whose timestamp is before now - maxAge.
+// Messages without a timestamp are kept since their age is unknown.
+func (p *ContextManagerPlugin) trimByAge(history []Message) []Message {
+	cutoff := p.now().Add(-p.maxAge)
+	kept := make([]Message, 0, len(history))
+	for _, msg := range history {
+		if msg.Timestamp.IsZero() || !msg.Timestamp.Before(cutoff) {
+			kept = append(kept, msg)
+		}
+	}
+	return kept
+}
+
+// DefaultLocale is the locale of the predefined response templates
+const DefaultLocale = "en"
+
 // ResponseGeneratorPlugin creates appropriate responses based on intent and entities
 type ResponseGeneratorPlugin struct {
-	templates map[string][]string
+	templates       map[string]map[string][]string // locale -> intent -> templates
+	fallbackLocale  string
+	fallbackIntents []string // tried in order when an intent has no templates
+	strict          bool     // no implicit "unknown" fallback
 }
 
-// NewResponseGeneratorPlugin creates a new response generator with predefined templates
+// NewResponseGeneratorPlugin creates a new response generator with predefined English templates
 func NewResponseGeneratorPlugin() *ResponseGeneratorPlugin {
+	return NewLocalizedResponseGeneratorPlugin(map[string]map[string][]string{
+		DefaultLocale: defaultTemplates(),
+	}, DefaultLocale)
+}
+
+// NewLocalizedResponseGeneratorPlugin creates a response generator with templates per locale.
+// The locale is selected from the "language" context metadata value; a regional locale such
+// as "es-MX" falls back to "es", and unknown locales use fallbackLocale.
+func NewLocalizedResponseGeneratorPlugin(locales map[string]map[string][]string, fallbackLocale string) *ResponseGeneratorPlugin {
+	templates := make(map[string]map[string][]string, len(locales))
+	for locale, intents := range locales {
+		templates[normalizeLocale(locale)] = intents
+	}
 	return &ResponseGeneratorPlugin{
-		templates: map[string][]string{
-			"greeting": {
-				"Hello! How can I help you today?",
-				"Hi there! What can I do for you?",
-				"Hey! Nice to see you. What's on your mind?",
-			},
-			"farewell": {
-				"Goodbye! Have a great day!",
-				"See you later! Take care!",
-				"Bye! Feel free to come back anytime!",
-			},
-			"question": {
-				"That's a great question. Let me help you with that.",
-				"I understand you're asking about something. Here's what I know.",
-				"Good question! Let me provide you with some information.",
-			},
-			"command": {
-				"I'll help you with that right away.",
-				"Sure, I can do that for you.",
-				"Consider it done!",
-			},
-			"unknown": {
-				"I'm not sure I understand. Could you rephrase that?",
-				"Hmm, I didn't quite get that. Can you tell me more?",
-				"I'm still learning. Could you explain that differently?",
-			},
+		templates:      templates,
+		fallbackLocale: normalizeLocale(fallbackLocale),
+	}
+}
+
+// WithFallbackIntents sets the intent types whose templates are tried, in order, when the
+// message's intent has none, such as a custom intent without templates, and returns the
+// plugin for method chaining. The "unknown" templates are tried last unless strict.
+func (p *ResponseGeneratorPlugin) WithFallbackIntents(intentTypes ...string) *ResponseGeneratorPlugin {
+	p.fallbackIntents = intentTypes
+	return p
+}
+
+// WithStrict enables or disables strict mode and returns the plugin for method chaining.
+// In strict mode an intent without templates is answered from the fallback intents only,
+// and Execute returns an error if none of them has templates either, instead of silently
+// answering with the "unknown" templates and hiding a missing template.
+func (p *ResponseGeneratorPlugin) WithStrict(strict bool) *ResponseGeneratorPlugin {
+	p.strict = strict
+	return p
+}
+
+// defaultTemplates returns the predefined English templates for each intent
+func defaultTemplates() map[string][]string {
+	return map[string][]string{
+		"greeting": {
+			"Hello! How can I help you today?",
+			"Hi there! What can I do for you?",
+			"Hey! Nice to see you. What's on your mind?",
+		},
+		"farewell": {
+			"Goodbye! Have a great day!",
+			"See you later! Take care!",
+			"Bye! Feel free to come back anytime!",
+		},
+		"question": {
+			"That's a great question. Let me help you with that.",
+			"I understand you're asking about something. Here's what I know.",
+			"Good question! Let me provide you with some information.",
+		},
+		"command": {
+			"I'll help you with that right away.",
+			"Sure, I can do that for you.",
+			"Consider it done!",
+		},
+		"unknown": {
+			"I'm not sure I understand. Could you rephrase that?",
+			"Hmm, I didn't quite get that. Can you tell me more?",
+			"I'm still learning. Could you explain that differently?",
 		},
 	}
 }
@@ -249,10 +940,16 @@ func (p *ResponseGeneratorPlugin) Execute(ctx *core.Context) error {
 		}
 	}
 
-	// Select template based on intent
-	templates, exists := p.templates[intent.Type]
-	if !exists {
-		templates = p.templates["unknown"]
+	// Select template based on locale and intent
+	locale := ""
+	if languageData, exists := ctx.Get("language"); exists {
+		if language, ok := languageData.(string); ok {
+			locale = language
+		}
+	}
+	templates, ok := p.selectTemplates(locale, intent.Type)
+	if !ok {
+		return fmt.Errorf("no response template for intent %q", intent.Type)
 	}
 
 	// Select a template (simple: use first one, could be randomized)
@@ -273,8 +970,13 @@ func (p *ResponseGeneratorPlugin) Execute(ctx *core.Context) error {
 	// Check conversation history for context-aware responses
 	if convStateData, exists := ctx.Get("conversation_state"); exists {
 		if convState, ok := convStateData.(ConversationState); ok {
-			if len(convState.History) > 1 {
-				responseText += fmt.Sprintf(" (This is message #%d in our conversation)", len(convState.History))
+			// Messages condensed by SummarizerPlugin still count
+			messageCount := len(convState.History)
+			if convState.Summary != nil {
+				messageCount += convState.Summary.Messages
+			}
+			if messageCount > 1 {
+				responseText += fmt.Sprintf(" (This is message #%d in our conversation)", messageCount)
 			}
 		}
 	}
@@ -298,20 +1000,72 @@ type PersonalityConfig struct {
 	Name         string
 	Emojis       bool
 	Casual       bool
-	Enthusiastic bool
+	Enthusiastic bool // Ends sentences with "!" instead of "."
 	Prefix       string
 	Suffix       string
+	MaxLength    int               // Maximum response length in runes, including the ellipsis (0 means unlimited)
+	EmojiMap     map[string]string // Intent type to emoji used when Emojis is set (nil uses DefaultEmojiMap)
+	Rules        []TransformRule   // Applied in order after Casual and Enthusiastic, before emojis and Suffix
 }
 
+// TransformRule is a declarative response edit. A rule with a Pattern replaces its matches,
+// expanding $1-style references in Replace; otherwise every occurrence of Find is replaced.
+// A pattern such as `^(.*)$` with Replace "<<$1>>" wraps the whole text
+type TransformRule struct {
+	Find    string
+	Pattern *regexp.Regexp
+	Replace string
+}
+
+// apply returns text with the rule applied
+func (r TransformRule) apply(text string) string {
+	if r.Pattern != nil {
+		return r.Pattern.ReplaceAllString(text, r.Replace)
+	}
+	if r.Find == "" {
+		return text
+	}
+	return strings.ReplaceAll(text, r.Find, r.Replace)
+}
+
+// DefaultEmojiMap returns the emoji appended for each intent type when no custom map is configured
+func DefaultEmojiMap() map[string]string {
+	return map[string]string{
+		"greeting": "👋",
+		"farewell": "👋",
+		"question": "🤔",
+		"command":  "✅",
+	}
+}
+
+// defaultEmojiMap is the shared read-only default used by PersonalityFilterPlugin
+var defaultEmojiMap = DefaultEmojiMap()
+
 // PersonalityFilterPlugin applies tone and style transformations to responses
 type PersonalityFilterPlugin struct {
-	config PersonalityConfig
+	config   PersonalityConfig
+	personas map[string]PersonalityConfig
 }
 
 // NewPersonalityFilterPlugin creates a new personality filter with the given configuration
 func NewPersonalityFilterPlugin(config PersonalityConfig) *PersonalityFilterPlugin {
 	return &PersonalityFilterPlugin{
-		config: config,
+		config:   config,
+		personas: make(map[string]PersonalityConfig),
+	}
+}
+
+// NewMultiPersonalityFilterPlugin creates a personality filter that selects a persona per request
+// from the "persona" context metadata value. Requests without a known persona use defaultPersona;
+// if defaultPersona is not in personas, such responses are left untransformed.
+func NewMultiPersonalityFilterPlugin(personas map[string]PersonalityConfig, defaultPersona string) *PersonalityFilterPlugin {
+	named := make(map[string]PersonalityConfig, len(personas))
+	for name, config := range personas {
+		named[name] = config
+	}
+	return &PersonalityFilterPlugin{
+		config:   named[defaultPersona],
+		personas: named,
 	}
 }
 
@@ -323,16 +1077,26 @@ func (p *PersonalityFilterPlugin) Execute(ctx *core.Context) error {
 		return fmt.Errorf("expected Response type in context data")
 	}
 
+	// Select the persona requested upstream, falling back to the default
+	config := p.config
+	if personaData, exists := ctx.Get("persona"); exists {
+		if persona, ok := personaData.(string); ok {
+			if personaConfig, ok := p.personas[persona]; ok {
+				config = personaConfig
+			}
+		}
+	}
+
 	// Apply personality transformations
 	text := response.Text
 
 	// Add prefix if configured
-	if p.config.Prefix != "" {
-		text = p.config.Prefix + " " + text
+	if config.Prefix != "" {
+		text = config.Prefix + " " + text
 	}
 
 	// Make casual if configured
-	if p.config.Casual {
+	if config.Casual {
 		text = strings.ReplaceAll(text, "Hello!", "Hey!")
 		text = strings.ReplaceAll(text, "Goodbye!", "Bye!")
 		text = strings.ReplaceAll(text, "I will", "I'll")
@@ -340,31 +1104,35 @@ func (p *PersonalityFilterPlugin) Execute(ctx *core.Context) error {
 	}
 
 	// Add enthusiasm if configured
-	if p.config.Enthusiastic {
-		// Add exclamation marks for emphasis
-		text = strings.ReplaceAll(text, ".", "!")
-		// But don't double up
-		text = strings.ReplaceAll(text, "!!", "!")
+	if config.Enthusiastic {
+		text = exclaim(text)
+	}
+
+	// Apply custom rules in order, each seeing the previous rule's output
+	for _, rule := range config.Rules {
+		text = rule.apply(text)
 	}
 
 	// Add emojis if configured
-	if p.config.Emojis {
+	if config.Emojis {
+		emojiMap := config.EmojiMap
+		if emojiMap == nil {
+			emojiMap = defaultEmojiMap
+		}
 		// Add emojis based on intent
-		switch response.Intent.Type {
-		case "greeting":
-			text += " 👋"
-		case "farewell":
-			text += " 👋"
-		case "question":
-			text += " 🤔"
-		case "command":
-			text += " ✅"
+		if emoji, ok := emojiMap[response.Intent.Type]; ok && emoji != "" {
+			text += " " + emoji
 		}
 	}
 
 	// Add suffix if configured
-	if p.config.Suffix != "" {
-		text = text + " " + p.config.Suffix
+	if config.Suffix != "" {
+		text = text + " " + config.Suffix
+	}
+
+	// Truncate after all other transformations
+	if config.MaxLength > 0 {
+		text = truncateRunes(text, config.MaxLength)
 	}
 
 	// Update response with transformed text
@@ -373,3 +1141,95 @@ func (p *PersonalityFilterPlugin) Execute(ctx *core.Context) error {
 
 	return nil
 }
+
+// exclaim replaces sentence-final periods with exclamation marks and collapses runs of
+// exclamation marks, leaving abbreviations ("e.g."), decimals and ellipses alone
+func exclaim(text string) string {
+	runes := []rune(text)
+	var out strings.Builder
+	tokenStart := 0
+	var last rune
+
+	for i, r := range runes {
+		if unicode.IsSpace(r) {
+			tokenStart = i + 1
+		}
+		if r == '.' && isSentenceEnd(runes, tokenStart, i) {
+			r = '!'
+		}
+
+		// Don't double up
+		if r == '!' && last == '!' {
+			continue
+		}
+		out.WriteRune(r)
+		last = r
+	}
+
+	return out.String()
+}
+
+// isSentenceEnd reports whether the period at runes[i] ends a sentence: it must end the
+// text or be followed by whitespace and a capital letter or digit, and must not be part of
+// an ellipsis or an abbreviation with inner periods. tokenStart is where the word holding
+// the period begins.
+func isSentenceEnd(runes []rune, tokenStart, i int) bool {
+	if i > 0 && runes[i-1] == '.' {
+		return false
+	}
+	for j := tokenStart; j+1 < i; j++ {
+		if runes[j] == '.' && unicode.IsLetter(runes[j+1]) {
+			return false
+		}
+	}
+
+	next := i + 1
+	if next < len(runes) && !unicode.IsSpace(runes[next]) {
+		return false
+	}
+	for next < len(runes) && unicode.IsSpace(runes[next]) {
+		next++
+	}
+	return next == len(runes) || unicode.IsUpper(runes[next]) || unicode.IsDigit(runes[next])
+}
+
+// truncateRunes shortens text to at most maxLength runes, replacing the tail with an ellipsis.
+// Cutting on rune boundaries keeps multibyte characters intact.
+func truncateRunes(text string, maxLength int) string {
+	runes := []rune(text)
+	if len(runes) <= maxLength {
+		return text
+	}
+	return strings.TrimRight(string(runes[:maxLength-1]), " ") + "…"
+}
+
+// selectTemplates returns the templates for intentType in the best matching locale.
+// The requested locale, its base language, and the fallback locale are tried in order,
+// each first for the intent, then for the fallback intents and, unless strict, "unknown".
+func (p *ResponseGeneratorPlugin) selectTemplates(locale, intentType string) ([]string, bool) {
+	locale = normalizeLocale(locale)
+	base, _, _ := strings.Cut(locale, "-")
+
+	intentTypes := append([]string{intentType}, p.fallbackIntents...)
+	if !p.strict {
+		intentTypes = append(intentTypes, "unknown")
+	}
+
+	for _, candidate := range []string{locale, base, p.fallbackLocale} {
+		intents, exists := p.templates[candidate]
+		if !exists {
+			continue
+		}
+		for _, candidateIntent := range intentTypes {
+			if templates := intents[candidateIntent]; len(templates) > 0 {
+				return templates, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// normalizeLocale lowercases a locale and uses "-" as the region separator
+func normalizeLocale(locale string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(locale)), "_", "-")
+}
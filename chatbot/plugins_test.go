@@ -0,0 +1,459 @@
+package chatbot
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+func TestContextManagerPluginFactory(t *testing.T) {
+	plugin, err := ContextManagerPluginFactory(json.RawMessage(`{"maxHistorySize": 3}`))
+	if err != nil {
+		t.Fatalf("ContextManagerPluginFactory: %v", err)
+	}
+	if got := plugin.(*ContextManagerPlugin).maxHistorySize; got != 3 {
+		t.Errorf("maxHistorySize = %d, want 3", got)
+	}
+
+	plugin, err = ContextManagerPluginFactory(nil)
+	if err != nil {
+		t.Fatalf("ContextManagerPluginFactory(nil): %v", err)
+	}
+	if got := plugin.(*ContextManagerPlugin).maxHistorySize; got != 10 {
+		t.Errorf("default maxHistorySize = %d, want 10", got)
+	}
+
+	if _, err := ContextManagerPluginFactory(json.RawMessage(`{"maxHistorySize": "3"}`)); err == nil {
+		t.Error("ContextManagerPluginFactory with an invalid config succeeded, want an error")
+	}
+}
+
+func TestContextManagerTrimsByAge(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	plugin := NewContextManagerPluginWithOptions(ContextManagerOptions{
+		MaxAge: time.Hour,
+		Now:    func() time.Time { return now },
+	})
+
+	messages := []Message{
+		{Text: "old", SessionID: "s1", Timestamp: now.Add(-2 * time.Hour)},
+		{Text: "undated", SessionID: "s1"},
+		{Text: "recent", SessionID: "s1", Timestamp: now.Add(-time.Minute)},
+	}
+	var ctx *core.Context
+	for _, msg := range messages {
+		ctx = core.NewContext(msg)
+		if err := plugin.Execute(ctx); err != nil {
+			t.Fatalf("Execute(%q): %v", msg.Text, err)
+		}
+	}
+
+	state, _ := core.Value[ConversationState](ctx, "conversation_state")
+	var texts []string
+	for _, msg := range state.History {
+		texts = append(texts, msg.Text)
+	}
+	if want := []string{"undated", "recent"}; !reflect.DeepEqual(texts, want) {
+		t.Errorf("history = %v, want %v", texts, want)
+	}
+}
+
+func TestContextManagerTrimsByCountAndAge(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	plugin := NewContextManagerPluginWithOptions(ContextManagerOptions{
+		MaxHistorySize: 2,
+		MaxAge:         time.Hour,
+		Now:            func() time.Time { return now },
+	})
+
+	var ctx *core.Context
+	for _, text := range []string{"a", "b", "c"} {
+		ctx = core.NewContext(Message{Text: text, SessionID: "s1", Timestamp: now})
+		plugin.Execute(ctx)
+	}
+
+	state, _ := core.Value[ConversationState](ctx, "conversation_state")
+	if len(state.History) != 2 || state.History[0].Text != "b" {
+		t.Errorf("history = %v, want the last 2 messages", state.History)
+	}
+}
+
+func TestContextManagerUserPrefsFollowUser(t *testing.T) {
+	plugin := NewContextManagerPlugin(10)
+	if err := plugin.SetUserPref("u1", "lang", "es"); err != nil {
+		t.Fatalf("SetUserPref: %v", err)
+	}
+
+	ctx := core.NewContext(Message{Text: "hola", SessionID: "s2", UserID: "u1"})
+	if err := plugin.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	state, _ := core.Value[ConversationState](ctx, "conversation_state")
+	if state.UserPrefs["lang"] != "es" {
+		t.Errorf("session prefs = %v, want lang=es", state.UserPrefs)
+	}
+	if prefs, _ := core.Value[map[string]any](ctx, "user_prefs"); prefs["lang"] != "es" {
+		t.Errorf("user_prefs = %v, want lang=es", prefs)
+	}
+}
+
+func TestContextManagerUserPrefsSeparateFromSessions(t *testing.T) {
+	plugin := NewContextManagerPlugin(10)
+	plugin.SetUserPref("u1", "lang", "es")
+
+	// A session whose ID looks like a preference key must not see or clobber them
+	ctx := core.NewContext(Message{Text: "hi", SessionID: "user:u1"})
+	if err := plugin.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	state, _ := core.Value[ConversationState](ctx, "conversation_state")
+	if len(state.History) != 1 || len(state.UserPrefs) != 0 {
+		t.Errorf("session state = %+v, want one message and no prefs", state)
+	}
+
+	prefs, err := plugin.UserPrefs("u1")
+	if err != nil || prefs["lang"] != "es" {
+		t.Errorf("UserPrefs = %v, %v, want lang=es", prefs, err)
+	}
+}
+
+func TestContextManagerSetUserPrefConcurrent(t *testing.T) {
+	plugin := NewContextManagerPlugin(10)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			plugin.SetUserPref("u1", fmt.Sprintf("key%d", i), i)
+		}(i)
+	}
+	wg.Wait()
+
+	prefs, _ := plugin.UserPrefs("u1")
+	if len(prefs) != 50 {
+		t.Errorf("len(prefs) = %d, want 50; concurrent updates were lost", len(prefs))
+	}
+}
+
+func TestIntentClassifierMinConfidence(t *testing.T) {
+	// "hi" matches one of the seven greeting keywords, a confidence of about 0.14
+	text := "hi"
+	if intent := NewIntentClassifierPlugin().Classify(text); intent.Type != "greeting" {
+		t.Fatalf("Classify(%q) = %+v, want greeting without a threshold", text, intent)
+	}
+
+	strict := NewIntentClassifierPluginWithConfig(IntentClassifierConfig{MinConfidence: 0.2})
+	intent := strict.Classify(text)
+	if intent.Type != "unknown" {
+		t.Errorf("Classify(%q) = %+v, want unknown below the threshold", text, intent)
+	}
+	if intent.Confidence == 0 {
+		t.Error("downgraded intent lost its confidence")
+	}
+
+	if intent := strict.Classify("hello, hi, good morning"); intent.Type != "greeting" {
+		t.Errorf("Classify with 3 greeting keywords = %+v, want greeting above the threshold", intent)
+	}
+}
+
+// personalize runs response through a personality filter and returns the resulting text.
+func personalize(t *testing.T, plugin *PersonalityFilterPlugin, response Response, metadata map[string]any) string {
+	t.Helper()
+	ctx := core.NewContext(response)
+	for key, value := range metadata {
+		ctx.Set(key, value)
+	}
+	if err := plugin.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	return ctx.GetData().(Response).Text
+}
+
+func TestPersonalityFilterMaxLength(t *testing.T) {
+	tests := []struct {
+		text      string
+		maxLength int
+		want      string
+	}{
+		{"Short reply", 20, "Short reply"},
+		{"This reply is far too long", 10, "This repl…"},
+		{"Naïve café émigré", 8, "Naïve c…"},
+		{"Hello world", 7, "Hello…"}, // the space before the ellipsis is dropped
+	}
+	for _, test := range tests {
+		plugin := NewPersonalityFilterPlugin(PersonalityConfig{MaxLength: test.maxLength})
+		if got := personalize(t, plugin, Response{Text: test.text}, nil); got != test.want {
+			t.Errorf("MaxLength %d of %q = %q, want %q", test.maxLength, test.text, got, test.want)
+		}
+	}
+}
+
+func TestPersonalityFilterMaxLengthAppliesLast(t *testing.T) {
+	plugin := NewPersonalityFilterPlugin(PersonalityConfig{Suffix: "Have a nice day!", MaxLength: 12})
+	got := personalize(t, plugin, Response{Text: "Hi"}, nil)
+	if n := len([]rune(got)); n != 12 {
+		t.Errorf("text = %q (%d runes), want the suffix truncated to 12 runes", got, n)
+	}
+}
+
+func TestMultiPersonalityFilter(t *testing.T) {
+	plugin := NewMultiPersonalityFilterPlugin(map[string]PersonalityConfig{
+		"formal": {Prefix: "Dear user,"},
+		"pirate": {Prefix: "Arr!", Enthusiastic: true},
+	}, "formal")
+	response := Response{Text: "Your order shipped."}
+
+	tests := []struct {
+		persona any
+		want    string
+	}{
+		{"pirate", "Arr! Your order shipped!"},
+		{"formal", "Dear user, Your order shipped."},
+		{"unknown", "Dear user, Your order shipped."}, // unknown personas use the default
+		{42, "Dear user, Your order shipped."},
+	}
+	for _, test := range tests {
+		got := personalize(t, plugin, response, map[string]any{"persona": test.persona})
+		if got != test.want {
+			t.Errorf("persona %v: text = %q, want %q", test.persona, got, test.want)
+		}
+	}
+	if got := personalize(t, plugin, response, nil); got != "Dear user, Your order shipped." {
+		t.Errorf("without a persona: text = %q, want the default persona", got)
+	}
+}
+
+func TestMultiPersonalityFilterWithoutDefault(t *testing.T) {
+	plugin := NewMultiPersonalityFilterPlugin(map[string]PersonalityConfig{"pirate": {Prefix: "Arr!"}}, "")
+	if got := personalize(t, plugin, Response{Text: "Hello."}, nil); got != "Hello." {
+		t.Errorf("text = %q, want the response untransformed", got)
+	}
+}
+
+func TestPersonalityFilterEmojiMap(t *testing.T) {
+	greeting := Response{Text: "Hello!", Intent: Intent{Type: "greeting"}}
+
+	plugin := NewPersonalityFilterPlugin(PersonalityConfig{Emojis: true})
+	if got := personalize(t, plugin, greeting, nil); got != "Hello! 👋" {
+		t.Errorf("default emoji map: text = %q, want %q", got, "Hello! 👋")
+	}
+
+	custom := NewPersonalityFilterPlugin(PersonalityConfig{
+		Emojis:   true,
+		EmojiMap: map[string]string{"greeting": "🙂", "question": ""},
+	})
+	if got := personalize(t, custom, greeting, nil); got != "Hello! 🙂" {
+		t.Errorf("custom emoji map: text = %q, want %q", got, "Hello! 🙂")
+	}
+	question := Response{Text: "Why?", Intent: Intent{Type: "question"}}
+	if got := personalize(t, custom, question, nil); got != "Why?" {
+		t.Errorf("empty emoji: text = %q, want no emoji", got)
+	}
+	farewell := Response{Text: "Bye!", Intent: Intent{Type: "farewell"}}
+	if got := personalize(t, custom, farewell, nil); got != "Bye!" {
+		t.Errorf("intent missing from a custom map: text = %q, want no emoji", got)
+	}
+}
+
+// respond runs plugin on a context with the given metadata and returns the response text.
+func respond(t *testing.T, plugin *ResponseGeneratorPlugin, metadata map[string]any) string {
+	t.Helper()
+	ctx := core.NewContext(Message{Text: "hi", SessionID: "s1"})
+	for key, value := range metadata {
+		ctx.Set(key, value)
+	}
+	if err := plugin.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	response, ok := ctx.GetData().(Response)
+	if !ok {
+		t.Fatalf("data = %T, want Response", ctx.GetData())
+	}
+	return response.Text
+}
+
+func TestResponseGeneratorLocales(t *testing.T) {
+	plugin := NewLocalizedResponseGeneratorPlugin(map[string]map[string][]string{
+		"en":    {"greeting": {"Hello!"}, "unknown": {"Sorry?"}},
+		"es":    {"greeting": {"¡Hola!"}},
+		"pt_BR": {"greeting": {"Olá!"}},
+	}, "en")
+	greeting := Intent{Type: "greeting", Confidence: 1}
+
+	tests := []struct {
+		language any
+		want     string
+	}{
+		{"es", "¡Hola!"},
+		{"es-MX", "¡Hola!"},
+		{"PT-br", "Olá!"},
+		{"de", "Hello!"},
+		{nil, "Hello!"},
+	}
+	for _, tt := range tests {
+		metadata := map[string]any{"intent": greeting}
+		if tt.language != nil {
+			metadata["language"] = tt.language
+		}
+		if got := respond(t, plugin, metadata); got != tt.want {
+			t.Errorf("language %v: response = %q, want %q", tt.language, got, tt.want)
+		}
+	}
+
+	// An intent without templates in any locale gets the "unknown" template of the fallback locale
+	if got := respond(t, plugin, map[string]any{"intent": Intent{Type: "farewell"}, "language": "es"}); got != "Sorry?" {
+		t.Errorf("missing intent: response = %q, want the fallback locale's unknown template", got)
+	}
+}
+
+func TestResponseGeneratorStrict(t *testing.T) {
+	plugin := NewResponseGeneratorPlugin().WithStrict(true)
+
+	ctx := core.NewContext(Message{Text: "hi"})
+	ctx.Set("intent", Intent{Type: "custom"})
+	if err := plugin.Execute(ctx); err == nil {
+		t.Error("Execute succeeded for an intent without templates in strict mode")
+	}
+
+	plugin.WithFallbackIntents("question")
+	got := respond(t, plugin, map[string]any{"intent": Intent{Type: "custom"}})
+	if got != defaultTemplates()["question"][0] {
+		t.Errorf("response = %q, want the fallback intent's template", got)
+	}
+}
+
+// classifyChunk runs a streamed chunk for session through plugin and returns the intent.
+func classifyChunk(t *testing.T, plugin *IntentClassifierPlugin, session, chunk string) Intent {
+	t.Helper()
+	ctx := core.NewContext(Message{Text: chunk, SessionID: session})
+	if err := plugin.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	intent, _ := core.Value[Intent](ctx, "intent")
+	return intent
+}
+
+func TestIntentClassifierStreaming(t *testing.T) {
+	plugin := NewIntentClassifierPluginWithConfig(IntentClassifierConfig{Streaming: true})
+
+	classifyChunk(t, plugin, "call", "well good morn")
+	if intent := classifyChunk(t, plugin, "call", "ing to you"); intent.Type != "greeting" {
+		t.Errorf("intent = %q, want a greeting split across chunks", intent.Type)
+	}
+	if intent := classifyChunk(t, plugin, "other", "ing"); intent.Type == "greeting" {
+		t.Error("another session's chunks affected the intent")
+	}
+
+	plugin.ResetStream("call")
+	if intent := classifyChunk(t, plugin, "call", "ing"); intent.Type == "greeting" {
+		t.Error("stream state kept after ResetStream")
+	}
+}
+
+func TestIntentClassifierEvictsIdleStreams(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	plugin := NewIntentClassifierPluginWithConfig(IntentClassifierConfig{
+		Streaming:         true,
+		StreamIdleTimeout: time.Minute,
+	})
+	plugin.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		classifyChunk(t, plugin, fmt.Sprintf("abandoned-%d", i), "hello")
+	}
+	now = now.Add(30 * time.Second)
+	classifyChunk(t, plugin, "active", "hello")
+
+	now = now.Add(45 * time.Second)
+	classifyChunk(t, plugin, "active", "again")
+
+	if len(plugin.streams) != 1 {
+		t.Errorf("streams = %d, want only the active session after the idle timeout", len(plugin.streams))
+	}
+	if _, ok := plugin.streams["active"]; !ok {
+		t.Error("active session's stream was evicted")
+	}
+}
+
+func TestPersonalityFilterEnthusiastic(t *testing.T) {
+	plugin := NewPersonalityFilterPlugin(PersonalityConfig{Enthusiastic: true})
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Great. See you soon.", "Great! See you soon!"},
+		{"It costs 3.50 today.", "It costs 3.50 today!"},
+		{"Bring snacks, e.g. chips.", "Bring snacks, e.g. chips!"},
+		{"Well... maybe.", "Well... maybe!"},
+		{"Wow!. Done!!", "Wow! Done!"},
+		{"Visit example.com today.", "Visit example.com today!"},
+	}
+
+	for _, test := range tests {
+		if got := personalize(t, plugin, Response{Text: test.text}, nil); got != test.want {
+			t.Errorf("personalize(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestPersonalityFilterRules(t *testing.T) {
+	plugin := NewPersonalityFilterPlugin(PersonalityConfig{
+		Enthusiastic: true,
+		Suffix:       "Bye",
+		Rules: []TransformRule{
+			{Find: "order", Replace: "parcel"},
+			{Find: "parcel", Replace: "package"},
+			{Pattern: regexp.MustCompile(`#(\d+)`), Replace: "no. $1"},
+			{Find: ""},
+			{Pattern: regexp.MustCompile(`^(.*)$`), Replace: "<<$1>>"},
+		},
+	})
+
+	got := personalize(t, plugin, Response{Text: "Your order #42 shipped."}, nil)
+	if want := "<<Your package no. 42 shipped!>> Bye"; got != want {
+		t.Errorf("text = %q, want %q", got, want)
+	}
+}
+
+func TestIntentClassifierPositionDecay(t *testing.T) {
+	// One greeting and one farewell keyword: a tie that farewell wins alphabetically
+	text := "hi, and bye"
+	if intent := NewIntentClassifierPlugin().Classify(text); intent.Type != "farewell" {
+		t.Fatalf("Classify(%q) = %+v, want farewell without positional weighting", text, intent)
+	}
+
+	plugin := NewIntentClassifierPluginWithConfig(IntentClassifierConfig{PositionDecay: DefaultPositionDecay})
+	intent := plugin.Classify(text)
+	if intent.Type != "greeting" {
+		t.Errorf("Classify(%q) = %+v, want the leading greeting to win", text, intent)
+	}
+	if want := 1.0 / 7; intent.Confidence != want {
+		t.Errorf("confidence = %v, want %v for one keyword at the start", intent.Confidence, want)
+	}
+
+	if got := DefaultPositionDecay(1); got != 0.5 {
+		t.Errorf("DefaultPositionDecay(1) = %v, want 0.5", got)
+	}
+}
+
+func TestResponseGeneratorFallbackIntents(t *testing.T) {
+	plugin := NewLocalizedResponseGeneratorPlugin(map[string]map[string][]string{
+		"en": {"question": {"Let me check."}, "unknown": {"Sorry?"}},
+	}, "en")
+	custom := map[string]any{"intent": Intent{Type: "refund"}}
+
+	if got := respond(t, plugin, custom); got != "Sorry?" {
+		t.Errorf("response = %q, want the unknown template without fallbacks", got)
+	}
+	plugin.WithFallbackIntents("billing", "question")
+	if got := respond(t, plugin, custom); got != "Let me check." {
+		t.Errorf("response = %q, want the first fallback intent with templates", got)
+	}
+}
@@ -0,0 +1,242 @@
+package chatbot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// Redactor masks the value of a detected entity
+type Redactor func(value string) string
+
+// DefaultRedactors returns the redactors used when none are configured: emails and phone
+// numbers are masked, all other entity types are left alone
+func DefaultRedactors() map[string]Redactor {
+	return map[string]Redactor{
+		"email": MaskEmail,
+		"phone": MaskPhone,
+	}
+}
+
+// MaskEmail keeps the first character of the local part and the domain, masking the rest:
+// "john@example.com" becomes "j***@example.com"
+func MaskEmail(value string) string {
+	at := strings.LastIndex(value, "@")
+	if at < 0 {
+		return MaskAll(value)
+	}
+
+	local := []rune(value[:at])
+	for i := 1; i < len(local); i++ {
+		local[i] = '*'
+	}
+	return string(local) + value[at:]
+}
+
+// MaskPhone masks every digit except the last four, keeping separators:
+// "555-123-4567" becomes "***-***-4567"
+func MaskPhone(value string) string {
+	runes := []rune(value)
+	keep := 4
+	for i := len(runes) - 1; i >= 0; i-- {
+		if !unicode.IsDigit(runes[i]) {
+			continue
+		}
+		if keep > 0 {
+			keep--
+			continue
+		}
+		runes[i] = '*'
+	}
+	return string(runes)
+}
+
+// MaskAll replaces every non-space character with '*'
+func MaskAll(value string) string {
+	runes := []rune(value)
+	for i, r := range runes {
+		if !unicode.IsSpace(r) {
+			runes[i] = '*'
+		}
+	}
+	return string(runes)
+}
+
+// RedactionPlugin masks personal information found by EntityExtractorPlugin so it never
+// leaves the system. It works from the entity offsets in the "entities" metadata instead of
+// scanning the text again.
+//
+// Placed before ResponseGeneratorPlugin it redacts the message text; placed after it,
+// it redacts the response text and the entities echoed in the response. Entity values and
+// offsets are updated to match the redacted text.
+type RedactionPlugin struct {
+	redactors map[string]Redactor
+}
+
+// NewRedactionPlugin creates a redaction plugin that masks each entity type with its
+// redactor. Entity types without a redactor are left alone. Nil uses DefaultRedactors.
+func NewRedactionPlugin(redactors map[string]Redactor) *RedactionPlugin {
+	if redactors == nil {
+		redactors = DefaultRedactors()
+	}
+
+	return &RedactionPlugin{
+		redactors: redactors,
+	}
+}
+
+// Execute redacts the message or response text in the context
+func (p *RedactionPlugin) Execute(ctx *core.Context) error {
+	switch data := ctx.GetData().(type) {
+	case Message:
+		entitiesData, _ := ctx.Get("entities")
+		entities, _ := entitiesData.([]Entity)
+		if len(entities) == 0 {
+			return nil
+		}
+
+		spans := p.spans(data.Text, entities)
+		data.Text = applySpans(data.Text, spans)
+		ctx.SetData(data)
+		ctx.Set("entities", p.redactNormalized(remapEntities(entities, spans)))
+
+	case Response:
+		if len(data.Entities) == 0 {
+			return nil
+		}
+
+		// Response entities point into the message text, not the response text, so the
+		// response text is redacted by value, longest first so that an entity inside
+		// another (a number inside a phone) doesn't break the outer replacement
+		entities := p.redactNormalized(remapEntities(data.Entities, p.spans("", data.Entities)))
+		order := make([]int, len(entities))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return len(data.Entities[order[a]].Value) > len(data.Entities[order[b]].Value)
+		})
+		for _, i := range order {
+			if original := data.Entities[i].Value; original != entities[i].Value {
+				data.Text = strings.ReplaceAll(data.Text, original, entities[i].Value)
+			}
+		}
+
+		data.Entities = entities
+		ctx.SetData(data)
+		ctx.Set("entities", entities)
+
+	default:
+		return fmt.Errorf("expected Message or Response type in context data")
+	}
+
+	return nil
+}
+
+// redactNormalized masks the normalized values of entities with a redactor, in place
+func (p *RedactionPlugin) redactNormalized(entities []Entity) []Entity {
+	for i, entity := range entities {
+		if redact, ok := p.redactors[entity.Type]; ok && entity.Normalized != "" {
+			entities[i].Normalized = redact(entity.Normalized)
+		}
+	}
+	return entities
+}
+
+// redactedSpan is a byte range of the original text and its replacement
+type redactedSpan struct {
+	start, end int
+	masked     string
+}
+
+// spans returns the non-overlapping spans to redact in start order. When text is given,
+// entities whose span no longer matches their value are skipped so a stale offset can't
+// corrupt the text.
+func (p *RedactionPlugin) spans(text string, entities []Entity) []redactedSpan {
+	sorted := make([]Entity, len(entities))
+	copy(sorted, entities)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		return sorted[a].Start < sorted[b].Start
+	})
+
+	spans := make([]redactedSpan, 0)
+	lastEnd := 0
+	for _, entity := range sorted {
+		redact, ok := p.redactors[entity.Type]
+		if !ok || entity.Start < lastEnd || entity.End-entity.Start != len(entity.Value) {
+			continue
+		}
+		if text != "" && (entity.End > len(text) || text[entity.Start:entity.End] != entity.Value) {
+			continue
+		}
+
+		spans = append(spans, redactedSpan{start: entity.Start, end: entity.End, masked: redact(entity.Value)})
+		lastEnd = entity.End
+	}
+
+	return spans
+}
+
+// applySpans replaces each span of text with its mask
+func applySpans(text string, spans []redactedSpan) string {
+	var out strings.Builder
+	cursor := 0
+	for _, span := range spans {
+		out.WriteString(text[cursor:span.start])
+		out.WriteString(span.masked)
+		cursor = span.end
+	}
+	out.WriteString(text[cursor:])
+	return out.String()
+}
+
+// remapEntities returns a copy of entities with values and offsets matching the redacted
+// text. An entity inside a redacted span takes the matching part of the mask, or the whole
+// mask when the redactor changed the span's length.
+func remapEntities(entities []Entity, spans []redactedSpan) []Entity {
+	remapped := make([]Entity, len(entities))
+	for i, entity := range entities {
+		remapped[i] = entity
+		inside := false
+
+		for _, span := range spans {
+			if entity.Start < span.start || entity.End > span.end {
+				continue
+			}
+
+			start := shiftedOffset(span.start, spans)
+			if len(span.masked) == span.end-span.start {
+				remapped[i].Start = start + entity.Start - span.start
+				remapped[i].End = start + entity.End - span.start
+				remapped[i].Value = span.masked[entity.Start-span.start : entity.End-span.start]
+			} else {
+				remapped[i].Start = start
+				remapped[i].End = start + len(span.masked)
+				remapped[i].Value = span.masked
+			}
+			inside = true
+			break
+		}
+
+		if !inside {
+			remapped[i].Start = shiftedOffset(entity.Start, spans)
+			remapped[i].End = shiftedOffset(entity.End, spans)
+		}
+	}
+
+	return remapped
+}
+
+// shiftedOffset maps an offset in the original text to the redacted text
+func shiftedOffset(offset int, spans []redactedSpan) int {
+	shifted := offset
+	for _, span := range spans {
+		if span.end <= offset {
+			shifted += len(span.masked) - (span.end - span.start)
+		}
+	}
+	return shifted
+}
@@ -0,0 +1,88 @@
+package chatbot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+func TestMaskers(t *testing.T) {
+	tests := []struct {
+		name string
+		mask Redactor
+		in   string
+		want string
+	}{
+		{"email", MaskEmail, "john@example.com", "j***@example.com"},
+		{"email without at", MaskEmail, "john", "****"},
+		{"phone", MaskPhone, "555-123-4567", "***-***-4567"},
+		{"short phone", MaskPhone, "4567", "4567"},
+		{"all", MaskAll, "top secret", "*** ******"},
+	}
+	for _, tt := range tests {
+		if got := tt.mask(tt.in); got != tt.want {
+			t.Errorf("%s(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRedactionPluginMessage(t *testing.T) {
+	text := "Mail john@example.com or call 555-123-4567 after 5"
+	extractor := NewEntityExtractorPlugin()
+	ctx := core.NewContext(Message{Text: text})
+	if err := extractor.Execute(ctx); err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+
+	if err := NewRedactionPlugin(nil).Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	redacted := ctx.GetData().(Message).Text
+	if want := "Mail j***@example.com or call ***-***-4567 after 5"; redacted != want {
+		t.Errorf("text = %q, want %q", redacted, want)
+	}
+	entities, _ := core.Value[[]Entity](ctx, "entities")
+	for _, entity := range entities {
+		if redacted[entity.Start:entity.End] != entity.Value {
+			t.Errorf("entity %+v does not match the redacted text at its offsets", entity)
+		}
+		if strings.Contains(entity.Value, "john") || strings.Contains(entity.Value, "555-123") {
+			t.Errorf("entity %+v still holds personal information", entity)
+		}
+	}
+}
+
+func TestRedactionPluginResponse(t *testing.T) {
+	ctx := core.NewContext(Response{
+		Text: "I noticed you mentioned: john@example.com (email)",
+		Entities: []Entity{
+			{Type: "email", Value: "john@example.com", Start: 5, End: 21},
+		},
+	})
+
+	if err := NewRedactionPlugin(nil).Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	response := ctx.GetData().(Response)
+	if want := "I noticed you mentioned: j***@example.com (email)"; response.Text != want {
+		t.Errorf("text = %q, want %q", response.Text, want)
+	}
+	if response.Entities[0].Value != "j***@example.com" {
+		t.Errorf("entity value = %q, want it redacted", response.Entities[0].Value)
+	}
+}
+
+func TestRedactionPluginSkipsStaleOffsets(t *testing.T) {
+	ctx := core.NewContext(Message{Text: "write to john@example.com"})
+	ctx.Set("entities", []Entity{{Type: "email", Value: "john@example.com", Start: 0, End: 16}})
+
+	if err := NewRedactionPlugin(nil).Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if text := ctx.GetData().(Message).Text; text != "write to john@example.com" {
+		t.Errorf("text = %q, want it unchanged for an entity whose offsets don't match", text)
+	}
+}
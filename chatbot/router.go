@@ -0,0 +1,49 @@
+package chatbot
+
+import (
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// RouterPlugin dispatches to a different plugin per intent type, so each intent can use a
+// specialized response strategy (an FAQ lookup for questions, a scripted flow for commands)
+// while the rest fall through to a default such as ResponseGeneratorPlugin
+type RouterPlugin struct {
+	routes   map[string]core.Plugin
+	fallback core.Plugin
+}
+
+// NewRouterPlugin creates a router that runs fallback for intents without a route.
+// A nil fallback leaves unrouted requests unchanged.
+func NewRouterPlugin(fallback core.Plugin) *RouterPlugin {
+	return &RouterPlugin{
+		routes:   make(map[string]core.Plugin),
+		fallback: fallback,
+	}
+}
+
+// Route maps an intent type to the plugin that handles it and returns the router for method chaining
+func (p *RouterPlugin) Route(intentType string, plugin core.Plugin) *RouterPlugin {
+	p.routes[intentType] = plugin
+	return p
+}
+
+// Execute runs the plugin routed for the "intent" in context metadata, or the fallback.
+// A missing intent is routed as "unknown".
+func (p *RouterPlugin) Execute(ctx *core.Context) error {
+	intentType := "unknown"
+	if intentData, exists := ctx.Get("intent"); exists {
+		if intent, ok := intentData.(Intent); ok {
+			intentType = intent.Type
+		}
+	}
+
+	plugin, exists := p.routes[intentType]
+	if !exists {
+		plugin = p.fallback
+	}
+	if plugin == nil {
+		return nil
+	}
+
+	return plugin.Execute(ctx)
+}
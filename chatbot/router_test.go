@@ -0,0 +1,75 @@
+package chatbot
+
+import (
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// pluginFunc adapts a function to the core.Plugin interface.
+type pluginFunc func(*core.Context) error
+
+func (f pluginFunc) Execute(ctx *core.Context) error { return f(ctx) }
+
+// setHandler returns a plugin that records its name under "handler".
+func setHandler(name string) core.Plugin {
+	return pluginFunc(func(ctx *core.Context) error {
+		ctx.Set("handler", name)
+		return nil
+	})
+}
+
+func TestRouterPlugin(t *testing.T) {
+	router := NewRouterPlugin(setHandler("fallback")).
+		Route("question", setHandler("faq")).
+		Route("command", setHandler("script"))
+
+	tests := []struct {
+		name   string
+		intent any
+		want   string
+	}{
+		{"question", Intent{Type: "question"}, "faq"},
+		{"command", Intent{Type: "command"}, "script"},
+		{"unrouted intent", Intent{Type: "greeting"}, "fallback"},
+		{"no intent", nil, "fallback"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := core.NewContext(nil)
+			if tt.intent != nil {
+				ctx.Set("intent", tt.intent)
+			}
+			if err := router.Execute(ctx); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			if got, _ := core.Value[string](ctx, "handler"); got != tt.want {
+				t.Errorf("handler = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouterPluginUnknownRoute(t *testing.T) {
+	router := NewRouterPlugin(setHandler("fallback")).Route("unknown", setHandler("clarify"))
+
+	ctx := core.NewContext(nil)
+	if err := router.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if got, _ := core.Value[string](ctx, "handler"); got != "clarify" {
+		t.Errorf("handler = %q, want the route for unknown", got)
+	}
+}
+
+func TestRouterPluginNilFallback(t *testing.T) {
+	ctx := core.NewContext(nil)
+	ctx.Set("intent", Intent{Type: "greeting"})
+	if err := NewRouterPlugin(nil).Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if _, ok := ctx.Get("handler"); ok {
+		t.Error("unrouted request changed without a fallback")
+	}
+}
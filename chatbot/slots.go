@@ -0,0 +1,147 @@
+package chatbot
+
+import (
+	"fmt"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// SlotDefinition describes a value a form needs to collect
+type SlotDefinition struct {
+	Name       string // slot name, e.g. "date"
+	EntityType string // entity type that fills the slot, e.g. "date"
+	Prompt     string // question asked while the slot is missing
+}
+
+// SlotFillingPlugin collects the slots required by an intent across several turns.
+// When a message has an intent with a form, the form becomes active for the session;
+// each following message fills missing slots from the extracted entities until all are
+// filled. Progress is kept in the conversation store, so the plugin should share the
+// store of ContextManagerPlugin and run after it and after EntityExtractorPlugin.
+type SlotFillingPlugin struct {
+	store ConversationStore
+	forms map[string][]SlotDefinition
+}
+
+// NewSlotFillingPlugin creates a slot filler with the forms to fill per intent type.
+// Slots are filled and prompted for in the order given. A nil store defaults to a new
+// in-memory store.
+func NewSlotFillingPlugin(store ConversationStore, forms map[string][]SlotDefinition) *SlotFillingPlugin {
+	if store == nil {
+		store = NewMemoryConversationStore()
+	}
+	return &SlotFillingPlugin{
+		store: store,
+		forms: forms,
+	}
+}
+
+// Execute fills slots for the active form and stores the progress in context metadata:
+// "slots" (values collected so far), "missing_slots", "slots_complete", and, while slots
+// are missing, "slot_prompt" with the question for the next one. When the form completes
+// its intent is stored under "completed_form" and the session's form is cleared.
+func (p *SlotFillingPlugin) Execute(ctx *core.Context) error {
+	// Extract message from context
+	msg, ok := ctx.GetData().(Message)
+	if !ok {
+		return fmt.Errorf("expected Message type in context data")
+	}
+
+	convState, exists, err := p.store.Load(msg.SessionID)
+	if err != nil {
+		return fmt.Errorf("failed to load conversation %q: %w", msg.SessionID, err)
+	}
+	if !exists {
+		convState = ConversationState{
+			History:   make([]Message, 0),
+			UserPrefs: make(map[string]any),
+		}
+	}
+
+	// A new intent with a form starts that form, replacing any unfinished one
+	if intentData, exists := ctx.Get("intent"); exists {
+		if intent, ok := intentData.(Intent); ok {
+			if _, hasForm := p.forms[intent.Type]; hasForm && intent.Type != convState.FormIntent {
+				convState.FormIntent = intent.Type
+				convState.Slots = make(map[string]string)
+			}
+		}
+	}
+
+	slots, active := p.forms[convState.FormIntent]
+	if !active {
+		return nil
+	}
+	if convState.Slots == nil {
+		convState.Slots = make(map[string]string)
+	}
+
+	// Fill missing slots from this message's entities
+	var entities []Entity
+	if entitiesData, exists := ctx.Get("entities"); exists {
+		if e, ok := entitiesData.([]Entity); ok {
+			entities = e
+		}
+	}
+	used := make(map[int]bool)
+	for _, slot := range slots {
+		if _, filled := convState.Slots[slot.Name]; filled {
+			continue
+		}
+		for i, entity := range entities {
+			if !used[i] && entity.Type == slot.EntityType {
+				convState.Slots[slot.Name] = entity.Value
+				used[i] = true
+				break
+			}
+		}
+	}
+
+	missing := make([]string, 0)
+	for _, slot := range slots {
+		if _, filled := convState.Slots[slot.Name]; !filled {
+			missing = append(missing, slot.Name)
+		}
+	}
+
+	values := make(map[string]string, len(convState.Slots))
+	for name, value := range convState.Slots {
+		values[name] = value
+	}
+	ctx.Set("slots", values)
+	ctx.Set("missing_slots", missing)
+	ctx.Set("slots_complete", len(missing) == 0)
+
+	if len(missing) > 0 {
+		ctx.Set("slot_prompt", slotPrompt(slots, missing[0]))
+	} else {
+		ctx.Set("completed_form", convState.FormIntent)
+		convState.FormIntent = ""
+		convState.Slots = nil
+	}
+
+	// Persist form progress
+	if err := p.store.Save(msg.SessionID, convState); err != nil {
+		return fmt.Errorf("failed to save conversation %q: %w", msg.SessionID, err)
+	}
+
+	ctx.SetState(fmt.Sprintf("conversation:%s", msg.SessionID), convState)
+	ctx.Set("conversation_state", convState)
+
+	return nil
+}
+
+// HealthCheck reports the health of the conversation store
+func (p *SlotFillingPlugin) HealthCheck() error {
+	return storeHealth(p.store)
+}
+
+// slotPrompt returns the prompt for the named slot, with a generic question as the default
+func slotPrompt(slots []SlotDefinition, name string) string {
+	for _, slot := range slots {
+		if slot.Name == name && slot.Prompt != "" {
+			return slot.Prompt
+		}
+	}
+	return fmt.Sprintf("What is your %s?", name)
+}
@@ -0,0 +1,89 @@
+package chatbot
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// bookingForms returns a form collecting a date and an email for "booking" intents.
+func bookingForms() map[string][]SlotDefinition {
+	return map[string][]SlotDefinition{
+		"booking": {
+			{Name: "date", EntityType: "date", Prompt: "When would you like to come?"},
+			{Name: "email", EntityType: "email"},
+		},
+	}
+}
+
+// fillSlots runs one turn through plugin with the given intent and entities.
+func fillSlots(t *testing.T, plugin *SlotFillingPlugin, intent string, entities ...Entity) *core.Context {
+	t.Helper()
+	ctx := core.NewContext(Message{Text: "turn", SessionID: "s1"})
+	if intent != "" {
+		ctx.Set("intent", Intent{Type: intent, Confidence: 1})
+	}
+	ctx.Set("entities", entities)
+	if err := plugin.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	return ctx
+}
+
+func TestSlotFillingAcrossTurns(t *testing.T) {
+	plugin := NewSlotFillingPlugin(NewMemoryConversationStore(), bookingForms())
+
+	ctx := fillSlots(t, plugin, "booking")
+	if prompt, _ := core.Value[string](ctx, "slot_prompt"); prompt != "When would you like to come?" {
+		t.Errorf("slot_prompt = %q, want the date prompt", prompt)
+	}
+
+	ctx = fillSlots(t, plugin, "", Entity{Type: "date", Value: "2024-05-01"})
+	if missing, _ := core.Value[[]string](ctx, "missing_slots"); !reflect.DeepEqual(missing, []string{"email"}) {
+		t.Errorf("missing_slots = %v, want [email]", missing)
+	}
+	if prompt, _ := core.Value[string](ctx, "slot_prompt"); prompt != "What is your email?" {
+		t.Errorf("slot_prompt = %q, want the generic email prompt", prompt)
+	}
+
+	ctx = fillSlots(t, plugin, "", Entity{Type: "email", Value: "a@example.com"})
+	if complete, _ := core.Value[bool](ctx, "slots_complete"); !complete {
+		t.Fatal("slots_complete = false after all slots were filled")
+	}
+	if form, _ := core.Value[string](ctx, "completed_form"); form != "booking" {
+		t.Errorf("completed_form = %q, want booking", form)
+	}
+	want := map[string]string{"date": "2024-05-01", "email": "a@example.com"}
+	if slots, _ := core.Value[map[string]string](ctx, "slots"); !reflect.DeepEqual(slots, want) {
+		t.Errorf("slots = %v, want %v", slots, want)
+	}
+
+	// The completed form is cleared, so the next turn has no active form
+	ctx = fillSlots(t, plugin, "")
+	if _, ok := ctx.Get("slots"); ok {
+		t.Error("form still active after completion")
+	}
+}
+
+func TestSlotFillingNilStore(t *testing.T) {
+	plugin := NewSlotFillingPlugin(nil, bookingForms())
+
+	fillSlots(t, plugin, "booking", Entity{Type: "date", Value: "2024-05-01"})
+	ctx := fillSlots(t, plugin, "", Entity{Type: "email", Value: "a@example.com"})
+	if complete, _ := core.Value[bool](ctx, "slots_complete"); !complete {
+		t.Error("progress not kept across turns with the default store")
+	}
+	if err := plugin.HealthCheck(); err != nil {
+		t.Errorf("HealthCheck: %v", err)
+	}
+}
+
+func TestSlotFillingIgnoresIntentsWithoutForm(t *testing.T) {
+	plugin := NewSlotFillingPlugin(nil, bookingForms())
+
+	ctx := fillSlots(t, plugin, "greeting", Entity{Type: "date", Value: "2024-05-01"})
+	if _, ok := ctx.Get("slots"); ok {
+		t.Error("slots set for an intent without a form")
+	}
+}
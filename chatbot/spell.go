@@ -0,0 +1,182 @@
+package chatbot
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// minCorrectableLength is the shortest word the spell corrector will change; shorter
+// words have too many neighbors within a small edit distance to correct reliably
+const minCorrectableLength = 4
+
+// SpellCorrectorPlugin replaces misspelled words with the closest dictionary word so typos
+// don't defeat intent and entity matching. Place it before IntentClassifierPlugin.
+//
+// Words in the dictionary, words shorter than four letters, words containing digits,
+// and capitalized words in mid-sentence (likely proper nouns) are left alone.
+type SpellCorrectorPlugin struct {
+	words       []string
+	dictionary  map[string]bool
+	maxDistance int
+}
+
+// NewSpellCorrectorPlugin creates a spell corrector that corrects words to the nearest
+// dictionary word at most maxDistance edits away. A maxDistance of zero or less defaults to 2.
+// On ties the word listed first in the dictionary wins.
+func NewSpellCorrectorPlugin(dictionary []string, maxDistance int) *SpellCorrectorPlugin {
+	if maxDistance <= 0 {
+		maxDistance = 2
+	}
+
+	words := make([]string, 0, len(dictionary))
+	known := make(map[string]bool, len(dictionary))
+	for _, word := range dictionary {
+		word = strings.ToLower(word)
+		if !known[word] {
+			known[word] = true
+			words = append(words, word)
+		}
+	}
+
+	return &SpellCorrectorPlugin{
+		words:       words,
+		dictionary:  known,
+		maxDistance: maxDistance,
+	}
+}
+
+// Execute corrects the message text and stores the original under "original_text".
+// The corrections made are stored under "spelling_corrections" as a map of
+// misspelled word to replacement.
+func (p *SpellCorrectorPlugin) Execute(ctx *core.Context) error {
+	// Extract message from context
+	msg, ok := ctx.GetData().(Message)
+	if !ok {
+		return fmt.Errorf("expected Message type in context data")
+	}
+
+	corrections := make(map[string]string)
+	runes := []rune(msg.Text)
+	var corrected strings.Builder
+	sentenceStart := true
+
+	for i := 0; i < len(runes); {
+		if !isWordLetter(runes[i]) && !unicode.IsDigit(runes[i]) {
+			if runes[i] == '.' || runes[i] == '!' || runes[i] == '?' {
+				sentenceStart = true
+			}
+			corrected.WriteRune(runes[i])
+			i++
+			continue
+		}
+
+		// Consume a whole word
+		start := i
+		for i < len(runes) && (isWordLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+			i++
+		}
+		word := string(runes[start:i])
+
+		replacement := word
+		if p.shouldCorrect(word, sentenceStart) {
+			if match, found := p.nearest(strings.ToLower(word)); found {
+				replacement = matchCase(match, word)
+				corrections[word] = replacement
+			}
+		}
+		corrected.WriteString(replacement)
+		sentenceStart = false
+	}
+
+	if len(corrections) == 0 {
+		return nil
+	}
+
+	// Keep the earliest original if another plugin already rewrote the text
+	if _, exists := ctx.Get("original_text"); !exists {
+		ctx.Set("original_text", msg.Text)
+	}
+	ctx.Set("spelling_corrections", corrections)
+
+	msg.Text = corrected.String()
+	ctx.SetData(msg)
+
+	return nil
+}
+
+// shouldCorrect reports whether word is a candidate for correction
+func (p *SpellCorrectorPlugin) shouldCorrect(word string, sentenceStart bool) bool {
+	runes := []rune(word)
+	if len(runes) < minCorrectableLength || p.dictionary[strings.ToLower(word)] {
+		return false
+	}
+	for _, r := range runes {
+		if unicode.IsDigit(r) {
+			return false
+		}
+	}
+	// A capital letter mid-sentence usually marks a proper noun
+	if unicode.IsUpper(runes[0]) && !sentenceStart {
+		return false
+	}
+	return true
+}
+
+// nearest returns the dictionary word closest to word within maxDistance edits
+func (p *SpellCorrectorPlugin) nearest(word string) (string, bool) {
+	best := ""
+	bestDistance := p.maxDistance + 1
+	for _, candidate := range p.words {
+		distance := editDistance(word, candidate)
+		if distance < bestDistance {
+			best = candidate
+			bestDistance = distance
+		}
+	}
+	return best, best != ""
+}
+
+// isWordLetter reports whether r can be part of a correctable word
+func isWordLetter(r rune) bool {
+	return unicode.IsLetter(r) || r == '\''
+}
+
+// matchCase applies the capitalization of original to the lowercase word
+func matchCase(word, original string) string {
+	if original == strings.ToUpper(original) && len([]rune(original)) > 1 {
+		return strings.ToUpper(word)
+	}
+	runes := []rune(original)
+	if unicode.IsUpper(runes[0]) {
+		corrected := []rune(word)
+		corrected[0] = unicode.ToUpper(corrected[0])
+		return string(corrected)
+	}
+	return word
+}
+
+// editDistance returns the Levenshtein distance between a and b, counted in runes
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
@@ -0,0 +1,78 @@
+package chatbot
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// correct runs text through plugin and returns the context.
+func correct(t *testing.T, plugin *SpellCorrectorPlugin, text string) *core.Context {
+	t.Helper()
+	ctx := core.NewContext(Message{Text: text})
+	if err := plugin.Execute(ctx); err != nil {
+		t.Fatalf("Execute(%q): %v", text, err)
+	}
+	return ctx
+}
+
+func TestSpellCorrectorPlugin(t *testing.T) {
+	plugin := NewSpellCorrectorPlugin([]string{"weather", "tomorrow", "please", "hello"}, 2)
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"whats the wether tomorow", "whats the weather tomorrow"},
+		{"Helo there. Plase come", "Hello there. Please come"},
+		{"WETHER report", "WEATHER report"},
+		{"ask Wether about it", "ask Wether about it"}, // proper noun mid-sentence
+		{"room 4wether", "room 4wether"},               // contains a digit
+		{"hte weather", "hte weather"},                 // too short to correct
+		{"completely unrelated", "completely unrelated"},
+	}
+
+	for _, tt := range tests {
+		ctx := correct(t, plugin, tt.text)
+		if got := ctx.GetData().(Message).Text; got != tt.want {
+			t.Errorf("corrected %q = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSpellCorrectorPluginMetadata(t *testing.T) {
+	plugin := NewSpellCorrectorPlugin([]string{"weather"}, 0)
+
+	ctx := correct(t, plugin, "nice wether")
+	if original, _ := core.Value[string](ctx, "original_text"); original != "nice wether" {
+		t.Errorf("original_text = %q, want the uncorrected text", original)
+	}
+	corrections, _ := core.Value[map[string]string](ctx, "spelling_corrections")
+	if want := map[string]string{"wether": "weather"}; !reflect.DeepEqual(corrections, want) {
+		t.Errorf("spelling_corrections = %v, want %v", corrections, want)
+	}
+
+	ctx = correct(t, plugin, "nice weather")
+	if _, ok := ctx.Get("original_text"); ok {
+		t.Error("original_text set although nothing was corrected")
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"kitten", "sitting", 3},
+		{"wether", "weather", 1},
+		{"café", "cafe", 1},
+		{"", "abc", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package chatbot
+
+import (
+	"sync"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// ConversationStore persists conversation state between pipeline executions.
+// Implementations must be safe for concurrent use; a Redis- or database-backed
+// store can be plugged into ContextManagerPlugin to share state across instances.
+// Stores that can become unavailable should also implement core.HealthChecker, which
+// the plugins using the store report through Pipeline.Health.
+type ConversationStore interface {
+	// Load returns the state for a session and whether it exists.
+	Load(sessionID string) (ConversationState, bool, error)
+	// Save stores the state for a session, replacing any previous state.
+	Save(sessionID string, state ConversationState) error
+}
+
+// UserPrefsStore persists preferences that follow a user across sessions. It is keyed by
+// user ID, separately from sessions, so a session ID can never collide with a user's
+// preferences. Implementations must be safe for concurrent use.
+type UserPrefsStore interface {
+	// LoadUserPrefs returns the preferences stored for a user, or nil if there are none.
+	LoadUserPrefs(userID string) (map[string]any, error)
+	// SaveUserPrefs stores the preferences for a user, replacing any previous ones.
+	SaveUserPrefs(userID string, prefs map[string]any) error
+}
+
+// MemoryConversationStore is an in-process ConversationStore and UserPrefsStore backed by maps
+type MemoryConversationStore struct {
+	mu     sync.RWMutex
+	states map[string]ConversationState
+	prefs  map[string]map[string]any
+}
+
+// NewMemoryConversationStore creates an empty in-memory conversation store
+func NewMemoryConversationStore() *MemoryConversationStore {
+	return &MemoryConversationStore{
+		states: make(map[string]ConversationState),
+		prefs:  make(map[string]map[string]any),
+	}
+}
+
+// Load returns a copy of the stored state for the session
+func (s *MemoryConversationStore) Load(sessionID string) (ConversationState, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	state, exists := s.states[sessionID]
+	if !exists {
+		return ConversationState{}, false, nil
+	}
+	return copyConversationState(state), true, nil
+}
+
+// Save stores a copy of the state for the session
+func (s *MemoryConversationStore) Save(sessionID string, state ConversationState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.states[sessionID] = copyConversationState(state)
+	return nil
+}
+
+// LoadUserPrefs returns a copy of the stored preferences for the user
+func (s *MemoryConversationStore) LoadUserPrefs(userID string) (map[string]any, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return copyPrefs(s.prefs[userID]), nil
+}
+
+// SaveUserPrefs stores a copy of the preferences for the user
+func (s *MemoryConversationStore) SaveUserPrefs(userID string, prefs map[string]any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prefs[userID] = copyPrefs(prefs)
+	return nil
+}
+
+// storeHealth returns the health of store, if it reports one
+func storeHealth(store ConversationStore) error {
+	if checker, ok := store.(core.HealthChecker); ok {
+		return checker.HealthCheck()
+	}
+	return nil
+}
+
+// copyConversationState copies the history and seen entity slices, the preference and slot maps and the summary so stored state
+// is not shared with callers that keep modifying their copy
+func copyConversationState(state ConversationState) ConversationState {
+	history := make([]Message, len(state.History))
+	copy(history, state.History)
+	state.History = history
+
+	state.UserPrefs = copyPrefs(state.UserPrefs)
+	if state.UserPrefs == nil {
+		state.UserPrefs = make(map[string]any)
+	}
+
+	if state.Slots != nil {
+		slots := make(map[string]string, len(state.Slots))
+		for name, value := range state.Slots {
+			slots[name] = value
+		}
+		state.Slots = slots
+	}
+
+	if state.SeenEntities != nil {
+		seen := make([]string, len(state.SeenEntities))
+		copy(seen, state.SeenEntities)
+		state.SeenEntities = seen
+	}
+
+	if state.Summary != nil {
+		summary := *state.Summary
+		summary.Intents = make(map[string]int, len(state.Summary.Intents))
+		for intentType, count := range state.Summary.Intents {
+			summary.Intents[intentType] = count
+		}
+		summary.Entities = append([]string(nil), state.Summary.Entities...)
+		state.Summary = &summary
+	}
+
+	return state
+}
+
+// copyPrefs returns a copy of prefs, or nil if prefs is nil
+func copyPrefs(prefs map[string]any) map[string]any {
+	if prefs == nil {
+		return nil
+	}
+	copied := make(map[string]any, len(prefs))
+	for key, value := range prefs {
+		copied[key] = value
+	}
+	return copied
+}
@@ -0,0 +1,67 @@
+package chatbot
+
+import (
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// countingStore records the sessions loaded and saved through it.
+type countingStore struct {
+	*MemoryConversationStore
+	loads []string
+	saves []string
+}
+
+func (s *countingStore) Load(sessionID string) (ConversationState, bool, error) {
+	s.loads = append(s.loads, sessionID)
+	return s.MemoryConversationStore.Load(sessionID)
+}
+
+func (s *countingStore) Save(sessionID string, state ConversationState) error {
+	s.saves = append(s.saves, sessionID)
+	return s.MemoryConversationStore.Save(sessionID, state)
+}
+
+func TestContextManagerUsesStore(t *testing.T) {
+	store := &countingStore{MemoryConversationStore: NewMemoryConversationStore()}
+	plugin := NewContextManagerPluginWithStore(5, store)
+
+	for _, text := range []string{"hello", "how are you"} {
+		if err := plugin.Execute(core.NewContext(Message{Text: text, SessionID: "s1"})); err != nil {
+			t.Fatalf("Execute(%q): %v", text, err)
+		}
+	}
+
+	if len(store.loads) != 2 || len(store.saves) != 2 {
+		t.Errorf("loads = %v, saves = %v, want 2 of each", store.loads, store.saves)
+	}
+	state, exists, _ := store.MemoryConversationStore.Load("s1")
+	if !exists || len(state.History) != 2 {
+		t.Fatalf("stored history = %v, want 2 messages", state.History)
+	}
+}
+
+func TestMemoryConversationStoreCopies(t *testing.T) {
+	store := NewMemoryConversationStore()
+	state := ConversationState{
+		History:   []Message{{Text: "hello"}},
+		UserPrefs: map[string]any{"lang": "en"},
+	}
+	store.Save("s1", state)
+
+	// Changes to the saved or loaded state must not reach the store
+	state.History[0].Text = "changed"
+	state.UserPrefs["lang"] = "fr"
+	loaded, _, _ := store.Load("s1")
+	loaded.UserPrefs["lang"] = "de"
+
+	again, _, _ := store.Load("s1")
+	if again.History[0].Text != "hello" || again.UserPrefs["lang"] != "en" {
+		t.Errorf("stored state = %+v, want the state as saved", again)
+	}
+
+	if _, exists, err := store.Load("missing"); exists || err != nil {
+		t.Errorf("Load(missing) = %v, %v, want false, nil", exists, err)
+	}
+}
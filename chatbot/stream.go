@@ -0,0 +1,90 @@
+package chatbot
+
+import (
+	"fmt"
+	"time"
+	"unicode"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// ResponseStream delivers a response's text in chunks. Concatenating the chunks in order
+// gives the full response text. The channel is closed after the last chunk.
+type ResponseStream <-chan string
+
+// ResponseStreamPlugin turns the final response into a ResponseStream stored under
+// "response_stream", so servers can show the reply as it is being "typed". Chunks are
+// words with their trailing whitespace, sent delay apart. Place it last, after
+// ResponseGeneratorPlugin and PersonalityFilterPlugin.
+//
+// The stream is buffered to hold every chunk, so an unread stream never blocks.
+type ResponseStreamPlugin struct {
+	delay time.Duration
+}
+
+// NewResponseStreamPlugin creates a response streamer that waits delay between chunks
+// (0 sends them all at once)
+func NewResponseStreamPlugin(delay time.Duration) *ResponseStreamPlugin {
+	return &ResponseStreamPlugin{
+		delay: delay,
+	}
+}
+
+// Execute starts streaming the response text and stores the stream under "response_stream".
+// The response itself is left unchanged.
+func (p *ResponseStreamPlugin) Execute(ctx *core.Context) error {
+	// Extract response from context
+	response, ok := ctx.GetData().(Response)
+	if !ok {
+		return fmt.Errorf("expected Response type in context data")
+	}
+
+	chunks := SplitWords(response.Text)
+	stream := make(chan string, len(chunks))
+
+	if p.delay <= 0 {
+		for _, chunk := range chunks {
+			stream <- chunk
+		}
+		close(stream)
+	} else {
+		go func() {
+			defer close(stream)
+			for i, chunk := range chunks {
+				if i > 0 {
+					time.Sleep(p.delay)
+				}
+				stream <- chunk
+			}
+		}()
+	}
+
+	ctx.Set("response_stream", ResponseStream(stream))
+	return nil
+}
+
+// SplitWords splits text into words, each keeping the whitespace that follows it.
+// Leading whitespace is kept with the first word, so joining the chunks gives back text.
+func SplitWords(text string) []string {
+	chunks := make([]string, 0)
+	start := 0
+	sawWord, prevSpace := false, false
+
+	for i, r := range text {
+		space := unicode.IsSpace(r)
+		if !space {
+			// A word after whitespace starts the next chunk
+			if sawWord && prevSpace {
+				chunks = append(chunks, text[start:i])
+				start = i
+			}
+			sawWord = true
+		}
+		prevSpace = space
+	}
+
+	if start < len(text) {
+		chunks = append(chunks, text[start:])
+	}
+	return chunks
+}
@@ -0,0 +1,55 @@
+package chatbot
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Hello there, friend!", []string{"Hello ", "there, ", "friend!"}},
+		{"  padded  text \n", []string{"  padded  ", "text \n"}},
+		{"one", []string{"one"}},
+		{"", []string{}},
+	}
+
+	for _, test := range tests {
+		got := SplitWords(test.text)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("SplitWords(%q) = %q, want %q", test.text, got, test.want)
+		}
+		if joined := strings.Join(got, ""); joined != test.text {
+			t.Errorf("joined chunks = %q, want %q", joined, test.text)
+		}
+	}
+}
+
+func TestResponseStream(t *testing.T) {
+	for _, delay := range []time.Duration{0, time.Millisecond} {
+		ctx := core.NewContext(Response{Text: "Your order has shipped."})
+		if err := NewResponseStreamPlugin(delay).Execute(ctx); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		stream, ok := core.Value[ResponseStream](ctx, "response_stream")
+		if !ok {
+			t.Fatal("response_stream not set")
+		}
+
+		var text strings.Builder
+		chunks := 0
+		for chunk := range stream {
+			text.WriteString(chunk)
+			chunks++
+		}
+		if text.String() != "Your order has shipped." || chunks != 4 {
+			t.Errorf("delay %v: streamed %q in %d chunks, want the full text in 4", delay, text.String(), chunks)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package chatbot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// Summarizer defaults used for zero values in SummarizerConfig
+const (
+	DefaultSummaryThreshold   = 8
+	DefaultSummaryKeep        = 4
+	DefaultSummaryMaxEntities = 10
+)
+
+// SummarizerConfig defines when and how the conversation history is condensed
+type SummarizerConfig struct {
+	Threshold   int                    // History longer than this is condensed (0 uses DefaultSummaryThreshold)
+	Keep        int                    // Most recent messages kept verbatim (0 uses DefaultSummaryKeep)
+	MaxEntities int                    // Entities remembered in the summary, newest kept (0 uses DefaultSummaryMaxEntities)
+	Classifier  IntentClassifier       // Classifies summarized messages (nil uses NewIntentClassifierPlugin)
+	Extractor   *EntityExtractorPlugin // Finds entities in summarized messages (nil uses NewEntityExtractorPlugin)
+}
+
+// SummarizerPlugin keeps long conversations cheap to carry by condensing the older
+// messages of the history into a ConversationSummary: the number of messages per intent
+// and the entities mentioned. When the history grows past the threshold, all but the most
+// recent messages are folded into the summary and removed from the history. The summary
+// is kept in the conversation store, so the plugin must share the store of
+// ContextManagerPlugin, with a threshold below its history size, and run after it.
+type SummarizerPlugin struct {
+	store       ConversationStore
+	threshold   int
+	keep        int
+	maxEntities int
+	classifier  IntentClassifier
+	extractor   *EntityExtractorPlugin
+}
+
+// NewSummarizerPlugin creates a summarizer backed by store with the given configuration.
+// The store must be the one the ContextManagerPlugin writes the history to; with any other
+// store, including nil, Execute fails rather than never summarizing.
+func NewSummarizerPlugin(store ConversationStore, config SummarizerConfig) *SummarizerPlugin {
+	if config.Threshold <= 0 {
+		config.Threshold = DefaultSummaryThreshold
+	}
+	if config.Keep <= 0 {
+		config.Keep = DefaultSummaryKeep
+	}
+	if config.MaxEntities <= 0 {
+		config.MaxEntities = DefaultSummaryMaxEntities
+	}
+	if config.Classifier == nil {
+		config.Classifier = NewIntentClassifierPlugin()
+	}
+	if config.Extractor == nil {
+		config.Extractor = NewEntityExtractorPlugin()
+	}
+
+	return &SummarizerPlugin{
+		store:       store,
+		threshold:   config.Threshold,
+		keep:        min(config.Keep, config.Threshold),
+		maxEntities: config.MaxEntities,
+		classifier:  config.Classifier,
+		extractor:   config.Extractor,
+	}
+}
+
+// Execute condenses the history if it is over the threshold and stores the summary text
+// under "conversation_summary" once there is one. It fails if the context manager ran but
+// the conversation is missing from the summarizer's store, which means they don't share it.
+func (p *SummarizerPlugin) Execute(ctx *core.Context) error {
+	// Extract message from context
+	msg, ok := ctx.GetData().(Message)
+	if !ok {
+		return fmt.Errorf("expected Message type in context data")
+	}
+	if p.store == nil {
+		return fmt.Errorf("summarizer has no conversation store; pass the context manager's store")
+	}
+
+	convState, exists, err := p.store.Load(msg.SessionID)
+	if err != nil {
+		return fmt.Errorf("failed to load conversation %q: %w", msg.SessionID, err)
+	}
+	if _, managed := ctx.Get("conversation_state"); managed && !exists {
+		return fmt.Errorf("conversation %q is not in the summarizer's store; pass the context manager's store", msg.SessionID)
+	}
+	if !exists || len(convState.History) <= p.threshold {
+		if convState.Summary != nil {
+			ctx.Set("conversation_summary", convState.Summary.Text)
+		}
+		return nil
+	}
+
+	older := convState.History[:len(convState.History)-p.keep]
+	convState.Summary = p.summarize(convState.Summary, older)
+	convState.History = append([]Message(nil), convState.History[len(older):]...)
+
+	// Persist the condensed history
+	if err := p.store.Save(msg.SessionID, convState); err != nil {
+		return fmt.Errorf("failed to save conversation %q: %w", msg.SessionID, err)
+	}
+
+	ctx.SetState(fmt.Sprintf("conversation:%s", msg.SessionID), convState)
+	ctx.Set("conversation_state", convState)
+	ctx.Set("conversation_summary", convState.Summary.Text)
+
+	return nil
+}
+
+// HealthCheck reports the health of the conversation store
+func (p *SummarizerPlugin) HealthCheck() error {
+	if p.store == nil {
+		return fmt.Errorf("summarizer has no conversation store")
+	}
+	return storeHealth(p.store)
+}
+
+// summarize folds messages into a copy of summary, which may be nil
+func (p *SummarizerPlugin) summarize(summary *ConversationSummary, messages []Message) *ConversationSummary {
+	updated := &ConversationSummary{
+		Intents: make(map[string]int),
+	}
+	if summary != nil {
+		updated.Messages = summary.Messages
+		for intentType, count := range summary.Intents {
+			updated.Intents[intentType] = count
+		}
+		updated.Entities = append(updated.Entities, summary.Entities...)
+	}
+
+	for _, message := range messages {
+		updated.Messages++
+		if intent := p.classifier.Classify(message.Text); intent.Type != "unknown" {
+			updated.Intents[intent.Type]++
+		}
+
+		for _, entity := range p.extractor.Extract(message.Text) {
+			mention := fmt.Sprintf("%s (%s)", entity.Value, entity.Type)
+			updated.Entities = appendUnique(updated.Entities, mention)
+		}
+	}
+
+	if len(updated.Entities) > p.maxEntities {
+		updated.Entities = updated.Entities[len(updated.Entities)-p.maxEntities:]
+	}
+	updated.Text = summaryText(updated)
+	return updated
+}
+
+// summaryText renders a summary such as
+// "Earlier, 6 messages: question (4), greeting (1). Mentioned: john@example.com (email)."
+func summaryText(summary *ConversationSummary) string {
+	intentTypes := make([]string, 0, len(summary.Intents))
+	for intentType := range summary.Intents {
+		intentTypes = append(intentTypes, intentType)
+	}
+	// Most frequent first, ties alphabetically
+	sort.Slice(intentTypes, func(i, j int) bool {
+		a, b := intentTypes[i], intentTypes[j]
+		if summary.Intents[a] != summary.Intents[b] {
+			return summary.Intents[a] > summary.Intents[b]
+		}
+		return a < b
+	})
+
+	var text strings.Builder
+	if summary.Messages == 1 {
+		text.WriteString("Earlier, 1 message")
+	} else {
+		fmt.Fprintf(&text, "Earlier, %d messages", summary.Messages)
+	}
+	for i, intentType := range intentTypes {
+		separator := ", "
+		if i == 0 {
+			separator = ": "
+		}
+		fmt.Fprintf(&text, "%s%s (%d)", separator, intentType, summary.Intents[intentType])
+	}
+	text.WriteString(".")
+	if len(summary.Entities) > 0 {
+		fmt.Fprintf(&text, " Mentioned: %s.", strings.Join(summary.Entities, ", "))
+	}
+	return text.String()
+}
+
+// appendUnique appends value to values unless it is already there
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
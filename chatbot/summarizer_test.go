@@ -0,0 +1,89 @@
+package chatbot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+func TestSummarizer(t *testing.T) {
+	store := NewMemoryConversationStore()
+	texts := []string{"hello", "what is the price?", "mail a@example.com", "where is it?", "recent one", "recent two"}
+	history := make([]Message, len(texts))
+	for i, text := range texts {
+		history[i] = Message{Text: text, SessionID: "s1"}
+	}
+	store.Save("s1", ConversationState{History: history})
+
+	plugin := NewSummarizerPlugin(store, SummarizerConfig{Threshold: 4, Keep: 2})
+	ctx := core.NewContext(Message{Text: "recent two", SessionID: "s1"})
+	if err := plugin.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	state, _, _ := store.Load("s1")
+	if len(state.History) != 2 || state.History[0].Text != "recent one" {
+		t.Errorf("history = %v, want the 2 most recent messages", state.History)
+	}
+	want := "Earlier, 4 messages: question (2), greeting (1). Mentioned: a@example.com (email)."
+	if state.Summary == nil || state.Summary.Text != want {
+		t.Fatalf("summary = %+v, want %q", state.Summary, want)
+	}
+	if summary, _ := core.Value[string](ctx, "conversation_summary"); summary != want {
+		t.Errorf("conversation_summary = %q, want %q", summary, want)
+	}
+
+	// Below the threshold the existing summary is reported unchanged
+	ctx = core.NewContext(Message{Text: "next", SessionID: "s1"})
+	if err := plugin.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if summary, _ := core.Value[string](ctx, "conversation_summary"); summary != want {
+		t.Errorf("conversation_summary = %q, want the stored summary", summary)
+	}
+}
+
+func TestSummarizerFoldsIntoExistingSummary(t *testing.T) {
+	plugin := NewSummarizerPlugin(nil, SummarizerConfig{MaxEntities: 2})
+	summary := plugin.summarize(nil, []Message{{Text: "mail a@example.com"}, {Text: "hi"}})
+	summary = plugin.summarize(summary, []Message{{Text: "mail b@example.com and c@example.com"}})
+
+	if summary.Messages != 3 || summary.Intents["greeting"] != 1 {
+		t.Errorf("summary = %+v, want 3 messages with one greeting", summary)
+	}
+	if len(summary.Entities) != 2 || !strings.HasPrefix(summary.Entities[0], "b@example.com") {
+		t.Errorf("entities = %v, want the 2 newest", summary.Entities)
+	}
+	if !strings.HasPrefix(summary.Text, "Earlier, 3 messages") {
+		t.Errorf("text = %q, want it to count all messages", summary.Text)
+	}
+}
+
+func TestSummarizerRequiresSharedStore(t *testing.T) {
+	manager := NewContextManagerPlugin(20)
+	for _, plugin := range []*SummarizerPlugin{
+		NewSummarizerPlugin(NewMemoryConversationStore(), SummarizerConfig{}),
+		NewSummarizerPlugin(nil, SummarizerConfig{}),
+	} {
+		pipeline := core.NewPipeline(core.AbortOnError).Use(manager).Use(plugin)
+		if err := pipeline.Execute(core.NewContext(Message{Text: "hi", SessionID: "s1"})); err == nil {
+			t.Error("Execute with a store the context manager doesn't write to succeeded, want an error")
+		}
+	}
+
+	store := NewMemoryConversationStore()
+	pipeline := core.NewPipeline(core.AbortOnError).
+		Use(NewContextManagerPluginWithStore(20, store)).
+		Use(NewSummarizerPlugin(store, SummarizerConfig{Threshold: 2, Keep: 1}))
+	var ctx *core.Context
+	for _, text := range []string{"hello", "what is the price?", "thanks"} {
+		ctx = core.NewContext(Message{Text: text, SessionID: "s1"})
+		if err := pipeline.Execute(ctx); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	}
+	if _, ok := ctx.Get("conversation_summary"); !ok {
+		t.Error("no summary with a shared store")
+	}
+}
@@ -0,0 +1,38 @@
+package core
+
+// Result is the outcome of a pipeline execution run in the background.
+type Result struct {
+	Context *Context // Context the pipeline ran with
+	Data    any      // Context data after execution
+	Err     error    // Error returned by Execute
+}
+
+// ExecuteAsync runs the pipeline in a new goroutine and delivers the error returned by
+// Execute on the returned channel, which receives exactly one value and is then closed.
+//
+// The caller must not read or modify ctx until the result has been received, since the
+// pipeline's plugins mutate it concurrently. Each concurrent execution needs its own Context.
+func (p *Pipeline) ExecuteAsync(ctx *Context) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		defer close(done)
+		done <- p.Execute(ctx)
+	}()
+	return done
+}
+
+// ExecuteAsyncResult is like ExecuteAsync but delivers a Result carrying the processed data.
+// The same restrictions on using ctx apply until the Result has been received.
+func (p *Pipeline) ExecuteAsyncResult(ctx *Context) <-chan Result {
+	done := make(chan Result, 1)
+	go func() {
+		defer close(done)
+		err := p.Execute(ctx)
+		done <- Result{
+			Context: ctx,
+			Data:    ctx.GetData(),
+			Err:     err,
+		}
+	}()
+	return done
+}
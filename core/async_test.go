@@ -0,0 +1,49 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExecuteAsync(t *testing.T) {
+	release := make(chan struct{})
+	pipeline := NewPipeline(AbortOnError).Use(pluginFunc(func(ctx *Context) error {
+		<-release
+		ctx.SetData("done")
+		return nil
+	}))
+
+	done := pipeline.ExecuteAsync(NewContext(nil))
+	select {
+	case <-done:
+		t.Fatal("ExecuteAsync delivered a result before the pipeline finished")
+	default:
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("result = %v, want nil", err)
+	}
+	if _, ok := <-done; ok {
+		t.Error("channel not closed after the result")
+	}
+}
+
+func TestExecuteAsyncResult(t *testing.T) {
+	boom := errors.New("boom")
+	pipeline := NewPipeline(AbortOnError).Use(pluginFunc(func(ctx *Context) error {
+		ctx.SetData("processed")
+		return boom
+	}))
+
+	ctx := NewContext("raw")
+	select {
+	case result := <-pipeline.ExecuteAsyncResult(ctx):
+		if result.Context != ctx || result.Data != "processed" || !errors.Is(result.Err, boom) {
+			t.Errorf("result = %+v, want the processed data and boom", result)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no result delivered")
+	}
+}
@@ -0,0 +1,55 @@
+package core
+
+// branchPlugin runs one of two plugin sequences depending on a predicate evaluated at runtime.
+type branchPlugin struct {
+	predicate func(*Context) bool
+	ifTrue    *Pipeline
+	ifFalse   *Pipeline
+}
+
+// UseBranch adds a branch to the pipeline and returns the pipeline for method chaining.
+// When the branch is reached, predicate is evaluated against the Context and either the
+// ifTrue or the ifFalse plugins run in order; both share the same Context as the rest of
+// the pipeline. Branch plugins use the pipeline's error strategy, logger, tracer, budget,
+// and rollback setting as configured when UseBranch is called. Either sequence may be empty.
+func (p *Pipeline) UseBranch(predicate func(*Context) bool, ifTrue, ifFalse []Plugin) *Pipeline {
+	branch := &branchPlugin{
+		predicate: predicate,
+		ifTrue:    p.subPipeline(ifTrue),
+		ifFalse:   p.subPipeline(ifFalse),
+	}
+	return p.UseNamed("branch", branch)
+}
+
+// Execute evaluates the predicate and runs the selected plugin sequence.
+func (b *branchPlugin) Execute(ctx *Context) error {
+	if b.predicate(ctx) {
+		return b.ifTrue.run(ctx)
+	}
+	return b.ifFalse.run(ctx)
+}
+
+// HealthCheck reports the health of the plugins in both sequences.
+func (b *branchPlugin) HealthCheck() error {
+	if err := b.ifTrue.Health(); err != nil {
+		return err
+	}
+	return b.ifFalse.Health()
+}
+
+// Init initializes the plugins in both sequences.
+func (b *branchPlugin) Init() error {
+	if err := b.ifTrue.Init(); err != nil {
+		return err
+	}
+	return b.ifFalse.Init()
+}
+
+// subPipeline creates a pipeline for plugins that inherits this pipeline's settings.
+func (p *Pipeline) subPipeline(plugins []Plugin) *Pipeline {
+	sub := NewPipeline(p.errorStrategy).WithLogger(p.logger).WithTracer(p.tracer).WithRollback(p.rollback).WithExecutionTrace(p.trace).WithBudget(p.budget)
+	for _, plugin := range plugins {
+		sub.Use(plugin)
+	}
+	return sub
+}
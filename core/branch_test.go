@@ -0,0 +1,63 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestUseBranch(t *testing.T) {
+	var order []string
+	pipeline := NewPipeline(AbortOnError).
+		Use(recordPlugin(&order, "before")).
+		UseBranch(func(ctx *Context) bool {
+			flagged, _ := Value[bool](ctx, "flagged")
+			return flagged
+		},
+			[]Plugin{recordPlugin(&order, "review"), recordPlugin(&order, "notify")},
+			[]Plugin{recordPlugin(&order, "approve")},
+		).
+		Use(recordPlugin(&order, "after"))
+
+	tests := []struct {
+		flagged bool
+		want    []string
+	}{
+		{true, []string{"before", "review", "notify", "after"}},
+		{false, []string{"before", "approve", "after"}},
+	}
+	for _, test := range tests {
+		order = nil
+		ctx := NewContext(nil)
+		ctx.Set("flagged", test.flagged)
+		if err := pipeline.Execute(ctx); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		if !reflect.DeepEqual(order, test.want) {
+			t.Errorf("flagged=%v: execution order = %v, want %v", test.flagged, order, test.want)
+		}
+	}
+}
+
+func TestUseBranchPropagatesSignals(t *testing.T) {
+	var order []string
+	pipeline := NewPipeline(AbortOnError).
+		UseBranch(func(*Context) bool { return true },
+			[]Plugin{pluginFunc(func(*Context) error { return ErrSkipRemaining })}, nil).
+		Use(recordPlugin(&order, "skipped")).
+		UseFinally(recordPlugin(&order, "finally"))
+
+	if err := pipeline.Execute(NewContext(nil)); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if want := []string{"finally"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("execution order = %v, want %v", order, want)
+	}
+
+	failing := NewPipeline(AbortOnError).
+		UseBranch(func(*Context) bool { return false }, nil,
+			[]Plugin{pluginFunc(func(*Context) error { return errNotFound })})
+	if err := failing.Execute(NewContext(nil)); !errors.Is(err, errNotFound) {
+		t.Errorf("Execute = %v, want the branch plugin's error", err)
+	}
+}
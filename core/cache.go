@@ -0,0 +1,184 @@
+package core
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// CacheHitKey is the metadata key set to true when a CachePlugin served a cached result.
+const CacheHitKey = "cache_hit"
+
+// CachePlugin wraps a plugin and reuses its result for inputs with the same key.
+// On a miss the wrapped plugin runs and the data and metadata it produced are stored;
+// on a hit within the TTL they are applied to the context without running the plugin.
+// Scores the plugin added with AddScore are replayed with AddScore, so they combine with
+// the scores of other plugins instead of replacing them.
+// Failed executions, including errors collected in ContinueOnError mode, are not cached.
+type CachePlugin struct {
+	plugin Plugin
+	key    func(*Context) string
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// cacheEntry is the recorded result of one execution of the wrapped plugin.
+type cacheEntry struct {
+	data      any
+	dataSet   bool
+	metadata  map[string]any
+	scores    map[string]float64 // Scores added with AddScore, by category
+	expiresAt time.Time
+}
+
+// NewCachePlugin wraps plugin with a cache keyed by key and holding entries for ttl.
+// An empty key bypasses the cache for that execution. A ttl of zero or less disables expiry.
+func NewCachePlugin(plugin Plugin, key func(*Context) string, ttl time.Duration) *CachePlugin {
+	return &CachePlugin{
+		plugin:  plugin,
+		key:     key,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// Execute applies a fresh cached result if one exists, otherwise runs the wrapped plugin.
+func (p *CachePlugin) Execute(ctx *Context) error {
+	key := p.key(ctx)
+	if key == "" {
+		return p.plugin.Execute(ctx)
+	}
+
+	if entry, ok := p.lookup(key); ok {
+		if entry.dataSet {
+			ctx.SetData(entry.data)
+		}
+		for k, v := range entry.metadata {
+			ctx.Set(k, v)
+		}
+		for category, value := range entry.scores {
+			ctx.AddScore(category, value)
+		}
+		ctx.Set(CacheHitKey, true)
+		return nil
+	}
+
+	dataBefore := ctx.GetData()
+	metadataBefore := make(map[string]any, len(ctx.Metadata))
+	for k, v := range ctx.Metadata {
+		metadataBefore[k] = v
+	}
+	scoresBefore := ctx.Scores()
+	errorsBefore := len(ctx.Errors)
+
+	if err := p.plugin.Execute(ctx); err != nil {
+		return err
+	}
+	if len(ctx.Errors) > errorsBefore {
+		return nil
+	}
+
+	// Record only what the wrapped plugin changed, so unrelated request
+	// details such as IDs are not copied between inputs that share a key
+	entry := cacheEntry{
+		metadata: make(map[string]any),
+		scores:   make(map[string]float64),
+	}
+	if !sameValue(dataBefore, ctx.GetData()) {
+		entry.data = ctx.GetData()
+		entry.dataSet = true
+	}
+	for k, v := range ctx.Metadata {
+		if k == ScoresKey {
+			continue
+		}
+		if before, exists := metadataBefore[k]; !exists || !sameValue(before, v) {
+			entry.metadata[k] = v
+		}
+	}
+	for category, value := range ctx.Scores() {
+		if added := value - scoresBefore[category]; added != 0 {
+			entry.scores[category] = added
+		}
+	}
+	p.store(key, entry)
+	ctx.Set(CacheHitKey, false)
+	return nil
+}
+
+// Len returns the number of entries in the cache, including expired ones not yet evicted.
+func (p *CachePlugin) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.entries)
+}
+
+// Clear removes all entries from the cache.
+func (p *CachePlugin) Clear() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.entries = make(map[string]cacheEntry)
+}
+
+// Init initializes the wrapped plugin.
+func (p *CachePlugin) Init() error {
+	return initPlugin(p.plugin)
+}
+
+// HealthCheck reports the health of the wrapped plugin.
+func (p *CachePlugin) HealthCheck() error {
+	return checkHealth(p.plugin)
+}
+
+// lookup returns the entry for key if it exists and has not expired.
+// Expired entries are evicted.
+func (p *CachePlugin) lookup(key string) (cacheEntry, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.entries[key]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	if p.ttl > 0 && !p.now().Before(entry.expiresAt) {
+		delete(p.entries, key)
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+// store records entry under key and evicts any expired entries.
+func (p *CachePlugin) store(key string, entry cacheEntry) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	if p.ttl > 0 {
+		entry.expiresAt = now.Add(p.ttl)
+		for k, e := range p.entries {
+			if !now.Before(e.expiresAt) {
+				delete(p.entries, k)
+			}
+		}
+	}
+	p.entries[key] = entry
+}
+
+// sameValue reports whether a and b are the same comparable value.
+// Values that cannot be compared, such as maps and slices, are treated as different.
+func sameValue(a, b any) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	if ta != tb || !ta.Comparable() {
+		return false
+	}
+	// Interface-typed fields can still hold incomparable values
+	defer func() { recover() }()
+	return a == b
+}
@@ -0,0 +1,128 @@
+package core
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// countingPlugin counts its executions and sets "result" from the data.
+type countingPlugin struct {
+	calls int
+	fail  bool
+}
+
+func (p *countingPlugin) Execute(ctx *Context) error {
+	p.calls++
+	if p.fail {
+		return errors.New("scorer unavailable")
+	}
+	ctx.Set("result", "scored "+ctx.GetData().(string))
+	return nil
+}
+
+// dataKey is a cache key function using the string data as the key.
+func dataKey(ctx *Context) string {
+	key, _ := ctx.GetData().(string)
+	return key
+}
+
+func TestCachePluginHitAndMiss(t *testing.T) {
+	inner := &countingPlugin{}
+	cache := NewCachePlugin(inner, dataKey, 0)
+
+	first := NewContext("a")
+	first.Set("request_id", "r1")
+	cache.Execute(first)
+	if hit, _ := Value[bool](first, CacheHitKey); hit {
+		t.Error("first execution reported a cache hit")
+	}
+
+	second := NewContext("a")
+	second.Set("request_id", "r2")
+	cache.Execute(second)
+	if hit, _ := Value[bool](second, CacheHitKey); !hit {
+		t.Error("second execution reported a cache miss")
+	}
+	if result, _ := Value[string](second, "result"); result != "scored a" {
+		t.Errorf("cached result = %q, want %q", result, "scored a")
+	}
+	if id, _ := Value[string](second, "request_id"); id != "r2" {
+		t.Errorf("request_id = %q, want r2; only the plugin's changes are replayed", id)
+	}
+	if inner.calls != 1 {
+		t.Errorf("plugin ran %d times, want 1", inner.calls)
+	}
+
+	cache.Execute(NewContext("b"))
+	if inner.calls != 2 || cache.Len() != 2 {
+		t.Errorf("calls = %d, Len = %d after a new key, want 2 and 2", inner.calls, cache.Len())
+	}
+}
+
+func TestCachePluginExpiry(t *testing.T) {
+	now := time.Unix(0, 0)
+	inner := &countingPlugin{}
+	cache := NewCachePlugin(inner, dataKey, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	cache.Execute(NewContext("a"))
+	now = now.Add(59 * time.Second)
+	cache.Execute(NewContext("a"))
+	now = now.Add(time.Second)
+	cache.Execute(NewContext("a"))
+
+	if inner.calls != 2 {
+		t.Errorf("plugin ran %d times, want 2 since the entry expired", inner.calls)
+	}
+}
+
+func TestCachePluginSkipsFailures(t *testing.T) {
+	inner := &countingPlugin{fail: true}
+	cache := NewCachePlugin(inner, dataKey, 0)
+
+	if err := cache.Execute(NewContext("a")); err == nil {
+		t.Fatal("Execute succeeded, want the plugin's error")
+	}
+	if cache.Len() != 0 {
+		t.Errorf("Len = %d, want failed executions not cached", cache.Len())
+	}
+
+	// An empty key bypasses the cache
+	inner.fail = false
+	cache.Execute(NewContext(""))
+	cache.Execute(NewContext(""))
+	if inner.calls != 3 || cache.Len() != 0 {
+		t.Errorf("calls = %d, Len = %d, want every empty-key execution to run uncached", inner.calls, cache.Len())
+	}
+}
+
+func TestCachePluginReplaysScoresAdditively(t *testing.T) {
+	scorer := pluginFunc(func(ctx *Context) error {
+		ctx.AddScore("spam", 0.3)
+		return nil
+	})
+	cache := NewCachePlugin(scorer, dataKey, 0)
+
+	// An earlier plugin's scores are kept on both a miss and a hit
+	tests := []struct {
+		earlier float64
+		other   string
+	}{
+		{0.5, "profanity"},
+		{0.1, "toxicity"},
+	}
+	for _, test := range tests {
+		ctx := NewContext("a")
+		ctx.AddScore("spam", test.earlier)
+		ctx.AddScore(test.other, 0.2)
+		if err := cache.Execute(ctx); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		scores := ctx.Scores()
+		if spam := scores["spam"]; math.Abs(spam-(test.earlier+0.3)) > 1e-9 || scores[test.other] != 0.2 || len(scores) != 2 {
+			t.Errorf("scores = %v, want spam %v and %s 0.2", scores, test.earlier+0.3, test.other)
+		}
+	}
+}
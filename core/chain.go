@@ -0,0 +1,81 @@
+package core
+
+// chainPlugin runs a fixed sequence of plugins as a single plugin.
+type chainPlugin struct {
+	pipeline *Pipeline
+}
+
+// Chain returns a Plugin that runs plugins in order as one unit, so a bundle of plugins
+// can be registered under a single name and reused. The chain stops at the first error
+// and returns it as a *PipelineError indexed within the chain; the enclosing pipeline's
+// error strategy then applies to the chain as a whole. ErrSkipRemaining returned inside
+// the chain also stops the enclosing pipeline.
+func Chain(plugins ...Plugin) Plugin {
+	pipeline := NewPipeline(AbortOnError)
+	for _, plugin := range plugins {
+		pipeline.Use(plugin)
+	}
+	return &chainPlugin{
+		pipeline: pipeline,
+	}
+}
+
+// Execute runs the chained plugins in order.
+func (c *chainPlugin) Execute(ctx *Context) error {
+	return c.pipeline.run(ctx)
+}
+
+// Init initializes the chained plugins.
+func (c *chainPlugin) Init() error {
+	return c.pipeline.Init()
+}
+
+// HealthCheck reports the health of the chained plugins.
+func (c *chainPlugin) HealthCheck() error {
+	return c.pipeline.Health()
+}
+
+// Requires returns the keys required by chained plugins that are not provided by an
+// earlier plugin in the chain.
+func (c *chainPlugin) Requires() []string {
+	provided := make(map[string]bool)
+	required := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, plugin := range c.pipeline.Plugins() {
+		dependent, ok := plugin.(DependentPlugin)
+		if !ok {
+			continue
+		}
+		for _, key := range dependent.Requires() {
+			if !provided[key] && !seen[key] {
+				seen[key] = true
+				required = append(required, key)
+			}
+		}
+		for _, key := range dependent.Provides() {
+			provided[key] = true
+		}
+	}
+	return required
+}
+
+// Provides returns the keys provided by any of the chained plugins.
+func (c *chainPlugin) Provides() []string {
+	provides := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, plugin := range c.pipeline.Plugins() {
+		dependent, ok := plugin.(DependentPlugin)
+		if !ok {
+			continue
+		}
+		for _, key := range dependent.Provides() {
+			if !seen[key] {
+				seen[key] = true
+				provides = append(provides, key)
+			}
+		}
+	}
+	return provides
+}
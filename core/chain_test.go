@@ -0,0 +1,90 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// keysPlugin is a DependentPlugin that declares the keys it requires and provides.
+type keysPlugin struct {
+	requires, provides []string
+}
+
+func (p *keysPlugin) Execute(*Context) error { return nil }
+func (p *keysPlugin) Requires() []string     { return p.requires }
+func (p *keysPlugin) Provides() []string     { return p.provides }
+
+func TestChainRegisteredAsUnit(t *testing.T) {
+	var order []string
+	registry := NewRegistry()
+	if err := registry.Register("bundle", Chain(recordPlugin(&order, "a"), recordPlugin(&order, "b"))); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := registry.Register("last", recordPlugin(&order, "c")); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	pipeline, err := registry.BuildPipeline([]string{"bundle", "last"}, AbortOnError)
+	if err != nil {
+		t.Fatalf("BuildPipeline: %v", err)
+	}
+	if err := pipeline.Execute(NewContext(nil)); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+}
+
+func TestChainStopsAtFirstError(t *testing.T) {
+	var order []string
+	boom := errors.New("boom")
+	chain := Chain(
+		recordPlugin(&order, "a"),
+		pluginFunc(func(*Context) error { return boom }),
+		recordPlugin(&order, "c"),
+	)
+
+	err := NewPipeline(ContinueOnError).Use(chain).Execute(NewContext(nil))
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if want := []string{"a"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+
+	err = chain.Execute(NewContext(nil))
+	var pipelineErr *PipelineError
+	if !errors.As(err, &pipelineErr) || pipelineErr.PluginIndex != 1 || !errors.Is(err, boom) {
+		t.Errorf("err = %v, want boom at index 1 within the chain", err)
+	}
+}
+
+func TestChainSkipRemaining(t *testing.T) {
+	var order []string
+	pipeline := NewPipeline(AbortOnError).
+		Use(Chain(pluginFunc(func(*Context) error { return ErrSkipRemaining }))).
+		Use(recordPlugin(&order, "after"))
+
+	if err := pipeline.Execute(NewContext(nil)); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if len(order) != 0 {
+		t.Errorf("order = %v, want plugins after the chain skipped", order)
+	}
+}
+
+func TestChainDependencies(t *testing.T) {
+	chain := Chain(
+		&keysPlugin{requires: []string{"input"}, provides: []string{"tokens"}},
+		&keysPlugin{requires: []string{"tokens", "input", "lang"}, provides: []string{"score", "tokens"}},
+	).(DependentPlugin)
+
+	if want := []string{"input", "lang"}; !reflect.DeepEqual(chain.Requires(), want) {
+		t.Errorf("Requires = %v, want %v", chain.Requires(), want)
+	}
+	if want := []string{"tokens", "score"}; !reflect.DeepEqual(chain.Provides(), want) {
+		t.Errorf("Provides = %v, want %v", chain.Provides(), want)
+	}
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by a CircuitBreakerPlugin while its circuit is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState describes the current state of a CircuitBreakerPlugin.
+type CircuitState int
+
+const (
+	// CircuitClosed passes every call through to the wrapped plugin.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails every call immediately until the cooldown elapses.
+	CircuitOpen
+	// CircuitHalfOpen allows a single trial call to decide whether to close or reopen.
+	CircuitHalfOpen
+)
+
+// String returns a human-readable name for the circuit state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitBreakerOptions configures a CircuitBreakerPlugin.
+type CircuitBreakerOptions struct {
+	FailureThreshold int              // Consecutive failures that open the circuit (default 5)
+	Cooldown         time.Duration    // Time the circuit stays open before a trial call (default 30s)
+	Now              func() time.Time // Clock used for cooldowns (default time.Now)
+}
+
+// CircuitBreakerPlugin wraps a plugin that depends on an external service and stops
+// calling it after repeated failures. After FailureThreshold consecutive failures the
+// circuit opens and calls fail fast with ErrCircuitOpen. Once Cooldown has elapsed a
+// single trial call is allowed: success closes the circuit, failure reopens it.
+type CircuitBreakerPlugin struct {
+	plugin           Plugin
+	failureThreshold int
+	cooldown         time.Duration
+	now              func() time.Time
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	trialing bool
+}
+
+// NewCircuitBreakerPlugin wraps plugin with a circuit breaker configured by opts.
+func NewCircuitBreakerPlugin(plugin Plugin, opts CircuitBreakerOptions) *CircuitBreakerPlugin {
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = 5
+	}
+	if opts.Cooldown <= 0 {
+		opts.Cooldown = 30 * time.Second
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &CircuitBreakerPlugin{
+		plugin:           plugin,
+		failureThreshold: opts.FailureThreshold,
+		cooldown:         opts.Cooldown,
+		now:              opts.Now,
+		state:            CircuitClosed,
+	}
+}
+
+// Execute runs the wrapped plugin unless the circuit is open.
+func (p *CircuitBreakerPlugin) Execute(ctx *Context) error {
+	if err := p.before(); err != nil {
+		return err
+	}
+
+	err := p.plugin.Execute(ctx)
+	p.after(err)
+	return err
+}
+
+// State returns the current state of the circuit.
+// An open circuit whose cooldown has elapsed is reported as half-open.
+func (p *CircuitBreakerPlugin) State() CircuitState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.state == CircuitOpen && p.now().Sub(p.openedAt) >= p.cooldown {
+		return CircuitHalfOpen
+	}
+	return p.state
+}
+
+// Init initializes the wrapped plugin.
+func (p *CircuitBreakerPlugin) Init() error {
+	return initPlugin(p.plugin)
+}
+
+// HealthCheck returns ErrCircuitOpen while the circuit is open, and otherwise the health
+// of the wrapped plugin.
+func (p *CircuitBreakerPlugin) HealthCheck() error {
+	if p.State() == CircuitOpen {
+		return ErrCircuitOpen
+	}
+	return checkHealth(p.plugin)
+}
+
+// before decides whether a call may proceed, moving an expired open circuit to half-open.
+func (p *CircuitBreakerPlugin) before() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.state == CircuitOpen {
+		if p.now().Sub(p.openedAt) < p.cooldown {
+			return ErrCircuitOpen
+		}
+		p.state = CircuitHalfOpen
+	}
+
+	if p.state == CircuitHalfOpen {
+		// Only one trial call at a time; others keep failing fast
+		if p.trialing {
+			return ErrCircuitOpen
+		}
+		p.trialing = true
+	}
+
+	return nil
+}
+
+// after records the outcome of a call and updates the circuit state.
+func (p *CircuitBreakerPlugin) after(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.trialing = false
+
+	if err == nil {
+		p.state = CircuitClosed
+		p.failures = 0
+		return
+	}
+
+	p.failures++
+	if p.state == CircuitHalfOpen || p.failures >= p.failureThreshold {
+		p.state = CircuitOpen
+		p.openedAt = p.now()
+	}
+}
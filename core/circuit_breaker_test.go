@@ -0,0 +1,105 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// flakyPlugin fails while fail is set and counts its calls.
+type flakyPlugin struct {
+	fail  bool
+	calls int
+}
+
+func (p *flakyPlugin) Execute(*Context) error {
+	p.calls++
+	if p.fail {
+		return errors.New("service unavailable")
+	}
+	return nil
+}
+
+func TestCircuitBreakerTripAndRecover(t *testing.T) {
+	now := time.Unix(0, 0)
+	flaky := &flakyPlugin{fail: true}
+	breaker := NewCircuitBreakerPlugin(flaky, CircuitBreakerOptions{
+		FailureThreshold: 2,
+		Cooldown:         time.Second,
+		Now:              func() time.Time { return now },
+	})
+	ctx := NewContext(nil)
+
+	breaker.Execute(ctx)
+	if state := breaker.State(); state != CircuitClosed {
+		t.Fatalf("state after 1 failure = %v, want %v", state, CircuitClosed)
+	}
+	breaker.Execute(ctx)
+	if state := breaker.State(); state != CircuitOpen {
+		t.Fatalf("state after 2 failures = %v, want %v", state, CircuitOpen)
+	}
+
+	// Open circuits fail fast without calling the plugin
+	if err := breaker.Execute(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Execute while open = %v, want ErrCircuitOpen", err)
+	}
+	if flaky.calls != 2 {
+		t.Errorf("calls = %d, want 2", flaky.calls)
+	}
+	if err := breaker.HealthCheck(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("HealthCheck while open = %v, want ErrCircuitOpen", err)
+	}
+
+	// After the cooldown a successful trial closes the circuit
+	now = now.Add(time.Second)
+	if state := breaker.State(); state != CircuitHalfOpen {
+		t.Fatalf("state after cooldown = %v, want %v", state, CircuitHalfOpen)
+	}
+	flaky.fail = false
+	if err := breaker.Execute(ctx); err != nil {
+		t.Fatalf("trial Execute: %v", err)
+	}
+	if state := breaker.State(); state != CircuitClosed {
+		t.Errorf("state after successful trial = %v, want %v", state, CircuitClosed)
+	}
+}
+
+func TestCircuitBreakerFailedTrialReopens(t *testing.T) {
+	now := time.Unix(0, 0)
+	flaky := &flakyPlugin{fail: true}
+	breaker := NewCircuitBreakerPlugin(flaky, CircuitBreakerOptions{
+		FailureThreshold: 1,
+		Cooldown:         time.Second,
+		Now:              func() time.Time { return now },
+	})
+	ctx := NewContext(nil)
+
+	breaker.Execute(ctx)
+	now = now.Add(time.Second)
+	if err := breaker.Execute(ctx); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("trial Execute = %v, want the plugin's error", err)
+	}
+	if state := breaker.State(); state != CircuitOpen {
+		t.Errorf("state after failed trial = %v, want %v", state, CircuitOpen)
+	}
+	if err := breaker.Execute(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Execute after failed trial = %v, want ErrCircuitOpen", err)
+	}
+}
+
+func TestCircuitBreakerSuccessResetsFailures(t *testing.T) {
+	flaky := &flakyPlugin{}
+	breaker := NewCircuitBreakerPlugin(flaky, CircuitBreakerOptions{FailureThreshold: 2})
+	ctx := NewContext(nil)
+
+	flaky.fail = true
+	breaker.Execute(ctx)
+	flaky.fail = false
+	breaker.Execute(ctx)
+	flaky.fail = true
+	breaker.Execute(ctx)
+
+	if state := breaker.State(); state != CircuitClosed {
+		t.Errorf("state = %v, want %v since the failures weren't consecutive", state, CircuitClosed)
+	}
+}
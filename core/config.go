@@ -0,0 +1,105 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// PipelineConfig is the declarative description of a pipeline, written in JSON or YAML.
+// Plugins are referenced by the names they were registered under in a Registry.
+//
+// Example:
+//
+//	{
+//	  "strategy": "abort",
+//	  "plugins": ["profanity", {"name": "history", "config": {"maxHistorySize": 5}}]
+//	}
+type PipelineConfig struct {
+	Strategy string       `json:"strategy"` // "abort" (default) or "continue"
+	Plugins  []PluginSpec `json:"plugins"`  // Plugins in execution order
+}
+
+// PluginSpec references a registered plugin and its optional configuration block.
+// It may be written as a bare name string or as an object with "name" and "config".
+type PluginSpec struct {
+	Name   string          `json:"name"`
+	Config json.RawMessage `json:"config,omitempty"`
+}
+
+// UnmarshalJSON accepts either a plugin name string or a {"name", "config"} object.
+func (s *PluginSpec) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*s = PluginSpec{Name: name}
+		return nil
+	}
+
+	type plainSpec PluginSpec
+	var spec plainSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return fmt.Errorf("plugin entry must be a name or an object with a name: %w", err)
+	}
+	if spec.Name == "" {
+		return fmt.Errorf("plugin entry is missing a name")
+	}
+
+	*s = PluginSpec(spec)
+	return nil
+}
+
+// String returns the configuration name of the error strategy.
+func (s ErrorStrategy) String() string {
+	switch s {
+	case AbortOnError:
+		return "abort"
+	case ContinueOnError:
+		return "continue"
+	default:
+		return fmt.Sprintf("ErrorStrategy(%d)", int(s))
+	}
+}
+
+// ParseErrorStrategy converts a configuration name into an ErrorStrategy.
+// An empty name defaults to AbortOnError.
+func ParseErrorStrategy(name string) (ErrorStrategy, error) {
+	switch name {
+	case "", "abort":
+		return AbortOnError, nil
+	case "continue":
+		return ContinueOnError, nil
+	default:
+		return 0, fmt.Errorf("unknown error strategy %q (expected \"abort\" or \"continue\")", name)
+	}
+}
+
+// BuildFromConfig constructs a pipeline from a PipelineConfig.
+// Each plugin's config block is passed to its factory if it was registered with one.
+// Returns an error if the strategy is invalid or any plugin cannot be created.
+func (r *Registry) BuildFromConfig(config PipelineConfig) (*Pipeline, error) {
+	strategy, err := ParseErrorStrategy(config.Strategy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pipeline config: %w", err)
+	}
+
+	pipeline := NewPipeline(strategy)
+	for _, spec := range config.Plugins {
+		plugin, err := r.Create(spec.Name, spec.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build pipeline: %w", err)
+		}
+		pipeline.UseNamed(spec.Name, plugin)
+	}
+
+	return pipeline, nil
+}
+
+// BuildFromJSON reads a JSON-encoded PipelineConfig and constructs the pipeline it describes.
+func (r *Registry) BuildFromJSON(reader io.Reader) (*Pipeline, error) {
+	var config PipelineConfig
+	if err := json.NewDecoder(reader).Decode(&config); err != nil {
+		return nil, fmt.Errorf("invalid pipeline config: %w", err)
+	}
+
+	return r.BuildFromConfig(config)
+}
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildFromJSON(t *testing.T) {
+	var order []string
+	registry := NewRegistry()
+	registry.Register("first", recordPlugin(&order, "first"))
+	registry.Register("second", recordPlugin(&order, "second"))
+
+	pipeline, err := registry.BuildFromJSON(strings.NewReader(`{"strategy": "continue", "plugins": ["second", {"name": "first"}]}`))
+	if err != nil {
+		t.Fatalf("BuildFromJSON: %v", err)
+	}
+	if pipeline.errorStrategy != ContinueOnError {
+		t.Errorf("strategy = %v, want %v", pipeline.errorStrategy, ContinueOnError)
+	}
+	if got, want := pipeline.PluginNames(), []string{"second", "first"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PluginNames = %v, want %v", got, want)
+	}
+
+	if err := pipeline.Execute(NewContext(nil)); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if want := []string{"second", "first"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("execution order = %v, want %v", order, want)
+	}
+}
+
+func TestBuildFromJSONErrors(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("noop", pluginFunc(func(*Context) error { return nil }))
+
+	tests := map[string]string{
+		"unknown strategy": `{"strategy": "retry", "plugins": ["noop"]}`,
+		"unknown plugin":   `{"plugins": ["missing"]}`,
+		"missing name":     `{"plugins": [{"config": {}}]}`,
+		"invalid JSON":     `{"plugins": [`,
+	}
+	for name, config := range tests {
+		if _, err := registry.BuildFromJSON(strings.NewReader(config)); err == nil {
+			t.Errorf("%s: BuildFromJSON succeeded, want an error", name)
+		}
+	}
+}
+
+func TestParseErrorStrategy(t *testing.T) {
+	for _, strategy := range []ErrorStrategy{AbortOnError, ContinueOnError} {
+		parsed, err := ParseErrorStrategy(strategy.String())
+		if err != nil || parsed != strategy {
+			t.Errorf("ParseErrorStrategy(%q) = %v, %v, want %v", strategy.String(), parsed, err, strategy)
+		}
+	}
+	if parsed, err := ParseErrorStrategy(""); err != nil || parsed != AbortOnError {
+		t.Errorf("ParseErrorStrategy(\"\") = %v, %v, want %v", parsed, err, AbortOnError)
+	}
+	if _, err := ParseErrorStrategy("retry"); err == nil {
+		t.Error("ParseErrorStrategy(\"retry\") succeeded, want an error")
+	}
+}
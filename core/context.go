@@ -1,5 +1,12 @@
 package core
 
+import "time"
+
+// DryRunKey is the metadata key that marks a context as a dry run.
+// Plugins with side effects should check IsDryRun and skip them while still
+// populating their results, so callers can inspect what would have happened.
+const DryRunKey = "dry_run"
+
 // Context carries data and metadata through the pipeline.
 // It supports both stateless transformations and stateful processing.
 type Context struct {
@@ -7,6 +14,8 @@ type Context struct {
 	Metadata map[string]any // Additional metadata
 	Errors   []error        // Collected errors (for continue-on-error mode)
 	state    map[string]any // Internal state for stateful pipelines
+	trace    []TraceEntry   // Execution trace, when enabled on the pipeline
+	started  time.Time      // Start of the outermost pipeline execution, for budgets
 }
 
 // NewContext creates a new Context with the given data.
@@ -59,3 +68,22 @@ func (c *Context) GetState(key string) (any, bool) {
 func (c *Context) AddError(err error) {
 	c.Errors = append(c.Errors, err)
 }
+
+// IsDryRun reports whether the context is marked as a dry run.
+func (c *Context) IsDryRun() bool {
+	value, exists := c.Metadata[DryRunKey]
+	if !exists {
+		return false
+	}
+	dryRun, ok := value.(bool)
+	return ok && dryRun
+}
+
+// WithData returns a new Context for data that shares c's internal state.
+// Metadata and errors start empty. This lets a long-lived session, such as a
+// WebSocket connection, keep state across separate pipeline executions.
+func (c *Context) WithData(data any) *Context {
+	ctx := NewContext(data)
+	ctx.state = c.state
+	return ctx
+}
@@ -0,0 +1,52 @@
+package core
+
+import "fmt"
+
+// DependencyError reports a plugin whose required key is not provided by an earlier plugin.
+type DependencyError struct {
+	PluginIndex int
+	Plugin      string
+	Key         string
+}
+
+// Error implements the error interface.
+func (e *DependencyError) Error() string {
+	return fmt.Sprintf("plugin %d (%s) requires %q, which no earlier plugin provides", e.PluginIndex, e.Plugin, e.Key)
+}
+
+// Validate checks that every key required by a DependentPlugin is provided by a plugin
+// earlier in the pipeline. Plugins that do not implement DependentPlugin are assumed to
+// require and provide nothing. Returns a *DependencyError for a single problem or a
+// *MultiError of them when there are several.
+func (p *Pipeline) Validate() error {
+	provided := make(map[string]bool)
+	problems := make([]error, 0)
+
+	for i, s := range p.stages {
+		dependent, ok := s.plugin.(DependentPlugin)
+		if !ok {
+			continue
+		}
+		for _, key := range dependent.Requires() {
+			if !provided[key] {
+				problems = append(problems, &DependencyError{
+					PluginIndex: i,
+					Plugin:      s.displayName(),
+					Key:         key,
+				})
+			}
+		}
+		for _, key := range dependent.Provides() {
+			provided[key] = true
+		}
+	}
+
+	switch len(problems) {
+	case 0:
+		return nil
+	case 1:
+		return problems[0]
+	default:
+		return &MultiError{Errors: problems}
+	}
+}
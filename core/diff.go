@@ -0,0 +1,70 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Diff is one difference between an expected and an actual Context, as reported by
+// DiffResults.
+type Diff struct {
+	Path     string // "data", "metadata.<key>" or "errors[<index>]"
+	Expected any    // nil when only the actual Context has the value
+	Actual   any    // nil when only the expected Context has the value
+}
+
+// String describes the difference, such as `metadata.spam_score: expected 0.3, got 0.6`.
+func (d Diff) String() string {
+	return fmt.Sprintf("%s: expected %v, got %v", d.Path, d.Expected, d.Actual)
+}
+
+// DiffResults compares the results of two executions, such as a golden baseline and the
+// output of a changed pipeline, and returns their differences: the data, each metadata key,
+// and the collected errors, which are compared by message. Values are compared with
+// reflect.DeepEqual, so pointers are followed. Metadata differences are sorted by key, and
+// an empty result means the contexts match. Values that change on every run, such as
+// timestamps, should be removed from both contexts first.
+func DiffResults(expected, actual *Context) []Diff {
+	var diffs []Diff
+
+	if !reflect.DeepEqual(expected.Data, actual.Data) {
+		diffs = append(diffs, Diff{Path: "data", Expected: expected.Data, Actual: actual.Data})
+	}
+
+	keys := make([]string, 0, len(expected.Metadata))
+	for key := range expected.Metadata {
+		keys = append(keys, key)
+	}
+	for key := range actual.Metadata {
+		if _, ok := expected.Metadata[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		expectedValue, inExpected := expected.Metadata[key]
+		actualValue, inActual := actual.Metadata[key]
+		if inExpected != inActual || !reflect.DeepEqual(expectedValue, actualValue) {
+			diffs = append(diffs, Diff{Path: "metadata." + key, Expected: expectedValue, Actual: actualValue})
+		}
+	}
+
+	for i := 0; i < max(len(expected.Errors), len(actual.Errors)); i++ {
+		expectedMessage := errorMessage(expected.Errors, i)
+		actualMessage := errorMessage(actual.Errors, i)
+		if expectedMessage != actualMessage {
+			diffs = append(diffs, Diff{Path: fmt.Sprintf("errors[%d]", i), Expected: expectedMessage, Actual: actualMessage})
+		}
+	}
+
+	return diffs
+}
+
+// errorMessage returns the message of errs[i], or nil when there is no such error.
+func errorMessage(errs []error, i int) any {
+	if i >= len(errs) || errs[i] == nil {
+		return nil
+	}
+	return errs[i].Error()
+}
@@ -0,0 +1,53 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDiffResults(t *testing.T) {
+	expected := NewContext("hello")
+	expected.Set("spam_score", 0.3)
+	expected.Set("tags", []string{"greeting"})
+	expected.Set("removed", true)
+	expected.AddError(errors.New("timeout"))
+
+	actual := NewContext("hello")
+	actual.Set("spam_score", 0.6)
+	actual.Set("tags", []string{"greeting"})
+	actual.Set("added", 1)
+	actual.AddError(errors.New("timeout"))
+	actual.AddError(errors.New("rate limited"))
+
+	want := []Diff{
+		{Path: "metadata.added", Expected: nil, Actual: 1},
+		{Path: "metadata.removed", Expected: true, Actual: nil},
+		{Path: "metadata.spam_score", Expected: 0.3, Actual: 0.6},
+		{Path: "errors[1]", Expected: nil, Actual: "rate limited"},
+	}
+	if got := DiffResults(expected, actual); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffResults = %v, want %v", got, want)
+	}
+
+	if got := want[2].String(); got != "metadata.spam_score: expected 0.3, got 0.6" {
+		t.Errorf("String = %q", got)
+	}
+}
+
+func TestDiffResultsMatch(t *testing.T) {
+	expected := NewContext([]string{"a"})
+	expected.Set("score", 0.5)
+	actual := NewContext([]string{"a"})
+	actual.Set("score", 0.5)
+
+	if diffs := DiffResults(expected, actual); len(diffs) != 0 {
+		t.Errorf("DiffResults = %v, want none", diffs)
+	}
+
+	actual.Data = []string{"b"}
+	diffs := DiffResults(expected, actual)
+	if len(diffs) != 1 || diffs[0].Path != "data" {
+		t.Errorf("DiffResults = %v, want a data difference", diffs)
+	}
+}
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrSkipRemaining can be returned by a plugin to stop the pipeline cleanly.
+// The remaining plugins are not executed, except those added with UseFinally, and
+// Execute returns nil. Plugins may wrap it (fmt.Errorf("...: %w", ErrSkipRemaining));
+// it is detected with errors.Is.
+var ErrSkipRemaining = errors.New("skip remaining plugins")
+
+// ErrQuarantined is returned by Execute when a ContinueOnError execution collected enough
+// errors to be quarantined. The Context's result should not be used.
+var ErrQuarantined = errors.New("content quarantined after repeated plugin errors")
+
+// MultiError aggregates the errors collected during a ContinueOnError execution.
+// It supports errors.Is and errors.As across every collected error.
+type MultiError struct {
+	Errors []error
+}
+
+// Error implements the error interface.
+func (e *MultiError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the collected errors for error chain support.
+func (e *MultiError) Unwrap() []error {
+	return e.Errors
+}
+
+// PanicError is returned in place of a plugin's error when the plugin panics.
+// The pipeline wraps it in a PipelineError and applies its error strategy as usual.
+type PanicError struct {
+	Value any    // The value passed to panic
+	Stack []byte // The stack trace of the panicking goroutine
+}
+
+// Error implements the error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("plugin panicked: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, for error chain support.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
@@ -0,0 +1,45 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+// errNotFound is a sentinel error for testing error chains.
+var errNotFound = errors.New("not found")
+
+func TestExecuteCollect(t *testing.T) {
+	pipeline := NewPipeline(ContinueOnError).
+		Use(pluginFunc(func(*Context) error { return errNotFound })).
+		Use(pluginFunc(func(*Context) error { return nil })).
+		Use(pluginFunc(func(*Context) error { return errors.New("timeout") }))
+
+	ctx := NewContext(nil)
+	ctx.AddError(errors.New("earlier"))
+	err := pipeline.ExecuteCollect(ctx)
+
+	var multi *MultiError
+	if !errors.As(err, &multi) {
+		t.Fatalf("ExecuteCollect = %v, want a *MultiError", err)
+	}
+	if len(multi.Errors) != 2 {
+		t.Fatalf("collected %d errors, want 2; errors from before the call must be excluded", len(multi.Errors))
+	}
+	if !errors.Is(err, errNotFound) {
+		t.Error("errors.Is(err, errNotFound) = false, want true")
+	}
+	var pipelineErr *PipelineError
+	if !errors.As(multi.Errors[1], &pipelineErr) || pipelineErr.PluginIndex != 2 {
+		t.Errorf("second error = %v, want a PipelineError for plugin 2", multi.Errors[1])
+	}
+	if want := "plugin 0 failed: not found; plugin 2 failed: timeout"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestExecuteCollectWithoutErrors(t *testing.T) {
+	pipeline := NewPipeline(ContinueOnError).Use(pluginFunc(func(*Context) error { return nil }))
+	if err := pipeline.ExecuteCollect(NewContext(nil)); err != nil {
+		t.Errorf("ExecuteCollect = %v, want nil", err)
+	}
+}
@@ -0,0 +1,78 @@
+package core
+
+import "sync"
+
+// Executor runs a pipeline over many Contexts with a fixed number of worker goroutines.
+// Contexts are processed concurrently, at most workers at a time, and their results are
+// delivered on Results in completion order.
+//
+// Typical use submits from one goroutine while consuming Results in another, then calls
+// Close once everything is submitted; Results is closed after the last result is delivered.
+// Workers block until their results are received, so Results must be drained.
+type Executor struct {
+	pipeline *Pipeline
+	jobs     chan *Context
+	results  chan Result
+	wg       sync.WaitGroup
+	once     sync.Once
+}
+
+// NewExecutor creates an Executor that runs pipeline with the given number of workers and
+// starts the workers. A workers value of zero or less uses a single worker.
+func NewExecutor(pipeline *Pipeline, workers int) *Executor {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	e := &Executor{
+		pipeline: pipeline,
+		jobs:     make(chan *Context),
+		results:  make(chan Result, workers),
+	}
+
+	e.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go e.work()
+	}
+
+	// Close results once every worker has exited
+	go func() {
+		e.wg.Wait()
+		close(e.results)
+	}()
+
+	return e
+}
+
+// Submit queues ctx for execution, blocking until a worker is free to take it.
+// The Context must not be used by the caller until its Result has been received.
+// Submit must not be called after Close.
+func (e *Executor) Submit(ctx *Context) {
+	e.jobs <- ctx
+}
+
+// Results returns the channel on which execution results are delivered.
+func (e *Executor) Results() <-chan Result {
+	return e.results
+}
+
+// Close stops accepting submissions. Contexts already submitted are still processed,
+// after which Results is closed. Close is safe to call more than once.
+func (e *Executor) Close() {
+	e.once.Do(func() {
+		close(e.jobs)
+	})
+}
+
+// work executes submitted Contexts until the executor is closed.
+func (e *Executor) work() {
+	defer e.wg.Done()
+	for ctx := range e.jobs {
+		err := e.pipeline.Execute(ctx)
+		e.results <- Result{
+			Context: ctx,
+			Data:    ctx.GetData(),
+			Err:     err,
+		}
+	}
+}
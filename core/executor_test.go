@@ -0,0 +1,73 @@
+package core
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExecutorBoundsConcurrency(t *testing.T) {
+	const workers, jobs = 3, 20
+	var running, peak atomic.Int32
+	pipeline := NewPipeline(AbortOnError).Use(pluginFunc(func(ctx *Context) error {
+		now := running.Add(1)
+		for {
+			old := peak.Load()
+			if now <= old || peak.CompareAndSwap(old, now) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+		ctx.SetData(ctx.GetData().(int) * 2)
+		return nil
+	}))
+
+	executor := NewExecutor(pipeline, workers)
+	go func() {
+		for i := 0; i < jobs; i++ {
+			executor.Submit(NewContext(i))
+		}
+		executor.Close()
+	}()
+
+	sum := 0
+	count := 0
+	for result := range executor.Results() {
+		if result.Err != nil {
+			t.Errorf("result error: %v", result.Err)
+		}
+		sum += result.Data.(int)
+		count++
+	}
+
+	if count != jobs {
+		t.Errorf("results = %d, want %d", count, jobs)
+	}
+	if want := jobs * (jobs - 1); sum != want {
+		t.Errorf("sum of results = %d, want %d", sum, want)
+	}
+	if got := peak.Load(); got > workers {
+		t.Errorf("peak concurrency = %d, want at most %d", got, workers)
+	}
+}
+
+func TestExecutorErrorsAndClose(t *testing.T) {
+	boom := errors.New("boom")
+	executor := NewExecutor(NewPipeline(AbortOnError).Use(pluginFunc(func(*Context) error { return boom })), 0)
+
+	go func() {
+		executor.Submit(NewContext(nil))
+		executor.Close()
+		executor.Close()
+	}()
+
+	result, ok := <-executor.Results()
+	if !ok || !errors.Is(result.Err, boom) {
+		t.Errorf("result = %+v, want boom", result)
+	}
+	if _, ok := <-executor.Results(); ok {
+		t.Error("Results not closed after Close")
+	}
+}
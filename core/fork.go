@@ -0,0 +1,110 @@
+package core
+
+import "reflect"
+
+// Fork returns an independent copy of the Context for running another pipeline on the
+// same input, such as an A/B variant. Data, metadata, and state are deep-copied: maps,
+// slices, arrays, pointers, and exported struct fields are copied recursively, so changes
+// made through the fork never reach the original. Unexported struct fields, channels, and
+// functions are shared. Collected errors and the execution trace belong to the original's
+// execution and start empty in the fork. Unlike WithData, the fork doesn't share state.
+func (c *Context) Fork() *Context {
+	visited := make(map[visitKey]reflect.Value)
+	fork := NewContext(deepCopy(c.Data, visited))
+	for key, value := range c.Metadata {
+		fork.Metadata[key] = deepCopy(value, visited)
+	}
+	for key, value := range c.state {
+		fork.state[key] = deepCopy(value, visited)
+	}
+	return fork
+}
+
+// visitKey identifies a pointer or map already copied. The type is part of the key since a
+// struct and its first field share an address.
+type visitKey struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+// deepCopy returns a deep copy of value. visited maps the pointers already copied to their
+// copies, so shared and cyclic references are preserved.
+func deepCopy(value any, visited map[visitKey]reflect.Value) any {
+	if value == nil {
+		return nil
+	}
+	return copyValue(reflect.ValueOf(value), visited).Interface()
+}
+
+// copyValue returns a deep copy of v with the same type.
+func copyValue(v reflect.Value, visited map[visitKey]reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		key := visitKey{v.Type(), v.Pointer()}
+		if copied, ok := visited[key]; ok {
+			return copied
+		}
+		copied := reflect.New(v.Type().Elem())
+		visited[key] = copied
+		copied.Elem().Set(copyValue(v.Elem(), visited))
+		return copied
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		key := visitKey{v.Type(), v.Pointer()}
+		if copied, ok := visited[key]; ok {
+			return copied
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		visited[key] = copied
+		iter := v.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(copyValue(iter.Key(), visited), copyValue(iter.Value(), visited))
+		}
+		return copied
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(copyValue(v.Index(i), visited))
+		}
+		return copied
+
+	case reflect.Array:
+		copied := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(copyValue(v.Index(i), visited))
+		}
+		return copied
+
+	case reflect.Struct:
+		// Copying the whole struct carries over unexported fields, which can't be set
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				copied.Field(i).Set(copyValue(v.Field(i), visited))
+			}
+		}
+		return copied
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(copyValue(v.Elem(), visited))
+		return copied
+
+	default:
+		return v
+	}
+}
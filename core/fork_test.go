@@ -0,0 +1,65 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+// forkNode is a linked value used to check that Fork copies pointers and cycles.
+type forkNode struct {
+	Name  string
+	Tags  []string
+	Next  *forkNode
+	label string
+}
+
+func TestContextForkIsIndependent(t *testing.T) {
+	shared := &forkNode{Name: "shared", Tags: []string{"a"}, label: "kept"}
+	ctx := NewContext(map[string]any{"node": shared, "list": []int{1, 2}})
+	ctx.Set("again", shared)
+	ctx.Set("nested", map[string][]string{"k": {"v"}})
+	ctx.SetState("counter", &forkNode{Name: "state"})
+	ctx.AddError(errors.New("earlier failure"))
+
+	fork := ctx.Fork()
+	data := fork.GetData().(map[string]any)
+	node := data["node"].(*forkNode)
+	node.Name = "changed"
+	node.Tags[0] = "changed"
+	data["list"].([]int)[0] = 99
+	fork.Metadata["nested"].(map[string][]string)["k"][0] = "changed"
+	state, _ := fork.GetState("counter")
+	state.(*forkNode).Name = "changed"
+
+	if shared.Name != "shared" || shared.Tags[0] != "a" {
+		t.Errorf("original node = %+v, want it unchanged", shared)
+	}
+	if ctx.GetData().(map[string]any)["list"].([]int)[0] != 1 {
+		t.Error("original slice changed through the fork")
+	}
+	if ctx.Metadata["nested"].(map[string][]string)["k"][0] != "v" {
+		t.Error("original metadata changed through the fork")
+	}
+	if original, _ := ctx.GetState("counter"); original.(*forkNode).Name != "state" {
+		t.Error("original state changed through the fork")
+	}
+
+	// A pointer reached twice is copied once, and unexported fields carry over
+	if again, _ := fork.Get("again"); again.(*forkNode) != node || node.label != "kept" {
+		t.Errorf("fork node = %+v, want one shared copy with its unexported field", node)
+	}
+	if len(fork.Errors) != 0 {
+		t.Errorf("fork errors = %v, want none", fork.Errors)
+	}
+}
+
+func TestContextForkCycle(t *testing.T) {
+	node := &forkNode{Name: "a"}
+	node.Next = &forkNode{Name: "b", Next: node}
+
+	fork := NewContext(node).Fork()
+	copied := fork.GetData().(*forkNode)
+	if copied == node || copied.Next.Next != copied {
+		t.Error("cycle not preserved in the fork")
+	}
+}
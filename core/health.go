@@ -0,0 +1,55 @@
+package core
+
+import "fmt"
+
+// HealthError reports a plugin whose health check failed.
+type HealthError struct {
+	PluginIndex int
+	Plugin      string
+	Err         error
+}
+
+// Error implements the error interface.
+func (e *HealthError) Error() string {
+	return fmt.Sprintf("plugin %d (%s) is unhealthy: %v", e.PluginIndex, e.Plugin, e.Err)
+}
+
+// Unwrap returns the underlying error for error chain support.
+func (e *HealthError) Unwrap() error {
+	return e.Err
+}
+
+// Health runs the health check of every plugin that implements HealthChecker.
+// Plugins that do not implement it are assumed healthy. Returns a *HealthError for a
+// single failing plugin or a *MultiError of them when there are several.
+func (p *Pipeline) Health() error {
+	problems := make([]error, 0)
+
+	for i, s := range p.stages {
+		if err := checkHealth(s.plugin); err != nil {
+			problems = append(problems, &HealthError{
+				PluginIndex: i,
+				Plugin:      s.displayName(),
+				Err:         err,
+			})
+		}
+	}
+
+	switch len(problems) {
+	case 0:
+		return nil
+	case 1:
+		return problems[0]
+	default:
+		return &MultiError{Errors: problems}
+	}
+}
+
+// checkHealth runs plugin's health check, if it has one.
+func checkHealth(plugin Plugin) error {
+	checker, ok := plugin.(HealthChecker)
+	if !ok {
+		return nil
+	}
+	return checker.HealthCheck()
+}
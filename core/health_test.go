@@ -0,0 +1,41 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+// healthPlugin is a plugin whose health check returns err.
+type healthPlugin struct {
+	err error
+}
+
+func (p *healthPlugin) Execute(*Context) error { return nil }
+
+func (p *healthPlugin) HealthCheck() error { return p.err }
+
+func TestPipelineHealth(t *testing.T) {
+	storeDown := errors.New("store unreachable")
+	pipeline := NewPipeline(AbortOnError).
+		Use(pluginFunc(func(*Context) error { return nil })).
+		UseNamed("store", &healthPlugin{})
+	if err := pipeline.Health(); err != nil {
+		t.Fatalf("Health = %v, want nil", err)
+	}
+
+	pipeline.UseNamed("remote", &healthPlugin{err: storeDown})
+	var healthErr *HealthError
+	err := pipeline.Health()
+	if !errors.As(err, &healthErr) || healthErr.PluginIndex != 2 || healthErr.Plugin != "remote" {
+		t.Fatalf("Health = %v, want a HealthError for plugin 2 (remote)", err)
+	}
+	if !errors.Is(err, storeDown) {
+		t.Error("errors.Is does not see the plugin's health error")
+	}
+
+	pipeline.UseBranch(func(*Context) bool { return true }, []Plugin{&healthPlugin{err: storeDown}}, nil)
+	var multi *MultiError
+	if err := pipeline.Health(); !errors.As(err, &multi) || len(multi.Errors) != 2 {
+		t.Errorf("Health = %v, want a MultiError including the branch's plugin", err)
+	}
+}
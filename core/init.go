@@ -0,0 +1,63 @@
+package core
+
+import "fmt"
+
+// InitError reports a plugin that failed to initialize.
+type InitError struct {
+	PluginIndex int
+	Plugin      string
+	Err         error
+}
+
+// Error implements the error interface.
+func (e *InitError) Error() string {
+	return fmt.Sprintf("plugin %d (%s) failed to initialize: %v", e.PluginIndex, e.Plugin, e.Err)
+}
+
+// Unwrap returns the underlying error for error chain support.
+func (e *InitError) Unwrap() error {
+	return e.Err
+}
+
+// Init initializes every plugin that implements Initializer, in pipeline order, and is
+// meant to be called once at startup. It stops at the first failure and returns an
+// *InitError naming the plugin. Plugins that initialized successfully are marked ready
+// and skipped by later calls, so Init can be retried after a failure.
+func (p *Pipeline) Init() error {
+	for i := range p.stages {
+		s := &p.stages[i]
+		if s.ready {
+			continue
+		}
+		if err := initPlugin(s.plugin); err != nil {
+			p.logger.Error("plugin failed to initialize", "index", i, "plugin", s.displayName(), "error", err)
+			return &InitError{
+				PluginIndex: i,
+				Plugin:      s.displayName(),
+				Err:         err,
+			}
+		}
+		s.ready = true
+	}
+	return nil
+}
+
+// Ready reports whether every plugin has been initialized by Init. A pipeline whose
+// plugins don't implement Initializer still needs Init to be called once.
+func (p *Pipeline) Ready() bool {
+	for _, s := range p.stages {
+		if !s.ready {
+			return false
+		}
+	}
+	return true
+}
+
+// initPlugin runs plugin's initialization, if it has one.
+func initPlugin(plugin Plugin) error {
+	initializer, ok := plugin.(Initializer)
+	if !ok {
+		return nil
+	}
+	return initializer.Init()
+}
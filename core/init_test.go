@@ -0,0 +1,65 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+// initPluginStub is a plugin whose Init fails until failures reaches zero.
+type initPluginStub struct {
+	failures int
+	calls    int
+}
+
+func (p *initPluginStub) Execute(*Context) error { return nil }
+
+func (p *initPluginStub) Init() error {
+	p.calls++
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("config not loaded")
+	}
+	return nil
+}
+
+func TestPipelineInit(t *testing.T) {
+	first := &initPluginStub{}
+	flaky := &initPluginStub{failures: 1}
+	pipeline := NewPipeline(AbortOnError).
+		Use(first).
+		Use(pluginFunc(func(*Context) error { return nil })).
+		UseNamed("flaky", flaky)
+	if pipeline.Ready() {
+		t.Fatal("Ready before Init")
+	}
+
+	var initErr *InitError
+	if err := pipeline.Init(); !errors.As(err, &initErr) || initErr.PluginIndex != 2 || initErr.Plugin != "flaky" {
+		t.Fatalf("Init = %v, want an InitError for plugin 2 (flaky)", err)
+	}
+	if pipeline.Ready() {
+		t.Error("Ready after a failed Init")
+	}
+
+	// A retry skips the plugins already initialized
+	if err := pipeline.Init(); err != nil {
+		t.Fatalf("retried Init = %v, want nil", err)
+	}
+	if !pipeline.Ready() || first.calls != 1 || flaky.calls != 2 {
+		t.Errorf("Ready = %v, Init calls = %d and %d, want ready after 1 and 2 calls", pipeline.Ready(), first.calls, flaky.calls)
+	}
+}
+
+func TestPipelineInitNested(t *testing.T) {
+	inner := &initPluginStub{failures: 1}
+	pipeline := NewPipeline(AbortOnError).
+		Use(Chain(inner)).
+		UseBranch(func(*Context) bool { return true }, nil, []Plugin{&initPluginStub{}})
+
+	if err := pipeline.Init(); err == nil {
+		t.Fatal("Init succeeded, want the chained plugin's error")
+	}
+	if err := pipeline.Init(); err != nil || inner.calls != 2 {
+		t.Errorf("retried Init = %v after %d calls, want nil after 2", err, inner.calls)
+	}
+}
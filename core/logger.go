@@ -0,0 +1,64 @@
+package core
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Logger receives structured events from pipeline execution.
+// Fields are alternating key/value pairs, following the log/slog convention.
+type Logger interface {
+	Debug(msg string, fields ...any)
+	Info(msg string, fields ...any)
+	Warn(msg string, fields ...any)
+	Error(msg string, fields ...any)
+}
+
+// nopLogger discards all events.
+type nopLogger struct{}
+
+func (nopLogger) Debug(msg string, fields ...any) {}
+func (nopLogger) Info(msg string, fields ...any)  {}
+func (nopLogger) Warn(msg string, fields ...any)  {}
+func (nopLogger) Error(msg string, fields ...any) {}
+
+// NopLogger returns a Logger that discards all events. It is the pipeline default.
+func NopLogger() Logger {
+	return nopLogger{}
+}
+
+// SlogLogger adapts a *slog.Logger to the Logger interface.
+type SlogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger creates a Logger that writes events to the given slog logger.
+// A nil logger uses slog.Default().
+func NewSlogLogger(logger *slog.Logger) *SlogLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SlogLogger{
+		logger: logger,
+	}
+}
+
+// Debug logs an event at debug level.
+func (l *SlogLogger) Debug(msg string, fields ...any) {
+	l.logger.Log(context.Background(), slog.LevelDebug, msg, fields...)
+}
+
+// Info logs an event at info level.
+func (l *SlogLogger) Info(msg string, fields ...any) {
+	l.logger.Log(context.Background(), slog.LevelInfo, msg, fields...)
+}
+
+// Warn logs an event at warn level.
+func (l *SlogLogger) Warn(msg string, fields ...any) {
+	l.logger.Log(context.Background(), slog.LevelWarn, msg, fields...)
+}
+
+// Error logs an event at error level.
+func (l *SlogLogger) Error(msg string, fields ...any) {
+	l.logger.Log(context.Background(), slog.LevelError, msg, fields...)
+}
@@ -0,0 +1,97 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// logEvent is an event received by recordingLogger.
+type logEvent struct {
+	level  string
+	msg    string
+	fields []any
+}
+
+// recordingLogger keeps every event it receives.
+type recordingLogger struct {
+	events []logEvent
+}
+
+func (l *recordingLogger) Debug(msg string, fields ...any) { l.add("debug", msg, fields) }
+func (l *recordingLogger) Info(msg string, fields ...any)  { l.add("info", msg, fields) }
+func (l *recordingLogger) Warn(msg string, fields ...any)  { l.add("warn", msg, fields) }
+func (l *recordingLogger) Error(msg string, fields ...any) { l.add("error", msg, fields) }
+
+func (l *recordingLogger) add(level, msg string, fields []any) {
+	l.events = append(l.events, logEvent{level: level, msg: msg, fields: fields})
+}
+
+// messages returns "level: msg" for each event.
+func (l *recordingLogger) messages() []string {
+	messages := make([]string, len(l.events))
+	for i, event := range l.events {
+		messages[i] = event.level + ": " + event.msg
+	}
+	return messages
+}
+
+func TestPipelineLogsPluginEvents(t *testing.T) {
+	logger := &recordingLogger{}
+	pipeline := NewPipeline(ContinueOnError).
+		WithLogger(logger).
+		UseNamed("ok", pluginFunc(func(*Context) error { return nil })).
+		UseNamed("broken", pluginFunc(func(*Context) error { return errors.New("boom") }))
+
+	pipeline.Execute(NewContext(nil))
+
+	want := []string{
+		"debug: plugin started",
+		"debug: plugin finished",
+		"debug: plugin started",
+		"warn: plugin failed",
+	}
+	if got := logger.messages(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+
+	failed := logger.events[3].fields
+	if failed[0] != "index" || failed[1] != 1 || failed[2] != "plugin" || failed[3] != "broken" {
+		t.Errorf("failure fields = %v, want index 1 and plugin broken first", failed)
+	}
+}
+
+func TestPipelineLogsAbortAsError(t *testing.T) {
+	logger := &recordingLogger{}
+	pipeline := NewPipeline(AbortOnError).
+		WithLogger(logger).
+		Use(pluginFunc(func(*Context) error { return errors.New("boom") }))
+
+	pipeline.Execute(NewContext(nil))
+	if last := logger.events[len(logger.events)-1]; last.level != "error" || last.msg != "plugin failed" {
+		t.Errorf("last event = %+v, want an error-level plugin failure", last)
+	}
+}
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewSlogLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	NewPipeline(AbortOnError).
+		WithLogger(logger).
+		UseNamed("noop", pluginFunc(func(*Context) error { return nil })).
+		Execute(NewContext(nil))
+
+	if out := buf.String(); !strings.Contains(out, `msg="plugin finished"`) || !strings.Contains(out, "plugin=noop") {
+		t.Errorf("slog output = %q, want a plugin finished event for noop", out)
+	}
+}
+
+func TestWithLoggerNil(t *testing.T) {
+	pipeline := NewPipeline(AbortOnError).WithLogger(nil).Use(pluginFunc(func(*Context) error { return nil }))
+	if err := pipeline.Execute(NewContext(nil)); err != nil {
+		t.Errorf("Execute with a nil logger: %v", err)
+	}
+}
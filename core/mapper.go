@@ -0,0 +1,43 @@
+package core
+
+import "fmt"
+
+// MapperPlugin converts the context's primary data into another form, such as decoding
+// a generic map from an HTTP request into a domain struct, so later plugins receive the
+// type they expect.
+type MapperPlugin struct {
+	fn func(any) (any, error)
+}
+
+// NewMapperPlugin creates a plugin that replaces the context's data with fn's result.
+// If fn fails the data is left unchanged and the error is wrapped with the type of the
+// data being mapped; errors.Is and errors.As still see fn's error.
+func NewMapperPlugin(fn func(any) (any, error)) *MapperPlugin {
+	return &MapperPlugin{
+		fn: fn,
+	}
+}
+
+// Map creates a MapperPlugin from a typed conversion function. The plugin fails if the
+// context's data is not an In.
+func Map[In, Out any](fn func(In) (Out, error)) *MapperPlugin {
+	return NewMapperPlugin(func(data any) (any, error) {
+		in, ok := data.(In)
+		if !ok {
+			var zero In
+			return nil, fmt.Errorf("expected %T, got %T", zero, data)
+		}
+		return fn(in)
+	})
+}
+
+// Execute maps the context's data and stores the result.
+func (p *MapperPlugin) Execute(ctx *Context) error {
+	data := ctx.GetData()
+	mapped, err := p.fn(data)
+	if err != nil {
+		return fmt.Errorf("failed to map %T: %w", data, err)
+	}
+	ctx.SetData(mapped)
+	return nil
+}
@@ -0,0 +1,41 @@
+package core
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestMapConvertsData(t *testing.T) {
+	ctx := NewContext("42")
+	if err := Map(strconv.Atoi).Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if got, ok := ctx.GetData().(int); !ok || got != 42 {
+		t.Errorf("data = %#v, want 42", ctx.GetData())
+	}
+}
+
+func TestMapWrongTypeKeepsData(t *testing.T) {
+	ctx := NewContext(7)
+	err := Map(strconv.Atoi).Execute(ctx)
+	if err == nil {
+		t.Fatal("Execute succeeded for an int, want a type error")
+	}
+	if ctx.GetData() != 7 {
+		t.Errorf("data = %#v, want it left unchanged", ctx.GetData())
+	}
+}
+
+func TestMapperWrapsError(t *testing.T) {
+	ctx := NewContext("not a number")
+	err := Map(strconv.Atoi).Execute(ctx)
+
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) {
+		t.Fatalf("err = %v, want a wrapped *strconv.NumError", err)
+	}
+	if ctx.GetData() != "not a number" {
+		t.Errorf("data = %#v, want it left unchanged", ctx.GetData())
+	}
+}
@@ -0,0 +1,63 @@
+package core
+
+// NamespaceSeparator joins a namespace and a key into a flat metadata key.
+const NamespaceSeparator = "."
+
+// NamespacedKey returns the flat metadata key for key within namespace ns,
+// for example "sentiment.score". An empty namespace returns key unchanged.
+func NamespacedKey(ns, key string) string {
+	if ns == "" {
+		return key
+	}
+	return ns + NamespaceSeparator + key
+}
+
+// SetNS stores a value in the metadata under key within namespace ns.
+// Plugins that reuse common key names use namespaces to avoid overwriting each other.
+func (c *Context) SetNS(ns, key string, value any) {
+	c.Set(NamespacedKey(ns, key), value)
+}
+
+// GetNS retrieves a value from the metadata by key within namespace ns.
+// Returns the value and a boolean indicating whether the key exists.
+func (c *Context) GetNS(ns, key string) (any, bool) {
+	return c.Get(NamespacedKey(ns, key))
+}
+
+// Namespace is a view of a Context's metadata restricted to one namespace.
+// Values it stores are visible through flat Get with the prefixed key.
+type Namespace struct {
+	ctx  *Context
+	name string
+}
+
+// Namespace returns a view of the metadata within namespace ns.
+func (c *Context) Namespace(ns string) Namespace {
+	return Namespace{ctx: c, name: ns}
+}
+
+// Name returns the namespace name.
+func (n Namespace) Name() string {
+	return n.name
+}
+
+// Key returns the flat metadata key for key within the namespace.
+func (n Namespace) Key(key string) string {
+	return NamespacedKey(n.name, key)
+}
+
+// Set stores a value under key within the namespace.
+func (n Namespace) Set(key string, value any) {
+	n.ctx.SetNS(n.name, key, value)
+}
+
+// Get retrieves a value by key within the namespace.
+// Returns the value and a boolean indicating whether the key exists.
+func (n Namespace) Get(key string) (any, bool) {
+	return n.ctx.GetNS(n.name, key)
+}
+
+// Delete removes key from the namespace.
+func (n Namespace) Delete(key string) {
+	delete(n.ctx.Metadata, n.Key(key))
+}
@@ -0,0 +1,40 @@
+package core
+
+import "testing"
+
+func TestContextNamespaces(t *testing.T) {
+	ctx := NewContext(nil)
+	ctx.Set("score", "flat")
+	ctx.SetNS("sentiment", "score", 0.8)
+	ctx.Namespace("toxicity").Set("score", 0.1)
+
+	if v, _ := ctx.Get("score"); v != "flat" {
+		t.Errorf("flat score = %v, want it untouched by namespaced writes", v)
+	}
+	if v, _ := ctx.GetNS("sentiment", "score"); v != 0.8 {
+		t.Errorf("sentiment score = %v, want 0.8", v)
+	}
+	if v, _ := ctx.Get("toxicity.score"); v != 0.1 {
+		t.Errorf("flat toxicity.score = %v, want the namespaced value", v)
+	}
+	if v, _ := ctx.GetNS("", "score"); v != "flat" {
+		t.Errorf("empty namespace = %v, want the flat key", v)
+	}
+}
+
+func TestNamespaceView(t *testing.T) {
+	ctx := NewContext(nil)
+	ns := ctx.Namespace("intent")
+	if ns.Name() != "intent" || ns.Key("type") != "intent.type" {
+		t.Errorf("Name, Key = %q, %q, want intent, intent.type", ns.Name(), ns.Key("type"))
+	}
+
+	ns.Set("type", "greeting")
+	if v, ok := ns.Get("type"); !ok || v != "greeting" {
+		t.Errorf("Get = %v, %v, want greeting", v, ok)
+	}
+	ns.Delete("type")
+	if _, ok := ctx.GetNS("intent", "type"); ok {
+		t.Error("key still set after Delete")
+	}
+}
@@ -1,7 +1,10 @@
 package core
 
 import (
+	"errors"
 	"fmt"
+	"runtime/debug"
+	"time"
 )
 
 // ErrorStrategy defines how the pipeline handles plugin errors.
@@ -16,50 +19,365 @@ const (
 
 // Pipeline orchestrates the execution of plugins in sequential order.
 type Pipeline struct {
-	plugins       []Plugin
+	stages        []stage
 	errorStrategy ErrorStrategy
+	logger        Logger
+	tracer        Tracer
+	rollback      bool
+	trace         bool
+	budget        time.Duration
+
+	quarantineThreshold int
+	onQuarantine        func(*Context)
+}
+
+// stage is a plugin in the pipeline together with the name it was added under.
+type stage struct {
+	name     string
+	plugin   Plugin
+	optional bool // errors are collected even under AbortOnError
+	final    bool // runs even after a plugin returns ErrSkipRemaining
+	ready    bool // Init succeeded, so it isn't run again
+}
+
+// displayName returns the stage name, falling back to the plugin's type name.
+func (s stage) displayName() string {
+	if s.name != "" {
+		return s.name
+	}
+	return fmt.Sprintf("%T", s.plugin)
 }
 
 // NewPipeline creates a new Pipeline with the specified error handling strategy.
 func NewPipeline(strategy ErrorStrategy) *Pipeline {
 	return &Pipeline{
-		plugins:       make([]Plugin, 0),
+		stages:        make([]stage, 0),
 		errorStrategy: strategy,
+		logger:        NopLogger(),
+		tracer:        NopTracer(),
 	}
 }
 
+// WithLogger sets the logger that receives plugin start, finish, and error events
+// and returns the pipeline for method chaining. A nil logger disables logging.
+func (p *Pipeline) WithLogger(logger Logger) *Pipeline {
+	if logger == nil {
+		logger = NopLogger()
+	}
+	p.logger = logger
+	return p
+}
+
+// WithTracer sets the tracer that receives a span for each plugin execution and returns
+// the pipeline for method chaining. Spans are named after the plugin, carry its index in
+// the "plugin.index" attribute, and record the plugin's error if it fails.
+// A nil tracer disables tracing.
+func (p *Pipeline) WithTracer(tracer Tracer) *Pipeline {
+	if tracer == nil {
+		tracer = NopTracer()
+	}
+	p.tracer = tracer
+	return p
+}
+
+// WithRollback enables or disables automatic rollback and returns the pipeline for
+// method chaining. With rollback enabled, the Context is snapshotted before each plugin
+// and restored if the plugin fails, so a failed plugin leaves no partial changes behind.
+// Errors collected in ContinueOnError mode are still recorded.
+func (p *Pipeline) WithRollback(enabled bool) *Pipeline {
+	p.rollback = enabled
+	return p
+}
+
+// WithExecutionTrace enables or disables the execution trace and returns the pipeline
+// for method chaining. With the trace enabled, each plugin execution is appended to the
+// Context's Trace with its name, duration, and error, preserving the order of execution.
+func (p *Pipeline) WithExecutionTrace(enabled bool) *Pipeline {
+	p.trace = enabled
+	return p
+}
+
+// WithBudget sets a time budget for each execution and returns the pipeline for method
+// chaining. Before each plugin, the time elapsed since the execution started is compared
+// with the budget; once it is exceeded, plugins added with UseOptional are skipped while
+// the others still run, so a slow execution degrades instead of growing later.
+// Nested pipelines, such as branches, measure from the start of the outermost execution
+// on the same Context. A budget of zero or less disables it.
+func (p *Pipeline) WithBudget(budget time.Duration) *Pipeline {
+	p.budget = budget
+	return p
+}
+
+// WithQuarantine routes failing content to onQuarantine and returns the pipeline for
+// method chaining. When an execution ends with at least threshold errors collected in
+// the Context by that execution, Execute calls onQuarantine with the Context and returns ErrQuarantined
+// instead of nil. This only applies in ContinueOnError mode: AbortOnError stops at the
+// first error, and errors of plugins added with UseOptional never quarantine. A threshold of zero or less or a nil callback disables quarantine.
+func (p *Pipeline) WithQuarantine(threshold int, onQuarantine func(*Context)) *Pipeline {
+	p.quarantineThreshold = threshold
+	p.onQuarantine = onQuarantine
+	return p
+}
+
 // Use adds a plugin to the pipeline and returns the pipeline for method chaining.
 // This enables fluent interface for pipeline construction.
 func (p *Pipeline) Use(plugin Plugin) *Pipeline {
-	p.plugins = append(p.plugins, plugin)
+	return p.UseNamed("", plugin)
+}
+
+// UseOptional adds a plugin whose errors never stop the pipeline and returns the pipeline
+// for method chaining. Under AbortOnError a failing optional plugin is treated as under
+// ContinueOnError: its error is collected in the Context as a PipelineError and the next
+// plugin runs, while errors from other plugins still abort. Collected errors count toward
+// quarantine and are reported by ExecuteCollect. Optional plugins are also the ones
+// skipped once the budget set by WithBudget is exceeded.
+func (p *Pipeline) UseOptional(plugin Plugin) *Pipeline {
+	p.stages = append(p.stages, stage{plugin: plugin, optional: true})
+	return p
+}
+
+// UseFinally adds a plugin that runs even when an earlier plugin stops the pipeline with
+// ErrSkipRemaining, and returns the pipeline for method chaining. This lets a plugin that
+// decides early skip the expensive work in between while a finalizing plugin, such as one
+// that writes the result, still runs. Errors abort the pipeline as usual.
+func (p *Pipeline) UseFinally(plugin Plugin) *Pipeline {
+	p.stages = append(p.stages, stage{plugin: plugin, final: true})
 	return p
 }
 
+// UseNamed adds a plugin under the given name and returns the pipeline for method chaining.
+// The name is reported by PluginNames; pipelines built by a Registry use the registered names.
+func (p *Pipeline) UseNamed(name string, plugin Plugin) *Pipeline {
+	p.stages = append(p.stages, stage{name: name, plugin: plugin})
+	return p
+}
+
+// InsertAt inserts a plugin at the given index, shifting later plugins back.
+// An index equal to the number of plugins appends to the end.
+// Returns an error without modifying the pipeline if the index is out of range.
+func (p *Pipeline) InsertAt(index int, plugin Plugin) error {
+	return p.InsertNamedAt(index, "", plugin)
+}
+
+// InsertNamedAt inserts a plugin under the given name at the given index, like InsertAt.
+// The name is reported by PluginNames.
+func (p *Pipeline) InsertNamedAt(index int, name string, plugin Plugin) error {
+	if index < 0 || index > len(p.stages) {
+		return fmt.Errorf("insert index %d out of range [0, %d]", index, len(p.stages))
+	}
+
+	p.stages = append(p.stages, stage{})
+	copy(p.stages[index+1:], p.stages[index:])
+	p.stages[index] = stage{name: name, plugin: plugin}
+	return nil
+}
+
+// RemoveAt removes the plugin at the given index, preserving the order of the others.
+// Returns an error without modifying the pipeline if the index is out of range.
+func (p *Pipeline) RemoveAt(index int) error {
+	if index < 0 || index >= len(p.stages) {
+		return fmt.Errorf("remove index %d out of range [0, %d)", index, len(p.stages))
+	}
+
+	p.stages = append(p.stages[:index], p.stages[index+1:]...)
+	return nil
+}
+
+// Len returns the number of plugins in the pipeline.
+func (p *Pipeline) Len() int {
+	return len(p.stages)
+}
+
+// Plugins returns the plugins in execution order.
+// The returned slice is a copy; modifying it does not affect the pipeline.
+func (p *Pipeline) Plugins() []Plugin {
+	plugins := make([]Plugin, len(p.stages))
+	for i, s := range p.stages {
+		plugins[i] = s.plugin
+	}
+	return plugins
+}
+
+// PluginNames returns the name of each plugin in execution order.
+// Plugins added without a name are reported by their type name.
+func (p *Pipeline) PluginNames() []string {
+	names := make([]string, len(p.stages))
+	for i, s := range p.stages {
+		names[i] = s.displayName()
+	}
+	return names
+}
+
+// Clone returns a copy of the pipeline that can be extended independently.
+// The plugin list and settings are copied, but the plugins themselves are
+// shared by reference, so stateful plugins are shared between the original and the clone.
+func (p *Pipeline) Clone() *Pipeline {
+	stages := make([]stage, len(p.stages))
+	copy(stages, p.stages)
+	return &Pipeline{
+		stages:        stages,
+		errorStrategy: p.errorStrategy,
+		logger:        p.logger,
+		tracer:        p.tracer,
+		rollback:      p.rollback,
+		trace:         p.trace,
+		budget:        p.budget,
+
+		quarantineThreshold: p.quarantineThreshold,
+		onQuarantine:        p.onQuarantine,
+	}
+}
+
 // Execute runs all plugins in the pipeline sequentially.
 // The behavior depends on the error strategy:
-// - AbortOnError: stops at the first error and returns it wrapped with context
-// - ContinueOnError: continues executing all plugins and collects errors in Context
+//   - AbortOnError: stops at the first error and returns it wrapped with context,
+//     except for plugins added with UseOptional, whose errors are collected in Context
+//   - ContinueOnError: continues executing all plugins and collects errors in Context
+//
+// A plugin that panics fails with a *PanicError. A plugin returning ErrSkipRemaining
+// stops execution without an error, after running the plugins added with UseFinally.
+// Once a configured budget is exceeded, optional plugins are skipped, and when quarantine
+// is configured and enough errors were collected, Execute returns ErrQuarantined.
 func (p *Pipeline) Execute(ctx *Context) error {
-	for i, plugin := range p.plugins {
-		err := plugin.Execute(ctx)
+	// Errors already on a reused Context don't count towards quarantine
+	before := len(ctx.Errors)
+	err := p.run(ctx)
+	if errors.Is(err, ErrSkipRemaining) {
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Under AbortOnError only optional plugins collect errors, so quarantine doesn't apply
+	quarantine := p.errorStrategy == ContinueOnError && p.onQuarantine != nil && p.quarantineThreshold > 0
+	if collected := len(ctx.Errors) - before; quarantine && collected >= p.quarantineThreshold {
+		p.logger.Warn("pipeline quarantined", "errors", collected)
+		p.onQuarantine(ctx)
+		return ErrQuarantined
+	}
+	return nil
+}
+
+// run executes the plugins and returns ErrSkipRemaining if a plugin stopped the pipeline early.
+// Composite plugins use it to propagate the signal to the enclosing pipeline.
+func (p *Pipeline) run(ctx *Context) error {
+	// Only the outermost execution starts the clock, so nested pipelines share its budget
+	if ctx.started.IsZero() {
+		ctx.started = time.Now()
+		defer func() { ctx.started = time.Time{} }()
+	}
+
+	stopped := false
+	for i, s := range p.stages {
+		if stopped && !s.final {
+			continue
+		}
+		name := s.displayName()
+		if s.optional && p.budget > 0 {
+			if elapsed := time.Since(ctx.started); elapsed > p.budget {
+				p.logger.Info("plugin skipped over budget", "index", i, "plugin", name, "elapsed", elapsed)
+				continue
+			}
+		}
+		p.logger.Debug("plugin started", "index", i, "plugin", name)
+
+		var snapshot Snapshot
+		if p.rollback {
+			snapshot = ctx.Snapshot()
+		}
+
+		span := p.tracer.StartSpan(name)
+		span.SetAttribute("plugin.index", i)
+
+		start := time.Now()
+		err := executePlugin(s.plugin, ctx)
+		duration := time.Since(start)
+
+		if err != nil && !errors.Is(err, ErrSkipRemaining) {
+			span.RecordError(err)
+		}
+		span.End()
+
+		if p.trace {
+			ctx.addTrace(TraceEntry{PluginIndex: i, Plugin: name, Duration: duration, Err: err})
+		}
+
+		if errors.Is(err, ErrSkipRemaining) {
+			p.logger.Info("pipeline stopped early", "index", i, "plugin", name, "duration", duration)
+			stopped = true
+			continue
+		}
+
 		if err != nil {
-			if p.errorStrategy == AbortOnError {
+			if p.rollback {
+				ctx.Restore(snapshot)
+			}
+			if p.errorStrategy == AbortOnError && !s.optional {
+				p.logger.Error("plugin failed", "index", i, "plugin", name, "duration", duration, "error", err)
 				// Wrap error with plugin context and return immediately
 				return &PipelineError{
 					PluginIndex: i,
 					Err:         err,
 				}
 			}
+			p.logger.Warn("plugin failed", "index", i, "plugin", name, "duration", duration, "error", err)
 			// ContinueOnError: collect error and continue
 			ctx.AddError(&PipelineError{
 				PluginIndex: i,
 				Err:         err,
 			})
+			continue
+		}
+
+		p.logger.Debug("plugin finished", "index", i, "plugin", name, "duration", duration)
+	}
+
+	if stopped {
+		return ErrSkipRemaining
+	}
+	return nil
+}
+
+// executePlugin runs plugin, converting a panic into a *PanicError so one faulty plugin
+// can't crash the process.
+func executePlugin(plugin Plugin, ctx *Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{
+				Value: r,
+				Stack: debug.Stack(),
+			}
 		}
+	}()
+	return plugin.Execute(ctx)
+}
+
+// ExecuteCollect runs the pipeline like Execute, but also reports errors collected in
+// ContinueOnError mode. If any plugin failed during this execution, the returned error
+// is a *MultiError wrapping each PipelineError; errors already in the Context before
+// the call are not included.
+func (p *Pipeline) ExecuteCollect(ctx *Context) error {
+	collected := len(ctx.Errors)
+	if err := p.Execute(ctx); err != nil {
+		return err
+	}
+
+	if len(ctx.Errors) > collected {
+		errs := make([]error, len(ctx.Errors)-collected)
+		copy(errs, ctx.Errors[collected:])
+		return &MultiError{Errors: errs}
 	}
 	return nil
 }
 
+// DryRun marks the context as a dry run and executes the pipeline.
+// Plugins that honor the convention compute their results without performing side effects.
+func (p *Pipeline) DryRun(ctx *Context) error {
+	ctx.Set(DryRunKey, true)
+	return p.Execute(ctx)
+}
+
 // PipelineError wraps plugin errors with context about which plugin failed.
 type PipelineError struct {
 	PluginIndex int
@@ -0,0 +1,363 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// pluginFunc adapts a function to the Plugin interface.
+type pluginFunc func(*Context) error
+
+func (f pluginFunc) Execute(ctx *Context) error { return f(ctx) }
+
+// recordPlugin returns a plugin that appends name to *order when it runs.
+func recordPlugin(order *[]string, name string) Plugin {
+	return pluginFunc(func(*Context) error {
+		*order = append(*order, name)
+		return nil
+	})
+}
+
+func TestPipelineDryRun(t *testing.T) {
+	var dryRun bool
+	pipeline := NewPipeline(AbortOnError).Use(pluginFunc(func(ctx *Context) error {
+		dryRun = ctx.IsDryRun()
+		return nil
+	}))
+
+	if err := pipeline.DryRun(NewContext(nil)); err != nil {
+		t.Fatalf("DryRun: %v", err)
+	}
+	if !dryRun {
+		t.Error("IsDryRun = false during DryRun, want true")
+	}
+
+	if err := pipeline.Execute(NewContext(nil)); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if dryRun {
+		t.Error("IsDryRun = true during Execute, want false")
+	}
+}
+
+func TestContextIsDryRunIgnoresNonBool(t *testing.T) {
+	ctx := NewContext(nil)
+	ctx.Set(DryRunKey, "yes")
+	if ctx.IsDryRun() {
+		t.Error("IsDryRun = true for a non-bool value, want false")
+	}
+}
+
+func TestPipelineClone(t *testing.T) {
+	var order []string
+	original := NewPipeline(ContinueOnError).
+		WithExecutionTrace(true).
+		UseNamed("a", recordPlugin(&order, "a"))
+
+	clone := original.Clone()
+	clone.UseNamed("b", recordPlugin(&order, "b"))
+	original.UseNamed("c", recordPlugin(&order, "c"))
+
+	if got, want := original.PluginNames(), []string{"a", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("original PluginNames = %v, want %v", got, want)
+	}
+	if got, want := clone.PluginNames(), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("clone PluginNames = %v, want %v", got, want)
+	}
+	if clone.errorStrategy != ContinueOnError || !clone.trace {
+		t.Error("clone did not copy the pipeline settings")
+	}
+
+	ctx := NewContext(nil)
+	if err := clone.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("clone execution order = %v, want %v", order, want)
+	}
+	if len(ctx.Trace()) != 2 {
+		t.Errorf("trace has %d entries, want 2", len(ctx.Trace()))
+	}
+}
+
+func TestPipelineInsertAndRemove(t *testing.T) {
+	var order []string
+	pipeline := NewPipeline(AbortOnError).
+		Use(recordPlugin(&order, "a")).
+		Use(recordPlugin(&order, "c"))
+
+	if err := pipeline.InsertAt(1, recordPlugin(&order, "b")); err != nil {
+		t.Fatalf("InsertAt(1): %v", err)
+	}
+	if err := pipeline.InsertAt(3, recordPlugin(&order, "d")); err != nil {
+		t.Fatalf("InsertAt(3): %v", err)
+	}
+	if err := pipeline.RemoveAt(0); err != nil {
+		t.Fatalf("RemoveAt(0): %v", err)
+	}
+
+	pipeline.Execute(NewContext(nil))
+	if want := []string{"b", "c", "d"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("execution order = %v, want %v", order, want)
+	}
+}
+
+func TestPipelineInsertAndRemoveOutOfRange(t *testing.T) {
+	pipeline := NewPipeline(AbortOnError).Use(pluginFunc(func(*Context) error { return nil }))
+
+	for _, index := range []int{-1, 2} {
+		if err := pipeline.InsertAt(index, pluginFunc(func(*Context) error { return nil })); err == nil {
+			t.Errorf("InsertAt(%d) succeeded, want an error", index)
+		}
+	}
+	for _, index := range []int{-1, 1} {
+		if err := pipeline.RemoveAt(index); err == nil {
+			t.Errorf("RemoveAt(%d) succeeded, want an error", index)
+		}
+	}
+	if pipeline.Len() != 1 {
+		t.Errorf("Len = %d after failed edits, want 1", pipeline.Len())
+	}
+}
+
+func TestPipelineIntrospection(t *testing.T) {
+	first := pluginFunc(func(*Context) error { return nil })
+	pipeline := NewPipeline(AbortOnError).
+		UseNamed("first", first).
+		Use(&flakyPlugin{})
+	if err := pipeline.InsertNamedAt(1, "inserted", pluginFunc(func(*Context) error { return nil })); err != nil {
+		t.Fatalf("InsertNamedAt: %v", err)
+	}
+	pipeline.InsertAt(0, &configPlugin{})
+
+	if pipeline.Len() != 4 {
+		t.Errorf("Len = %d, want 4", pipeline.Len())
+	}
+	want := []string{"*core.configPlugin", "first", "inserted", "*core.flakyPlugin"}
+	if got := pipeline.PluginNames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("PluginNames = %v, want %v", got, want)
+	}
+
+	// Plugins returns a copy
+	plugins := pipeline.Plugins()
+	plugins[0] = nil
+	if pipeline.Plugins()[0] == nil {
+		t.Error("modifying the Plugins result changed the pipeline")
+	}
+}
+
+func TestPipelineSkipRemaining(t *testing.T) {
+	var order []string
+	pipeline := NewPipeline(AbortOnError).
+		Use(recordPlugin(&order, "a")).
+		Use(pluginFunc(func(*Context) error {
+			order = append(order, "stop")
+			return fmt.Errorf("decided early: %w", ErrSkipRemaining)
+		})).
+		Use(recordPlugin(&order, "skipped")).
+		UseFinally(recordPlugin(&order, "finally"))
+
+	if err := pipeline.Execute(NewContext(nil)); err != nil {
+		t.Fatalf("Execute = %v, want nil", err)
+	}
+	if want := []string{"a", "stop", "finally"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("execution order = %v, want %v", order, want)
+	}
+}
+
+func TestPipelineSkipRemainingNotCollected(t *testing.T) {
+	pipeline := NewPipeline(ContinueOnError).
+		Use(pluginFunc(func(*Context) error { return ErrSkipRemaining }))
+
+	ctx := NewContext(nil)
+	if err := pipeline.ExecuteCollect(ctx); err != nil {
+		t.Errorf("ExecuteCollect = %v, want nil", err)
+	}
+	if len(ctx.Errors) != 0 {
+		t.Errorf("collected errors = %v, want none", ctx.Errors)
+	}
+}
+
+func TestPipelineQuarantine(t *testing.T) {
+	failing := pluginFunc(func(*Context) error { return fmt.Errorf("boom") })
+
+	var quarantined *Context
+	pipeline := NewPipeline(ContinueOnError).
+		WithQuarantine(2, func(ctx *Context) { quarantined = ctx }).
+		Use(failing).
+		Use(failing)
+
+	ctx := NewContext(nil)
+	if err := pipeline.Execute(ctx); !errors.Is(err, ErrQuarantined) {
+		t.Fatalf("Execute = %v, want ErrQuarantined", err)
+	}
+	if quarantined != ctx {
+		t.Error("onQuarantine not called with the Context")
+	}
+
+	quarantined = nil
+	pipeline = NewPipeline(ContinueOnError).
+		WithQuarantine(2, func(ctx *Context) { quarantined = ctx }).
+		Use(failing)
+	if err := pipeline.Execute(NewContext(nil)); err != nil {
+		t.Fatalf("Execute below the threshold = %v, want nil", err)
+	}
+	if quarantined != nil {
+		t.Error("onQuarantine called below the threshold")
+	}
+}
+
+func TestPipelineQuarantineCountsOwnErrors(t *testing.T) {
+	failing := pluginFunc(func(*Context) error { return fmt.Errorf("boom") })
+	quarantined := false
+	pipeline := NewPipeline(ContinueOnError).
+		WithQuarantine(2, func(*Context) { quarantined = true }).
+		Use(failing)
+
+	// Errors left on a reused Context by earlier executions don't count
+	ctx := NewContext(nil)
+	ctx.AddError(errors.New("earlier"))
+	ctx.AddError(errors.New("earlier"))
+	if err := pipeline.Execute(ctx); err != nil {
+		t.Fatalf("Execute = %v, want nil", err)
+	}
+	if err := pipeline.Execute(ctx); err != nil {
+		t.Fatalf("second Execute = %v, want nil", err)
+	}
+	if quarantined {
+		t.Error("quarantined for errors of earlier executions")
+	}
+}
+
+func TestPipelineQuarantineDisabled(t *testing.T) {
+	failing := pluginFunc(func(*Context) error { return fmt.Errorf("boom") })
+
+	for _, pipeline := range []*Pipeline{
+		NewPipeline(ContinueOnError).WithQuarantine(0, func(*Context) {}).Use(failing),
+		NewPipeline(ContinueOnError).WithQuarantine(1, nil).Use(failing),
+		// Quarantine only applies in ContinueOnError mode, even to optional plugins' errors
+		NewPipeline(AbortOnError).WithQuarantine(1, func(*Context) {}).UseOptional(failing),
+	} {
+		if err := pipeline.Execute(NewContext(nil)); err != nil {
+			t.Errorf("Execute = %v, want nil with quarantine disabled", err)
+		}
+	}
+}
+
+func TestPipelineRecoversPanic(t *testing.T) {
+	cause := errors.New("bad state")
+	panicking := pluginFunc(func(*Context) error { panic(cause) })
+
+	err := NewPipeline(AbortOnError).Use(panicking).Execute(NewContext(nil))
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("Execute = %v, want a *PanicError", err)
+	}
+	if panicErr.Value != cause || len(panicErr.Stack) == 0 {
+		t.Errorf("PanicError = %v with %d stack bytes, want the panic value and a stack", panicErr.Value, len(panicErr.Stack))
+	}
+	if !errors.Is(err, cause) {
+		t.Error("errors.Is does not see the error passed to panic")
+	}
+
+	var order []string
+	ctx := NewContext(nil)
+	pipeline := NewPipeline(ContinueOnError).
+		Use(pluginFunc(func(*Context) error { panic("not an error") })).
+		Use(recordPlugin(&order, "next"))
+	if err := pipeline.Execute(ctx); err != nil {
+		t.Fatalf("Execute = %v, want nil in ContinueOnError mode", err)
+	}
+	if len(ctx.Errors) != 1 || !errors.As(ctx.Errors[0], &panicErr) || panicErr.Value != "not an error" {
+		t.Errorf("collected errors = %v, want one PanicError", ctx.Errors)
+	}
+	if want := []string{"next"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("execution order = %v, want %v", order, want)
+	}
+}
+
+func TestPipelineUseOptional(t *testing.T) {
+	var order []string
+	failure := errors.New("enrichment unavailable")
+	pipeline := NewPipeline(AbortOnError).
+		UseOptional(pluginFunc(func(*Context) error { return failure })).
+		Use(recordPlugin(&order, "required"))
+
+	ctx := NewContext(nil)
+	err := pipeline.ExecuteCollect(ctx)
+	if want := []string{"required"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("execution order = %v, want %v", order, want)
+	}
+	var multi *MultiError
+	if !errors.As(err, &multi) || !errors.Is(err, failure) {
+		t.Errorf("ExecuteCollect = %v, want the optional error collected", err)
+	}
+
+	// Errors from other plugins still abort
+	order = nil
+	pipeline = NewPipeline(AbortOnError).
+		UseOptional(recordPlugin(&order, "optional")).
+		Use(pluginFunc(func(*Context) error { return failure })).
+		Use(recordPlugin(&order, "skipped"))
+	var pipelineErr *PipelineError
+	if err := pipeline.Execute(NewContext(nil)); !errors.As(err, &pipelineErr) || pipelineErr.PluginIndex != 1 {
+		t.Errorf("Execute = %v, want the required plugin's error", err)
+	}
+	if want := []string{"optional"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("execution order = %v, want %v", order, want)
+	}
+}
+
+func TestPipelineBudget(t *testing.T) {
+	slow := pluginFunc(func(*Context) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	})
+
+	var order []string
+	pipeline := NewPipeline(AbortOnError).
+		WithBudget(5 * time.Millisecond).
+		UseOptional(recordPlugin(&order, "early")).
+		Use(slow).
+		UseOptional(recordPlugin(&order, "skipped")).
+		Use(recordPlugin(&order, "required"))
+	if err := pipeline.Execute(NewContext(nil)); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if want := []string{"early", "required"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("execution order = %v, want %v", order, want)
+	}
+}
+
+func TestPipelineBudgetNested(t *testing.T) {
+	var order []string
+	inner := NewPipeline(AbortOnError).
+		WithBudget(5 * time.Millisecond).
+		UseOptional(recordPlugin(&order, "inner optional"))
+	outer := NewPipeline(AbortOnError).
+		Use(pluginFunc(func(*Context) error {
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		})).
+		Use(inner)
+
+	// The nested pipeline measures from the start of the outer execution
+	ctx := NewContext(nil)
+	if err := outer.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if len(order) != 0 {
+		t.Errorf("execution order = %v, want the nested optional plugin skipped", order)
+	}
+
+	// Each execution starts a new clock
+	if err := inner.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if want := []string{"inner optional"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("execution order = %v, want %v", order, want)
+	}
+}
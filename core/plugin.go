@@ -5,3 +5,29 @@ package core
 type Plugin interface {
 	Execute(ctx *Context) error
 }
+
+// DependentPlugin is implemented by plugins that declare the metadata keys they read and
+// write. Pipeline.Validate uses the declarations to check plugin order.
+type DependentPlugin interface {
+	Plugin
+	// Requires returns the keys that must be set by an earlier plugin.
+	Requires() []string
+	// Provides returns the keys this plugin sets.
+	Provides() []string
+}
+
+// Initializer is implemented by plugins that need expensive one-time setup, such as
+// loading a lexicon, that should happen at startup rather than on the first request.
+// Pipeline.Init runs it. A plugin shared by several pipelines is initialized by each of
+// them, so Init should only do its setup once.
+type Initializer interface {
+	// Init prepares the plugin and returns an error if it can't be used.
+	Init() error
+}
+
+// HealthChecker is implemented by plugins that depend on an external service, such as a
+// store or a remote scorer. Pipeline.Health uses it to report whether the pipeline can serve.
+type HealthChecker interface {
+	// HealthCheck returns an error if a dependency of the plugin is unavailable.
+	HealthCheck() error
+}
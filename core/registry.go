@@ -1,21 +1,29 @@
 package core
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 )
 
+// PluginFactory creates a plugin instance from an optional JSON configuration block.
+// The config is nil when the pipeline specification does not provide one.
+type PluginFactory func(config json.RawMessage) (Plugin, error)
+
 // Registry manages plugin registration and retrieval with thread-safe storage.
 // It allows plugins to be registered by name and retrieved for pipeline construction.
 type Registry struct {
-	plugins map[string]Plugin
-	mu      sync.RWMutex
+	plugins   map[string]Plugin
+	factories map[string]PluginFactory
+	mu        sync.RWMutex
 }
 
-// NewRegistry creates a new Registry with an empty plugin map.
+// NewRegistry creates a new Registry with empty plugin and factory maps.
 func NewRegistry() *Registry {
 	return &Registry{
-		plugins: make(map[string]Plugin),
+		plugins:   make(map[string]Plugin),
+		factories: make(map[string]PluginFactory),
 	}
 }
 
@@ -25,7 +33,7 @@ func (r *Registry) Register(name string, plugin Plugin) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.plugins[name]; exists {
+	if r.isRegistered(name) {
 		return fmt.Errorf("plugin %q is already registered", name)
 	}
 
@@ -33,18 +41,61 @@ func (r *Registry) Register(name string, plugin Plugin) error {
 	return nil
 }
 
+// RegisterFactory adds a plugin factory to the registry with the given name.
+// Each pipeline built from the registry receives a new instance created with that
+// pipeline's configuration block for the plugin.
+// Returns an error if a plugin or factory with the same name is already registered.
+func (r *Registry) RegisterFactory(name string, factory PluginFactory) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.isRegistered(name) {
+		return fmt.Errorf("plugin %q is already registered", name)
+	}
+
+	r.factories[name] = factory
+	return nil
+}
+
+// isRegistered reports whether name is taken by a plugin or a factory.
+// The caller must hold the lock.
+func (r *Registry) isRegistered(name string) bool {
+	_, isPlugin := r.plugins[name]
+	_, isFactory := r.factories[name]
+	return isPlugin || isFactory
+}
+
 // Get retrieves a plugin by name from the registry.
+// Plugins registered with a factory are created with a nil configuration.
 // Returns an error if the plugin is not found.
 func (r *Registry) Get(name string) (Plugin, error) {
+	return r.Create(name, nil)
+}
+
+// Create retrieves a plugin by name, passing config to its factory if it was registered with one.
+// Returns an error if the plugin is not found, the factory fails, or config is supplied for a
+// plugin registered as a fixed instance.
+func (r *Registry) Create(name string, config json.RawMessage) (Plugin, error) {
 	r.mu.RLock()
-	defer r.mu.RUnlock()
+	plugin, isPlugin := r.plugins[name]
+	factory, isFactory := r.factories[name]
+	r.mu.RUnlock()
 
-	plugin, exists := r.plugins[name]
-	if !exists {
+	switch {
+	case isPlugin:
+		if len(config) > 0 {
+			return nil, fmt.Errorf("plugin %q does not accept configuration", name)
+		}
+		return plugin, nil
+	case isFactory:
+		plugin, err := factory(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create plugin %q: %w", name, err)
+		}
+		return plugin, nil
+	default:
 		return nil, fmt.Errorf("plugin %q not found in registry", name)
 	}
-
-	return plugin, nil
 }
 
 // BuildPipeline constructs a pipeline from a list of plugin names.
@@ -57,7 +108,107 @@ func (r *Registry) BuildPipeline(names []string, strategy ErrorStrategy) (*Pipel
 		if err != nil {
 			return nil, fmt.Errorf("failed to build pipeline: %w", err)
 		}
-		pipeline.Use(plugin)
+		pipeline.UseNamed(name, plugin)
+	}
+
+	return pipeline, nil
+}
+
+// BuildFromExpr constructs a pipeline from an expression listing plugin names separated
+// by "|", such as "profanity | spam | scoring". Whitespace around names is ignored.
+// Returns an error if the expression has an empty step or a name is not found in the registry.
+func (r *Registry) BuildFromExpr(expr string, strategy ErrorStrategy) (*Pipeline, error) {
+	steps := strings.Split(expr, "|")
+	names := make([]string, len(steps))
+	for i, step := range steps {
+		name := strings.TrimSpace(step)
+		if name == "" {
+			return nil, fmt.Errorf("invalid pipeline expression %q: step %d is empty", expr, i+1)
+		}
+		if strings.Contains(name, "&") {
+			return nil, fmt.Errorf("invalid pipeline expression %q: parallel groups (&) are not supported", expr)
+		}
+		names[i] = name
+	}
+
+	return r.BuildPipeline(names, strategy)
+}
+
+// BuildOrdered constructs a pipeline from a list of plugin names, ordering the plugins so
+// that every plugin runs after the plugins providing the keys it requires (see DependentPlugin).
+// Plugins without dependencies between them keep their relative order from names.
+// Returns an error if a name is not found, a required key is not provided by any of the
+// named plugins, or the dependencies form a cycle.
+func (r *Registry) BuildOrdered(names []string, strategy ErrorStrategy) (*Pipeline, error) {
+	plugins := make([]Plugin, len(names))
+	for i, name := range names {
+		plugin, err := r.Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build pipeline: %w", err)
+		}
+		plugins[i] = plugin
+	}
+
+	// Index which plugins provide each key
+	providers := make(map[string][]int)
+	for i, plugin := range plugins {
+		if dependent, ok := plugin.(DependentPlugin); ok {
+			for _, key := range dependent.Provides() {
+				providers[key] = append(providers[key], i)
+			}
+		}
+	}
+
+	// Build edges from each provider to the plugins requiring its keys
+	dependents := make([][]int, len(plugins))
+	pending := make([]int, len(plugins))
+	for i, plugin := range plugins {
+		dependent, ok := plugin.(DependentPlugin)
+		if !ok {
+			continue
+		}
+		edges := make(map[int]bool)
+		for _, key := range dependent.Requires() {
+			keyProviders, ok := providers[key]
+			if !ok {
+				return nil, fmt.Errorf("failed to build pipeline: plugin %q requires %q, which no plugin provides", names[i], key)
+			}
+			for _, provider := range keyProviders {
+				if provider != i && !edges[provider] {
+					edges[provider] = true
+					dependents[provider] = append(dependents[provider], i)
+					pending[i]++
+				}
+			}
+		}
+	}
+
+	// Kahn's algorithm, always taking the earliest ready plugin to keep the given order
+	pipeline := NewPipeline(strategy)
+	done := make([]bool, len(plugins))
+	for added := 0; added < len(plugins); added++ {
+		next := -1
+		for i := range plugins {
+			if !done[i] && pending[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			cycle := make([]string, 0)
+			for i, name := range names {
+				if !done[i] {
+					cycle = append(cycle, name)
+				}
+			}
+			return nil, fmt.Errorf("failed to build pipeline: dependency cycle among %q", cycle)
+		}
+
+		done[next] = true
+		pipeline.UseNamed(names[next], plugins[next])
+		for _, i := range dependents[next] {
+			pending[i]--
+		}
 	}
 
 	return pipeline, nil
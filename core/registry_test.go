@@ -0,0 +1,160 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// configPlugin is a plugin created by a factory with its configuration block.
+type configPlugin struct {
+	Greeting string `json:"greeting"`
+}
+
+func (p *configPlugin) Execute(ctx *Context) error {
+	ctx.Set("greeting", p.Greeting)
+	return nil
+}
+
+func configFactory(config json.RawMessage) (Plugin, error) {
+	plugin := &configPlugin{Greeting: "hello"}
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, plugin); err != nil {
+			return nil, err
+		}
+	}
+	return plugin, nil
+}
+
+func TestRegistryFactoryConfig(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.RegisterFactory("greeter", configFactory); err != nil {
+		t.Fatalf("RegisterFactory: %v", err)
+	}
+
+	plugin, err := registry.Create("greeter", json.RawMessage(`{"greeting": "hi"}`))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got := plugin.(*configPlugin).Greeting; got != "hi" {
+		t.Errorf("Greeting = %q, want %q", got, "hi")
+	}
+
+	plugin, err = registry.Get("greeter")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got := plugin.(*configPlugin).Greeting; got != "hello" {
+		t.Errorf("Greeting without config = %q, want %q", got, "hello")
+	}
+}
+
+func TestRegistryFactoryPerPipeline(t *testing.T) {
+	registry := NewRegistry()
+	registry.RegisterFactory("greeter", configFactory)
+
+	pipeline, err := registry.BuildFromJSON(strings.NewReader(`{"plugins": [{"name": "greeter", "config": {"greeting": "hey"}}]}`))
+	if err != nil {
+		t.Fatalf("BuildFromJSON: %v", err)
+	}
+	ctx := NewContext(nil)
+	if err := pipeline.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if got, _ := Value[string](ctx, "greeting"); got != "hey" {
+		t.Errorf("greeting = %q, want %q", got, "hey")
+	}
+}
+
+func TestRegistryCreateErrors(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("fixed", pluginFunc(func(*Context) error { return nil }))
+	registry.RegisterFactory("failing", func(json.RawMessage) (Plugin, error) {
+		return nil, errors.New("bad config")
+	})
+
+	if _, err := registry.Create("fixed", json.RawMessage(`{}`)); err == nil {
+		t.Error("Create with config for a fixed plugin succeeded, want an error")
+	}
+	if _, err := registry.Create("failing", nil); err == nil {
+		t.Error("Create with a failing factory succeeded, want an error")
+	}
+	if _, err := registry.Create("missing", nil); err == nil {
+		t.Error("Create of an unregistered plugin succeeded, want an error")
+	}
+	if err := registry.RegisterFactory("fixed", configFactory); err == nil {
+		t.Error("RegisterFactory with a taken name succeeded, want an error")
+	}
+}
+
+func TestRegistryBuildOrdered(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("decide", &keysPlugin{requires: []string{"score"}, provides: []string{"decision"}})
+	registry.Register("score", &keysPlugin{requires: []string{"tokens"}, provides: []string{"score"}})
+	registry.Register("tokenize", &keysPlugin{provides: []string{"tokens"}})
+	registry.Register("log", pluginFunc(func(*Context) error { return nil }))
+
+	pipeline, err := registry.BuildOrdered([]string{"log", "decide", "score", "tokenize"}, AbortOnError)
+	if err != nil {
+		t.Fatalf("BuildOrdered: %v", err)
+	}
+	if want := []string{"log", "tokenize", "score", "decide"}; !reflect.DeepEqual(pipeline.PluginNames(), want) {
+		t.Errorf("order = %v, want %v", pipeline.PluginNames(), want)
+	}
+	if err := pipeline.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
+
+func TestRegistryBuildOrderedErrors(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("a", &keysPlugin{requires: []string{"b"}, provides: []string{"a"}})
+	registry.Register("b", &keysPlugin{requires: []string{"a"}, provides: []string{"b"}})
+	registry.Register("orphan", &keysPlugin{requires: []string{"missing"}})
+
+	tests := []struct {
+		names []string
+		want  string
+	}{
+		{[]string{"a", "b"}, "dependency cycle"},
+		{[]string{"orphan"}, `requires "missing"`},
+		{[]string{"unknown"}, "unknown"},
+	}
+	for _, tt := range tests {
+		if _, err := registry.BuildOrdered(tt.names, AbortOnError); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("BuildOrdered(%v) = %v, want an error containing %q", tt.names, err, tt.want)
+		}
+	}
+}
+
+func TestRegistryBuildFromExpr(t *testing.T) {
+	registry := NewRegistry()
+	for _, name := range []string{"a", "b", "c"} {
+		registry.Register(name, pluginFunc(func(*Context) error { return nil }))
+	}
+
+	pipeline, err := registry.BuildFromExpr(" a |b| c ", AbortOnError)
+	if err != nil {
+		t.Fatalf("BuildFromExpr: %v", err)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(pipeline.PluginNames(), want) {
+		t.Errorf("order = %v, want %v", pipeline.PluginNames(), want)
+	}
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"a || b", "step 2 is empty"},
+		{"", "step 1 is empty"},
+		{"a | b & c", "parallel groups"},
+		{"a | missing", "missing"},
+	}
+	for _, tt := range tests {
+		if _, err := registry.BuildFromExpr(tt.expr, AbortOnError); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("BuildFromExpr(%q) = %v, want an error containing %q", tt.expr, err, tt.want)
+		}
+	}
+}
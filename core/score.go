@@ -0,0 +1,25 @@
+package core
+
+// ScoresKey is the metadata key holding the scores added with AddScore. Plugins that add
+// scores can list it in Provides, and plugins that aggregate them in Requires.
+const ScoresKey = "scores"
+
+// AddScore adds value to the score of category, such as "spam", so several plugins can
+// contribute to one shared score map without knowing about each other. Scores are kept
+// in metadata under ScoresKey; the map is replaced rather than modified, so snapshots and
+// rollback include the scores.
+func (c *Context) AddScore(category string, value float64) {
+	current := c.Scores()
+	current[category] += value
+	c.Metadata[ScoresKey] = current
+}
+
+// Scores returns a copy of the scores added with AddScore, by category.
+func (c *Context) Scores() map[string]float64 {
+	existing, _ := c.Metadata[ScoresKey].(map[string]float64)
+	scores := make(map[string]float64, len(existing)+1)
+	for category, value := range existing {
+		scores[category] = value
+	}
+	return scores
+}
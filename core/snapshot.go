@@ -0,0 +1,49 @@
+package core
+
+// Snapshot is a point-in-time copy of a Context's data, metadata, and state.
+// Maps are copied one level deep: restoring brings back the original set of keys
+// and values, but values that are themselves pointers, maps, or slices are shared
+// with the live Context, so changes made inside them are not undone.
+type Snapshot struct {
+	data     any
+	metadata map[string]any
+	state    map[string]any
+}
+
+// Snapshot captures the current data, metadata, and state of the Context.
+// Collected errors are not part of the snapshot.
+func (c *Context) Snapshot() Snapshot {
+	return Snapshot{
+		data:     c.Data,
+		metadata: copyMap(c.Metadata),
+		state:    copyMap(c.state),
+	}
+}
+
+// Restore returns the Context's data, metadata, and state to the snapshot.
+// Keys added since the snapshot was taken are removed. The maps are updated in place,
+// so contexts sharing state through WithData see the restored state too.
+func (c *Context) Restore(s Snapshot) {
+	c.Data = s.data
+	replaceMap(c.Metadata, s.metadata)
+	replaceMap(c.state, s.state)
+}
+
+// copyMap returns a shallow copy of m.
+func copyMap(m map[string]any) map[string]any {
+	copied := make(map[string]any, len(m))
+	for k, v := range m {
+		copied[k] = v
+	}
+	return copied
+}
+
+// replaceMap makes dst hold exactly the entries of src.
+func replaceMap(dst, src map[string]any) {
+	for k := range dst {
+		delete(dst, k)
+	}
+	for k, v := range src {
+		dst[k] = v
+	}
+}
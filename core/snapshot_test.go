@@ -0,0 +1,77 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestContextSnapshotRestore(t *testing.T) {
+	ctx := NewContext("original")
+	ctx.Set("kept", 1)
+	ctx.SetState("session", "a")
+
+	snapshot := ctx.Snapshot()
+	ctx.SetData("changed")
+	ctx.Set("kept", 2)
+	ctx.Set("added", true)
+	ctx.SetState("session", "b")
+
+	ctx.Restore(snapshot)
+	if ctx.GetData() != "original" {
+		t.Errorf("data = %v, want original", ctx.GetData())
+	}
+	if v, _ := ctx.Get("kept"); v != 1 {
+		t.Errorf("kept = %v, want 1", v)
+	}
+	if _, ok := ctx.Get("added"); ok {
+		t.Error("key added after the snapshot survived Restore")
+	}
+	if v, _ := ctx.GetState("session"); v != "a" {
+		t.Errorf("state session = %v, want a", v)
+	}
+}
+
+func TestContextRestoreSharedState(t *testing.T) {
+	ctx := NewContext(nil)
+	ctx.SetState("count", 1)
+	derived := ctx.WithData("other")
+
+	snapshot := ctx.Snapshot()
+	derived.SetState("count", 2)
+	ctx.Restore(snapshot)
+
+	if v, _ := derived.GetState("count"); v != 1 {
+		t.Errorf("derived state count = %v, want 1 after Restore", v)
+	}
+}
+
+func TestPipelineRollback(t *testing.T) {
+	failing := pluginFunc(func(ctx *Context) error {
+		ctx.Set("partial", true)
+		ctx.Set("step", "failed")
+		return errors.New("boom")
+	})
+	setStep := pluginFunc(func(ctx *Context) error {
+		ctx.Set("step", "first")
+		return nil
+	})
+
+	ctx := NewContext(nil)
+	pipeline := NewPipeline(ContinueOnError).WithRollback(true).Use(setStep).Use(failing)
+	if err := pipeline.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if _, ok := ctx.Get("partial"); ok {
+		t.Error("changes of the failed plugin were not rolled back")
+	}
+	if v, _ := ctx.Get("step"); v != "first" {
+		t.Errorf("step = %v, want the value set before the failure", v)
+	}
+
+	ctx = NewContext(nil)
+	pipeline = NewPipeline(ContinueOnError).Use(setStep).Use(failing)
+	pipeline.Execute(ctx)
+	if _, ok := ctx.Get("partial"); !ok {
+		t.Error("changes rolled back with rollback disabled")
+	}
+}
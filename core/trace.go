@@ -0,0 +1,26 @@
+package core
+
+import "time"
+
+// TraceEntry records one plugin execution in a Context's execution trace.
+type TraceEntry struct {
+	PluginIndex int           // Index of the plugin within its pipeline
+	Plugin      string        // Name of the plugin, as reported by PluginNames
+	Duration    time.Duration // Time spent in the plugin
+	Err         error         // Error returned by the plugin, or nil
+}
+
+// Trace returns the plugins executed on this Context, in order, by pipelines with
+// the execution trace enabled. Plugins run by a nested pipeline, such as a branch,
+// appear before the plugin that contains them, with indexes within the nested pipeline.
+// The returned slice is a copy.
+func (c *Context) Trace() []TraceEntry {
+	trace := make([]TraceEntry, len(c.trace))
+	copy(trace, c.trace)
+	return trace
+}
+
+// addTrace appends an entry to the execution trace.
+func (c *Context) addTrace(entry TraceEntry) {
+	c.trace = append(c.trace, entry)
+}
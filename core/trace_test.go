@@ -0,0 +1,56 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExecutionTraceDisabledByDefault(t *testing.T) {
+	var order []string
+	ctx := NewContext(nil)
+	if err := NewPipeline(AbortOnError).Use(recordPlugin(&order, "a")).Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if trace := ctx.Trace(); len(trace) != 0 {
+		t.Errorf("trace = %+v, want empty", trace)
+	}
+}
+
+func TestExecutionTrace(t *testing.T) {
+	var order []string
+	failure := errors.New("boom")
+	pipeline := NewPipeline(ContinueOnError).
+		WithExecutionTrace(true).
+		UseNamed("first", recordPlugin(&order, "first")).
+		UseBranch(func(*Context) bool { return true },
+			[]Plugin{recordPlugin(&order, "inner")}, nil).
+		UseNamed("failing", pluginFunc(func(*Context) error { return failure }))
+
+	ctx := NewContext(nil)
+	if err := pipeline.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	trace := ctx.Trace()
+	want := []struct {
+		index int
+		name  string
+	}{{0, "first"}, {0, ""}, {1, "branch"}, {2, "failing"}}
+	if len(trace) != len(want) {
+		t.Fatalf("trace = %+v, want %d entries", trace, len(want))
+	}
+	for i, entry := range trace {
+		if entry.PluginIndex != want[i].index || (want[i].name != "" && entry.Plugin != want[i].name) {
+			t.Errorf("trace[%d] = %+v, want index %d named %q", i, entry, want[i].index, want[i].name)
+		}
+	}
+	if !errors.Is(trace[3].Err, failure) || trace[0].Err != nil {
+		t.Errorf("trace errors = %v, %v, want nil then the plugin's error", trace[0].Err, trace[3].Err)
+	}
+
+	// The returned trace is a copy
+	trace[0].Plugin = "changed"
+	if ctx.Trace()[0].Plugin != "first" {
+		t.Error("modifying the returned trace changed the Context")
+	}
+}
@@ -0,0 +1,35 @@
+package core
+
+// Tracer starts spans around plugin execution.
+// It is a thin interface so that the core stays free of tracing dependencies;
+// an adapter for OpenTelemetry or another tracing library only needs a few lines.
+type Tracer interface {
+	StartSpan(name string) Span
+}
+
+// Span is a single traced operation started by a Tracer.
+type Span interface {
+	// SetAttribute attaches a key/value pair to the span.
+	SetAttribute(key string, value any)
+	// RecordError records err on the span and marks it as failed.
+	RecordError(err error)
+	// End completes the span.
+	End()
+}
+
+// nopTracer starts spans that discard everything.
+type nopTracer struct{}
+
+// nopSpan discards all attributes and errors.
+type nopSpan struct{}
+
+func (nopTracer) StartSpan(name string) Span { return nopSpan{} }
+
+func (nopSpan) SetAttribute(key string, value any) {}
+func (nopSpan) RecordError(err error)              {}
+func (nopSpan) End()                               {}
+
+// NopTracer returns a Tracer whose spans discard everything. It is the pipeline default.
+func NopTracer() Tracer {
+	return nopTracer{}
+}
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// recordedSpan is a span captured by spanRecorder.
+type recordedSpan struct {
+	name       string
+	attributes map[string]any
+	err        error
+	ended      bool
+}
+
+// spanRecorder is an in-memory Tracer that keeps every span it starts.
+type spanRecorder struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (r *spanRecorder) StartSpan(name string) Span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	span := &recordedSpan{name: name, attributes: make(map[string]any)}
+	r.spans = append(r.spans, span)
+	return span
+}
+
+func (s *recordedSpan) SetAttribute(key string, value any) { s.attributes[key] = value }
+func (s *recordedSpan) RecordError(err error)              { s.err = err }
+func (s *recordedSpan) End()                               { s.ended = true }
+
+func TestPipelineTracer(t *testing.T) {
+	boom := errors.New("boom")
+	recorder := &spanRecorder{}
+	pipeline := NewPipeline(ContinueOnError).
+		WithTracer(recorder).
+		UseNamed("first", pluginFunc(func(*Context) error { return nil })).
+		UseNamed("second", pluginFunc(func(*Context) error { return boom }))
+
+	if err := pipeline.Execute(NewContext(nil)); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	if len(recorder.spans) != 2 {
+		t.Fatalf("spans = %d, want 2", len(recorder.spans))
+	}
+	for i, want := range []string{"first", "second"} {
+		span := recorder.spans[i]
+		if span.name != want || !span.ended || span.attributes["plugin.index"] != i {
+			t.Errorf("span %d = %+v, want an ended span named %s with plugin.index %d", i, span, want, i)
+		}
+	}
+	if recorder.spans[0].err != nil {
+		t.Errorf("span 0 error = %v, want none", recorder.spans[0].err)
+	}
+	if !errors.Is(recorder.spans[1].err, boom) {
+		t.Errorf("span 1 error = %v, want boom", recorder.spans[1].err)
+	}
+}
+
+func TestPipelineTracerSkipRemainingIsNotAnError(t *testing.T) {
+	recorder := &spanRecorder{}
+	pipeline := NewPipeline(AbortOnError).
+		WithTracer(recorder).
+		Use(pluginFunc(func(*Context) error { return ErrSkipRemaining }))
+
+	if err := pipeline.Execute(NewContext(nil)); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if len(recorder.spans) != 1 || recorder.spans[0].err != nil {
+		t.Errorf("spans = %+v, want one span without an error", recorder.spans)
+	}
+}
+
+func TestPipelineNilTracer(t *testing.T) {
+	pipeline := NewPipeline(AbortOnError).WithTracer(nil).Use(pluginFunc(func(*Context) error { return nil }))
+	if err := pipeline.Execute(NewContext(nil)); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+}
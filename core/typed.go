@@ -0,0 +1,30 @@
+package core
+
+// Data returns the context's primary data as type T.
+// Returns the zero value of T and false if the data is not a T.
+//
+// Plugins opt in by replacing manual type assertions:
+//
+//	content, ok := core.Data[*moderation.Content](ctx)
+//	if !ok {
+//		return fmt.Errorf("expected *Content, got %T", ctx.GetData())
+//	}
+func Data[T any](ctx *Context) (T, bool) {
+	data, ok := ctx.GetData().(T)
+	return data, ok
+}
+
+// Value returns the metadata value for key as type T.
+// Returns the zero value of T and false if the key is missing or holds a different type.
+func Value[T any](ctx *Context, key string) (T, bool) {
+	var zero T
+	value, exists := ctx.Get(key)
+	if !exists {
+		return zero, false
+	}
+	typed, ok := value.(T)
+	if !ok {
+		return zero, false
+	}
+	return typed, true
+}
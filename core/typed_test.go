@@ -0,0 +1,28 @@
+package core
+
+import "testing"
+
+func TestData(t *testing.T) {
+	ctx := NewContext("hello")
+	if data, ok := Data[string](ctx); !ok || data != "hello" {
+		t.Errorf("Data[string] = %q, %v, want hello, true", data, ok)
+	}
+	if data, ok := Data[int](ctx); ok || data != 0 {
+		t.Errorf("Data[int] = %d, %v, want 0, false", data, ok)
+	}
+}
+
+func TestValue(t *testing.T) {
+	ctx := NewContext(nil)
+	ctx.Set("score", 0.5)
+
+	if score, ok := Value[float64](ctx, "score"); !ok || score != 0.5 {
+		t.Errorf("Value[float64] = %v, %v, want 0.5, true", score, ok)
+	}
+	if score, ok := Value[int](ctx, "score"); ok || score != 0 {
+		t.Errorf("Value[int] of a float = %v, %v, want 0, false", score, ok)
+	}
+	if name, ok := Value[string](ctx, "missing"); ok || name != "" {
+		t.Errorf("Value of a missing key = %q, %v, want \"\", false", name, ok)
+	}
+}
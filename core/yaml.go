@@ -0,0 +1,424 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// BuildFromYAML reads a YAML-encoded PipelineConfig and constructs the pipeline it
+// describes, exactly as BuildFromJSON would from the equivalent JSON:
+//
+//	strategy: continue
+//	plugins:
+//	  - profanity
+//	  - name: history
+//	    config:
+//	      maxHistorySize: 5
+//
+// The YAML is converted to JSON first, so plugin config blocks reach factories as JSON.
+// The common subset of YAML used for configuration is supported: block mappings and
+// sequences, plain and quoted scalars, single-line flow collections such as [a, b], and
+// comments. Anchors, tags, and multi-line strings are not.
+func (r *Registry) BuildFromYAML(reader io.Reader) (*Pipeline, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pipeline config: %w", err)
+	}
+
+	value, err := parseYAML(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid pipeline config: %w", err)
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pipeline config: %w", err)
+	}
+
+	var config PipelineConfig
+	if err := json.Unmarshal(encoded, &config); err != nil {
+		return nil, fmt.Errorf("invalid pipeline config: %w", err)
+	}
+	return r.BuildFromConfig(config)
+}
+
+// yamlLine is a non-empty line of YAML with its comment removed.
+type yamlLine struct {
+	number int    // 1-based line number, for errors
+	indent int    // leading spaces
+	text   string // content after the indentation
+}
+
+// parseYAML parses a single YAML document into maps, slices, strings, float64s, bools,
+// and nils, the same types encoding/json produces.
+func parseYAML(source string) (any, error) {
+	var lines []yamlLine
+	for i, raw := range strings.Split(source, "\n") {
+		raw = strings.TrimRight(stripYAMLComment(raw), " \t\r")
+		text := strings.TrimLeft(raw, " ")
+		if text == "" || (len(lines) == 0 && text == "---") || text == "..." {
+			continue
+		}
+		if strings.HasPrefix(text, "\t") {
+			return nil, fmt.Errorf("yaml line %d: tabs are not allowed in indentation", i+1)
+		}
+		lines = append(lines, yamlLine{number: i + 1, indent: len(raw) - len(text), text: text})
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	value, next, err := parseYAMLBlock(lines, 0, lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if next < len(lines) {
+		return nil, fmt.Errorf("yaml line %d: unexpected indentation", lines[next].number)
+	}
+	return value, nil
+}
+
+// parseYAMLBlock parses the mapping or sequence starting at lines[start], whose entries
+// are indented by indent, and returns it with the index of the first line after it.
+func parseYAMLBlock(lines []yamlLine, start, indent int) (any, int, error) {
+	if isYAMLSequenceItem(lines[start].text) {
+		return parseYAMLSequence(lines, start, indent)
+	}
+	return parseYAMLMapping(lines, start, indent)
+}
+
+// parseYAMLSequence parses a block sequence of "- item" lines.
+func parseYAMLSequence(lines []yamlLine, start, indent int) (any, int, error) {
+	items := make([]any, 0)
+	i := start
+	for i < len(lines) && lines[i].indent == indent && isYAMLSequenceItem(lines[i].text) {
+		line := lines[i]
+		rest := strings.TrimLeft(line.text[1:], " ")
+
+		switch {
+		case rest == "":
+			// The item is the nested block on the following lines
+			if i+1 >= len(lines) || lines[i+1].indent <= indent {
+				items = append(items, nil)
+				i++
+				continue
+			}
+			value, next, err := parseYAMLBlock(lines, i+1, lines[i+1].indent)
+			if err != nil {
+				return nil, 0, err
+			}
+			items = append(items, value)
+			i = next
+		case isYAMLSequenceItem(rest) || isYAMLMappingEntry(rest):
+			// A collection starting on the item's line, as in "- name: history", continues
+			// on the following lines at the indentation of its first entry
+			lines[i].indent += len(line.text) - len(rest)
+			lines[i].text = rest
+			value, next, err := parseYAMLBlock(lines, i, lines[i].indent)
+			if err != nil {
+				return nil, 0, err
+			}
+			items = append(items, value)
+			i = next
+		default:
+			value, err := parseYAMLValue(rest, line.number)
+			if err != nil {
+				return nil, 0, err
+			}
+			items = append(items, value)
+			i++
+		}
+	}
+	return items, i, nil
+}
+
+// parseYAMLMapping parses a block mapping of "key: value" lines.
+func parseYAMLMapping(lines []yamlLine, start, indent int) (any, int, error) {
+	mapping := make(map[string]any)
+	i := start
+	for i < len(lines) && lines[i].indent == indent && !isYAMLSequenceItem(lines[i].text) {
+		line := lines[i]
+		key, rest, ok := splitYAMLEntry(line.text)
+		if !ok {
+			return nil, 0, fmt.Errorf("yaml line %d: expected \"key: value\"", line.number)
+		}
+		if _, exists := mapping[key]; exists {
+			return nil, 0, fmt.Errorf("yaml line %d: duplicate key %q", line.number, key)
+		}
+		i++
+
+		if rest != "" {
+			value, err := parseYAMLValue(rest, line.number)
+			if err != nil {
+				return nil, 0, err
+			}
+			mapping[key] = value
+			continue
+		}
+
+		// The value is the nested block on the following lines; a sequence may also sit
+		// at the key's own indentation
+		switch {
+		case i < len(lines) && lines[i].indent > indent:
+			value, next, err := parseYAMLBlock(lines, i, lines[i].indent)
+			if err != nil {
+				return nil, 0, err
+			}
+			mapping[key], i = value, next
+		case i < len(lines) && lines[i].indent == indent && isYAMLSequenceItem(lines[i].text):
+			value, next, err := parseYAMLSequence(lines, i, indent)
+			if err != nil {
+				return nil, 0, err
+			}
+			mapping[key], i = value, next
+		default:
+			mapping[key] = nil
+		}
+	}
+	if i < len(lines) && lines[i].indent > indent {
+		return nil, 0, fmt.Errorf("yaml line %d: unexpected indentation", lines[i].number)
+	}
+	return mapping, i, nil
+}
+
+// isYAMLSequenceItem reports whether text is a block sequence item.
+func isYAMLSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// isYAMLMappingEntry reports whether text is a "key: value" entry rather than a scalar.
+func isYAMLMappingEntry(text string) bool {
+	if strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{") {
+		return false
+	}
+	_, _, ok := splitYAMLEntry(text)
+	return ok
+}
+
+// splitYAMLEntry splits "key: value" into its key and value. The key may be quoted; the
+// value is empty when it is on the following lines.
+func splitYAMLEntry(text string) (string, string, bool) {
+	if strings.HasPrefix(text, `"`) || strings.HasPrefix(text, "'") {
+		end := closingQuote(text)
+		if end < 0 {
+			return "", "", false
+		}
+		rest := text[end+1:]
+		if rest != ":" && !strings.HasPrefix(rest, ": ") {
+			return "", "", false
+		}
+		key, err := parseYAMLScalar(text[:end+1])
+		if err != nil {
+			return "", "", false
+		}
+		return fmt.Sprint(key), strings.TrimSpace(rest[1:]), true
+	}
+
+	if strings.HasSuffix(text, ":") {
+		return strings.TrimSpace(text[:len(text)-1]), "", true
+	}
+	colon := strings.Index(text, ": ")
+	if colon <= 0 {
+		return "", "", false
+	}
+	return strings.TrimSpace(text[:colon]), strings.TrimSpace(text[colon+2:]), true
+}
+
+// parseYAMLValue parses an inline value: a flow collection or a scalar.
+func parseYAMLValue(text string, number int) (any, error) {
+	if strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{") {
+		parser := yamlFlowParser{text: text}
+		value, err := parser.parse()
+		if err == nil && strings.TrimSpace(parser.text[parser.pos:]) != "" {
+			err = fmt.Errorf("unexpected %q after collection", parser.text[parser.pos:])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("yaml line %d: %w", number, err)
+		}
+		return value, nil
+	}
+
+	value, err := parseYAMLScalar(text)
+	if err != nil {
+		return nil, fmt.Errorf("yaml line %d: %w", number, err)
+	}
+	return value, nil
+}
+
+// parseYAMLScalar converts a scalar to a string, float64, bool, or nil.
+func parseYAMLScalar(text string) (any, error) {
+	switch {
+	case strings.HasPrefix(text, `"`):
+		if closingQuote(text) != len(text)-1 {
+			return nil, fmt.Errorf("unterminated string %s", text)
+		}
+		return strconv.Unquote(text)
+	case strings.HasPrefix(text, "'"):
+		if closingQuote(text) != len(text)-1 {
+			return nil, fmt.Errorf("unterminated string %s", text)
+		}
+		return strings.ReplaceAll(text[1:len(text)-1], "''", "'"), nil
+	}
+
+	switch text {
+	case "", "~", "null", "Null", "NULL":
+		return nil, nil
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+	if number, err := strconv.ParseFloat(text, 64); err == nil && !strings.ContainsAny(text, "xXnN_") {
+		return number, nil
+	}
+	return text, nil
+}
+
+// closingQuote returns the index of the quote closing the string text starts with, or -1.
+func closingQuote(text string) int {
+	quote := text[0]
+	for i := 1; i < len(text); i++ {
+		switch {
+		case quote == '"' && text[i] == '\\':
+			i++
+		case text[i] == quote && quote == '\'' && i+1 < len(text) && text[i+1] == '\'':
+			i++ // '' is an escaped single quote
+		case text[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// stripYAMLComment removes a "#" comment, which starts a line or follows a space, unless
+// the "#" is inside a quoted string. Only a quote that opens a scalar starts a string, so
+// the apostrophe in "text: it's ok # note" doesn't hide the comment.
+func stripYAMLComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '"', '\'':
+			if !opensYAMLScalar(line, i) {
+				continue
+			}
+			end := closingQuote(line[i:])
+			if end < 0 {
+				return line
+			}
+			i += end
+		case '#':
+			if i == 0 || line[i-1] == ' ' || line[i-1] == '\t' {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
+
+// opensYAMLScalar reports whether line[i] is the first character of a scalar: it starts
+// the line, or follows a "key: ", a "- " sequence indicator, or a flow collection's "[",
+// "{" or ",".
+func opensYAMLScalar(line string, i int) bool {
+	j := i - 1
+	for j >= 0 && (line[j] == ' ' || line[j] == '\t') {
+		j--
+	}
+	if j < 0 {
+		return true
+	}
+	switch line[j] {
+	case '[', '{', ',':
+		return true
+	case ':':
+		return j < i-1
+	case '-':
+		return j < i-1 && (j == 0 || line[j-1] == ' ')
+	}
+	return false
+}
+
+// yamlFlowParser parses a single-line flow collection such as [a, {b: 1}].
+type yamlFlowParser struct {
+	text string
+	pos  int
+}
+
+// parse parses the value at the current position.
+func (p *yamlFlowParser) parse() (any, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.text) {
+		return nil, fmt.Errorf("unexpected end of collection")
+	}
+
+	switch p.text[p.pos] {
+	case '[':
+		p.pos++
+		items := make([]any, 0)
+		for !p.consume(']') {
+			if len(items) > 0 && !p.consume(',') {
+				return nil, fmt.Errorf("expected \",\" or \"]\" in %s", p.text)
+			}
+			item, err := p.parse()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case '{':
+		p.pos++
+		mapping := make(map[string]any)
+		for !p.consume('}') {
+			if len(mapping) > 0 && !p.consume(',') {
+				return nil, fmt.Errorf("expected \",\" or \"}\" in %s", p.text)
+			}
+			key, err := p.parse()
+			if err != nil {
+				return nil, err
+			}
+			if !p.consume(':') {
+				return nil, fmt.Errorf("expected \":\" after key in %s", p.text)
+			}
+			value, err := p.parse()
+			if err != nil {
+				return nil, err
+			}
+			mapping[fmt.Sprint(key)] = value
+		}
+		return mapping, nil
+	}
+
+	// A scalar runs to the next delimiter, or to its closing quote
+	start := p.pos
+	if quote := p.text[p.pos]; quote == '"' || quote == '\'' {
+		end := closingQuote(p.text[p.pos:])
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated string in %s", p.text)
+		}
+		p.pos += end + 1
+	} else {
+		for p.pos < len(p.text) && !strings.ContainsRune(",]}", rune(p.text[p.pos])) &&
+			!(p.text[p.pos] == ':' && (p.pos+1 == len(p.text) || p.text[p.pos+1] == ' ')) {
+			p.pos++
+		}
+	}
+	return parseYAMLScalar(strings.TrimSpace(p.text[start:p.pos]))
+}
+
+// consume skips spaces and then c, reporting whether c was there.
+func (p *yamlFlowParser) consume(c byte) bool {
+	p.skipSpaces()
+	if p.pos < len(p.text) && p.text[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// skipSpaces advances past spaces.
+func (p *yamlFlowParser) skipSpaces() {
+	for p.pos < len(p.text) && p.text[p.pos] == ' ' {
+		p.pos++
+	}
+}
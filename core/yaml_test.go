@@ -0,0 +1,125 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	source := `
+# pipeline
+strategy: continue   # keep going
+plugins:
+- profanity
+- name: "history"
+  config:
+    maxHistorySize: 5
+    tags: [a, 'b c', {x: 1}]
+    note: it's ok # trailing comment
+    hash: "# not a comment"
+    enabled: true
+    empty: ~
+`
+	got, err := parseYAML(source)
+	if err != nil {
+		t.Fatalf("parseYAML: %v", err)
+	}
+	want := map[string]any{
+		"strategy": "continue",
+		"plugins": []any{
+			"profanity",
+			map[string]any{
+				"name": "history",
+				"config": map[string]any{
+					"maxHistorySize": 5.0,
+					"tags":           []any{"a", "b c", map[string]any{"x": 1.0}},
+					"note":           "it's ok",
+					"hash":           "# not a comment",
+					"enabled":        true,
+					"empty":          nil,
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseYAML = %#v, want %#v", got, want)
+	}
+}
+
+func TestStripYAMLComment(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"text: it's ok # note", "text: it's ok "},
+		{"text: 'it''s # kept' # note", "text: 'it''s # kept' "},
+		{`- "a # b" # note`, `- "a # b" `},
+		{"tags: [x, 'y # z'] # note", "tags: [x, 'y # z'] "},
+		{"url: a#b", "url: a#b"},
+		{"# whole line", ""},
+	}
+	for _, tt := range tests {
+		if got := stripYAMLComment(tt.line); got != tt.want {
+			t.Errorf("stripYAMLComment(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestParseYAMLErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"a: 1\na: 2", "duplicate key"},
+		{"a: 1\n    b: 2", "unexpected indentation"},
+		{"a: [1, 2", "yaml line 1"},
+		{"a: \"open", "unterminated"},
+		{"just text", `expected "key: value"`},
+	}
+	for _, tt := range tests {
+		if _, err := parseYAML(tt.source); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("parseYAML(%q) = %v, want an error containing %q", tt.source, err, tt.want)
+		}
+	}
+}
+
+func TestRegistryBuildFromYAMLMatchesJSON(t *testing.T) {
+	registry := NewRegistry()
+	for _, name := range []string{"tokenize", "score", "decide"} {
+		registry.Register(name, pluginFunc(func(*Context) error { return nil }))
+	}
+	registry.RegisterFactory("greeter", configFactory)
+
+	fromJSON, err := registry.BuildFromJSON(strings.NewReader(
+		`{"strategy": "continue", "plugins": ["tokenize", {"name": "greeter", "config": {"greeting": "hey"}}, "score", "decide"]}`))
+	if err != nil {
+		t.Fatalf("BuildFromJSON: %v", err)
+	}
+	fromYAML, err := registry.BuildFromYAML(strings.NewReader(`
+strategy: continue
+plugins:
+  - tokenize
+  - name: greeter
+    config: {greeting: hey}
+  - score
+  - decide
+`))
+	if err != nil {
+		t.Fatalf("BuildFromYAML: %v", err)
+	}
+
+	// Plugins run in the order they are listed, whatever the format
+	want := []string{"tokenize", "greeter", "score", "decide"}
+	if !reflect.DeepEqual(fromJSON.PluginNames(), want) || !reflect.DeepEqual(fromYAML.PluginNames(), want) {
+		t.Errorf("order: JSON %v, YAML %v, want %v", fromJSON.PluginNames(), fromYAML.PluginNames(), want)
+	}
+
+	ctx := NewContext(nil)
+	if err := fromYAML.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if got, _ := Value[string](ctx, "greeting"); got != "hey" {
+		t.Errorf("greeting = %q, want %q", got, "hey")
+	}
+}
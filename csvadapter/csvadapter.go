@@ -0,0 +1,179 @@
+// Package csvadapter runs CSV files of user content through a moderation pipeline.
+//
+// Rows are read, moderated, and written one at a time, so files of any size can be
+// processed without loading them into memory.
+package csvadapter
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+	"github.com/dvictor357/pipeline-plugin-system/moderation"
+)
+
+// OutputColumns is the header row written to the results CSV
+var OutputColumns = []string{
+	"id", "action", "flagged", "overall_score",
+	"profanity_score", "spam_score", "toxicity_score", "reason", "error",
+}
+
+// ColumnMapping names the input columns that hold each Content field.
+// Text is required; the other columns are optional and may be left empty.
+type ColumnMapping struct {
+	ID        string
+	Text      string
+	AuthorID  string
+	Timestamp string // Parsed as RFC 3339
+}
+
+// DefaultColumnMapping returns the mapping for columns named id, text, author_id, and timestamp
+func DefaultColumnMapping() ColumnMapping {
+	return ColumnMapping{
+		ID:        "id",
+		Text:      "text",
+		AuthorID:  "author_id",
+		Timestamp: "timestamp",
+	}
+}
+
+// columnIndexes holds the position of each mapped column, or -1 if it is absent
+type columnIndexes struct {
+	id, text, authorID, timestamp int
+}
+
+// Process reads content rows from r, runs each through pipeline, and writes one result
+// row per input row to w, preceded by OutputColumns. The first input row must be a header.
+//
+// A row that fails moderation is written with its error in the error column and processing
+// continues. Process returns an error only if the input cannot be read or the output
+// cannot be written.
+func Process(r io.Reader, w io.Writer, pipeline *core.Pipeline, mapping ColumnMapping) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	writer := csv.NewWriter(w)
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return errors.New("csv input is empty")
+		}
+		return fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	columns, err := resolveColumns(header, mapping)
+	if err != nil {
+		return err
+	}
+
+	if err := writer.Write(OutputColumns); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read csv row: %w", err)
+		}
+
+		if err := writer.Write(moderateRow(pipeline, record, columns)); err != nil {
+			return fmt.Errorf("failed to write csv row: %w", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// resolveColumns finds the position of each mapped column in the header
+func resolveColumns(header []string, mapping ColumnMapping) (columnIndexes, error) {
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		positions[name] = i
+	}
+
+	find := func(name string) int {
+		if name == "" {
+			return -1
+		}
+		if i, ok := positions[name]; ok {
+			return i
+		}
+		return -1
+	}
+
+	columns := columnIndexes{
+		id:        find(mapping.ID),
+		text:      find(mapping.Text),
+		authorID:  find(mapping.AuthorID),
+		timestamp: find(mapping.Timestamp),
+	}
+	if columns.text < 0 {
+		return columns, fmt.Errorf("csv header has no text column %q", mapping.Text)
+	}
+	return columns, nil
+}
+
+// moderateRow runs a single input row through the pipeline and returns its result row
+func moderateRow(pipeline *core.Pipeline, record []string, columns columnIndexes) []string {
+	content := &moderation.Content{
+		ID:       field(record, columns.id),
+		Text:     field(record, columns.text),
+		AuthorID: field(record, columns.authorID),
+	}
+
+	failed := func(err error) []string {
+		return []string{content.ID, "", "", "", "", "", "", "", err.Error()}
+	}
+
+	if value := field(record, columns.timestamp); value != "" {
+		timestamp, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return failed(fmt.Errorf("invalid timestamp %q", value))
+		}
+		content.Timestamp = timestamp
+	}
+
+	ctx := core.NewContext(content)
+	if err := pipeline.Execute(ctx); err != nil {
+		return failed(err)
+	}
+
+	result, ok := ctx.GetData().(*moderation.ModerationResult)
+	if !ok {
+		return failed(fmt.Errorf("expected *moderation.ModerationResult, got %T", ctx.GetData()))
+	}
+
+	score := result.Decision.Score
+	return []string{
+		content.ID,
+		result.Decision.Action,
+		strconv.FormatBool(result.Decision.Flagged),
+		formatScore(score.OverallScore),
+		formatScore(score.ProfanityScore),
+		formatScore(score.SpamScore),
+		formatScore(score.ToxicityScore),
+		result.Decision.Reason,
+		"",
+	}
+}
+
+// field returns the value at index i, or an empty string if the column is absent
+func field(record []string, i int) string {
+	if i < 0 || i >= len(record) {
+		return ""
+	}
+	return record[i]
+}
+
+// formatScore formats a score with four decimal places
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', 4, 64)
+}
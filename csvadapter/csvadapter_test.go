@@ -0,0 +1,90 @@
+package csvadapter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+	"github.com/dvictor357/pipeline-plugin-system/moderation"
+)
+
+// moderationPipeline returns the standard moderation pipeline.
+func moderationPipeline() *core.Pipeline {
+	return core.NewPipeline(core.AbortOnError).
+		Use(moderation.NewProfanityFilterPlugin()).
+		Use(moderation.NewSpamDetectorPlugin()).
+		Use(moderation.NewSentimentAnalyzerPlugin()).
+		Use(moderation.NewScoringPlugin()).
+		Use(moderation.NewDecisionRouterPlugin()).
+		Use(moderation.NewActionHandlerPlugin())
+}
+
+// process runs input through Process and parses the output rows.
+func process(t *testing.T, input string, mapping ColumnMapping) [][]string {
+	t.Helper()
+	var out bytes.Buffer
+	if err := Process(strings.NewReader(input), &out, moderationPipeline(), mapping); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("parsing output: %v", err)
+	}
+	return rows
+}
+
+func TestProcess(t *testing.T) {
+	input := "id,text,author_id,timestamp\n" +
+		"1,Have a lovely day,alice,2024-01-02T03:04:05Z\n" +
+		"2,\"badword1 offensive vulgar obscene explicit\",bob,\n" +
+		"3,hello,carol,yesterday\n"
+
+	rows := process(t, input, DefaultColumnMapping())
+	if len(rows) != 4 {
+		t.Fatalf("rows = %d, want header and 3 results", len(rows))
+	}
+	if !reflect.DeepEqual(rows[0], OutputColumns) {
+		t.Errorf("header = %v, want %v", rows[0], OutputColumns)
+	}
+
+	if rows[1][0] != "1" || rows[1][1] != "approve" || rows[1][2] != "false" {
+		t.Errorf("clean row = %v, want id 1 approved and not flagged", rows[1])
+	}
+	if rows[2][0] != "2" || rows[2][1] == "approve" || rows[2][2] != "true" {
+		t.Errorf("profane row = %v, want id 2 flagged", rows[2])
+	}
+	if rows[3][0] != "3" || !strings.Contains(rows[3][8], "invalid timestamp") {
+		t.Errorf("bad timestamp row = %v, want an invalid timestamp error", rows[3])
+	}
+}
+
+func TestProcessColumnMapping(t *testing.T) {
+	input := "body,key\nHave a lovely day,42\n"
+
+	rows := process(t, input, ColumnMapping{ID: "key", Text: "body"})
+	if len(rows) != 2 || rows[1][0] != "42" || rows[1][1] != "approve" {
+		t.Errorf("rows = %v, want id 42 approved", rows)
+	}
+}
+
+func TestProcessErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"empty input", ""},
+		{"no text column", "id,body\n1,hello\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := Process(strings.NewReader(tt.input), &out, moderationPipeline(), DefaultColumnMapping()); err == nil {
+				t.Error("Process succeeded, want an error")
+			}
+		})
+	}
+}
@@ -9,13 +9,15 @@ import (
 
 	"github.com/dvictor357/pipeline-plugin-system/chatbot"
 	"github.com/dvictor357/pipeline-plugin-system/core"
+	httphandler "github.com/dvictor357/pipeline-plugin-system/http"
 )
 
 // ChatRequest represents the incoming HTTP request payload
 type ChatRequest struct {
-	Text      string `json:"text"`
-	UserID    string `json:"user_id"`
-	SessionID string `json:"session_id"`
+	Text        string               `json:"text"`
+	UserID      string               `json:"user_id"`
+	SessionID   string               `json:"session_id"`
+	Attachments []chatbot.Attachment `json:"attachments,omitempty"`
 }
 
 // ChatResponse represents the HTTP response payload
@@ -31,9 +33,13 @@ type ErrorResponse struct {
 	Error string `json:"error"`
 }
 
+// typingDelay is the pause between words streamed by /chat/stream
+const typingDelay = 50 * time.Millisecond
+
 // ChatBotServer wraps the pipeline and provides HTTP endpoints
 type ChatBotServer struct {
-	pipeline *core.Pipeline
+	pipeline       *core.Pipeline
+	streamPipeline *core.Pipeline // pipeline plus a ResponseStreamPlugin, for /chat/stream
 }
 
 // NewChatBotServer creates a new chat bot server with the configured pipeline
@@ -51,7 +57,8 @@ func NewChatBotServer() *ChatBotServer {
 		}))
 
 	return &ChatBotServer{
-		pipeline: pipeline,
+		pipeline:       pipeline,
+		streamPipeline: pipeline.Clone().Use(chatbot.NewResponseStreamPlugin(typingDelay)),
 	}
 }
 
@@ -87,10 +94,11 @@ func (s *ChatBotServer) HandleChat(w http.ResponseWriter, r *http.Request) {
 
 	// Create message
 	msg := chatbot.Message{
-		Text:      req.Text,
-		UserID:    req.UserID,
-		SessionID: req.SessionID,
-		Timestamp: time.Now(),
+		Text:        req.Text,
+		UserID:      req.UserID,
+		SessionID:   req.SessionID,
+		Timestamp:   time.Now(),
+		Attachments: req.Attachments,
 	}
 
 	// Create context and execute pipeline
@@ -120,9 +128,126 @@ func (s *ChatBotServer) HandleChat(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// HandleHealth provides a health check endpoint
+// HandleChatStream processes a chat message like HandleChat, but sends the response as
+// Server-Sent Events: a "chunk" event per word as it is typed, then a "done" event with
+// the complete response
+func (s *ChatBotServer) HandleChatStream(w http.ResponseWriter, r *http.Request) {
+	// Only accept POST requests
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "Method not allowed"})
+		return
+	}
+
+	// Parse request body
+	var req ChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+
+	// Validate required fields
+	if req.Text == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "Text field is required"})
+		return
+	}
+	if req.UserID == "" {
+		req.UserID = "anonymous"
+	}
+	if req.SessionID == "" {
+		req.SessionID = "default-session"
+	}
+
+	// Create context and execute pipeline
+	ctx := core.NewContext(chatbot.Message{
+		Text:        req.Text,
+		UserID:      req.UserID,
+		SessionID:   req.SessionID,
+		Timestamp:   time.Now(),
+		Attachments: req.Attachments,
+	})
+	if err := s.streamPipeline.Execute(ctx); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: fmt.Sprintf("Pipeline error: %v", err)})
+		return
+	}
+
+	response, ok := ctx.GetData().(chatbot.Response)
+	streamData, _ := ctx.Get("response_stream")
+	stream, isStream := streamData.(chatbot.ResponseStream)
+	if !ok || !isStream {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "Unexpected response type"})
+		return
+	}
+
+	// Stream the words as they are typed, stopping if the client goes away
+	events := httphandler.NewSSEWriter(w)
+	for {
+		select {
+		case chunk, open := <-stream:
+			if !open {
+				events.Send("done", ChatResponse{
+					Text:      response.Text,
+					Intent:    response.Intent,
+					Entities:  response.Entities,
+					Timestamp: response.Timestamp,
+				})
+				return
+			}
+			if err := events.Send("chunk", chunk); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// NewWebSocketHandler creates a WebSocket handler that runs each message through the chat pipeline.
+// Messages without a session_id use the connection's session, so history is kept per connection.
+func (s *ChatBotServer) NewWebSocketHandler() *httphandler.WSHandler {
+	return httphandler.NewWSHandlerWithDecoder(s.pipeline, func(payload []byte, sessionID string) (any, error) {
+		var req ChatRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, fmt.Errorf("invalid message")
+		}
+		if req.Text == "" {
+			return nil, fmt.Errorf("text field is required")
+		}
+		if req.UserID == "" {
+			req.UserID = "anonymous"
+		}
+		if req.SessionID == "" {
+			req.SessionID = sessionID
+		}
+		return chatbot.Message{
+			Text:        req.Text,
+			UserID:      req.UserID,
+			SessionID:   req.SessionID,
+			Timestamp:   time.Now(),
+			Attachments: req.Attachments,
+		}, nil
+	})
+}
+
+// HandleHealth provides a health check endpoint. It returns 503 while a pipeline
+// dependency, such as a store, reports itself unhealthy.
 func (s *ChatBotServer) HandleHealth(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
+
+	if err := s.pipeline.Health(); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{
+			"status": "unhealthy",
+			"error":  err.Error(),
+			"time":   time.Now().Format(time.RFC3339),
+		})
+		return
+	}
+
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]string{
 		"status": "healthy",
@@ -133,9 +258,19 @@ func (s *ChatBotServer) HandleHealth(w http.ResponseWriter, r *http.Request) {
 func main() {
 	server := NewChatBotServer()
 
+	// Run expensive plugin setup now rather than on the first request
+	if err := server.pipeline.Init(); err != nil {
+		log.Fatal(err)
+	}
+	if err := server.streamPipeline.Init(); err != nil {
+		log.Fatal(err)
+	}
+
 	// Register handlers
 	http.HandleFunc("/chat", server.HandleChat)
+	http.HandleFunc("/chat/stream", server.HandleChatStream)
 	http.HandleFunc("/health", server.HandleHealth)
+	http.Handle("/ws", server.NewWebSocketHandler())
 
 	// Start server
 	port := ":8080"
@@ -147,6 +282,10 @@ func main() {
 	fmt.Println(`curl -X POST http://localhost:8080/chat \`)
 	fmt.Println(`  -H "Content-Type: application/json" \`)
 	fmt.Println(`  -d '{"text":"Hello! How are you?","user_id":"user123","session_id":"session456"}'`)
+	fmt.Println("\n# Stream the response word by word (Server-Sent Events):")
+	fmt.Println(`curl -N -X POST http://localhost:8080/chat/stream \`)
+	fmt.Println(`  -H "Content-Type: application/json" \`)
+	fmt.Println(`  -d '{"text":"Hello! How are you?","user_id":"user123","session_id":"session456"}'`)
 	fmt.Println("\n# Ask a question with entities:")
 	fmt.Println(`curl -X POST http://localhost:8080/chat \`)
 	fmt.Println(`  -H "Content-Type: application/json" \`)
@@ -159,9 +298,15 @@ func main() {
 	fmt.Println(`curl -X POST http://localhost:8080/chat \`)
 	fmt.Println(`  -H "Content-Type: application/json" \`)
 	fmt.Println(`  -d '{"text":"Thanks! Goodbye!","user_id":"user123","session_id":"session456"}'`)
+	fmt.Println("\n# Chat over WebSocket (one JSON message per line):")
+	fmt.Println("websocat ws://localhost:8080/ws")
 	fmt.Println()
 
-	log.Fatal(http.ListenAndServe(port, nil))
+	// Serve until SIGINT/SIGTERM, letting in-flight requests finish
+	if err := httphandler.RunServer(port, nil); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Server stopped")
 }
 
 /*
@@ -202,4 +347,9 @@ curl -X POST http://localhost:8080/chat \
 curl -X POST http://localhost:8080/chat \
   -H "Content-Type: application/json" \
   -d '{"text":"Thank you!","user_id":"user123","session_id":"conv789"}'
+
+# WebSocket chat (conversation history is kept for the connection)
+websocat ws://localhost:8080/ws
+{"text":"Hi there!"}
+{"text":"Can you help me?"}
 */
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleHealth(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewChatBotServer().HandleHealth(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	var status map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if rec.Code != http.StatusOK || status["status"] != "healthy" {
+		t.Errorf("health = %d %v, want 200 healthy", rec.Code, status)
+	}
+}
+
+func TestHandleChatStream(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/chat/stream", strings.NewReader(`{"text": "hi"}`))
+	rec := httptest.NewRecorder()
+	NewChatBotServer().HandleChatStream(rec, req)
+
+	if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", got)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "event: chunk\n") || !strings.Contains(body, "event: done\n") {
+		t.Errorf("stream = %q, want chunk events followed by a done event", body)
+	}
+	if strings.LastIndex(body, "event: chunk") > strings.Index(body, "event: done") {
+		t.Errorf("stream = %q, want the done event last", body)
+	}
+}
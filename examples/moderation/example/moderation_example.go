@@ -38,13 +38,23 @@ func main() {
 			expectedAction: "approve",
 		},
 		{
-			name: "Mild Issues (Review)",
+			name: "Negative but Not Toxic (Approve)",
 			content: moderation.Content{
 				ID:        "content-002",
 				Text:      "This is terrible and awful. I hate this product so much.",
 				AuthorID:  "user-456",
 				Timestamp: time.Now(),
 			},
+			expectedAction: "approve",
+		},
+		{
+			name: "Insults (Review)",
+			content: moderation.Content{
+				ID:        "content-007",
+				Text:      "You are a pathetic idiot and a worthless loser.",
+				AuthorID:  "user-troll",
+				Timestamp: time.Now(),
+			},
 			expectedAction: "review",
 		},
 		{
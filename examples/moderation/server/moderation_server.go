@@ -1,31 +1,47 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/dvictor357/pipeline-plugin-system/core"
+	httphandler "github.com/dvictor357/pipeline-plugin-system/http"
 	"github.com/dvictor357/pipeline-plugin-system/moderation"
 )
 
+// Batch processing limits
+const (
+	maxBatchSize = 100 // Maximum number of items accepted in one batch request
+	batchWorkers = 4   // Number of items moderated concurrently
+
+	maxStreamLineSize = 1 << 20 // Maximum size of one line in a streaming request
+)
+
 // ModerationRequest represents the incoming HTTP request payload
 type ModerationRequest struct {
-	ID       string `json:"id"`
-	Text     string `json:"text"`
-	AuthorID string `json:"author_id"`
+	ID          string                  `json:"id"`
+	Text        string                  `json:"text"`
+	AuthorID    string                  `json:"author_id"`
+	Attachments []moderation.Attachment `json:"attachments,omitempty"`
 }
 
 // ModerationResponse represents the HTTP response payload
 type ModerationResponse struct {
-	ContentID string                     `json:"content_id"`
-	Action    string                     `json:"action"`
-	Flagged   bool                       `json:"flagged"`
-	Reason    string                     `json:"reason"`
-	Score     moderation.ModerationScore `json:"score"`
-	Timestamp time.Time                  `json:"timestamp"`
+	ContentID   string                     `json:"content_id"`
+	Action      string                     `json:"action"`
+	Flagged     bool                       `json:"flagged"`
+	Reason      string                     `json:"reason"`
+	Score       moderation.ModerationScore `json:"score"`
+	Explanation *moderation.Explanation    `json:"explanation,omitempty"`
+	Timestamp   time.Time                  `json:"timestamp"`
+	Error       string                     `json:"error,omitempty"` // Set for failed batch items
 }
 
 // ErrorResponse represents an error response
@@ -36,20 +52,28 @@ type ErrorResponse struct {
 // ModerationServer wraps the pipeline and provides HTTP endpoints
 type ModerationServer struct {
 	pipeline *core.Pipeline
+	metrics  *moderation.Metrics
+	events   *moderation.DecisionBroker // feeds /events
+	nextID   atomic.Uint64              // numbers content submitted without an ID
 }
 
 // NewModerationServer creates a new moderation server with the configured pipeline
 func NewModerationServer() *ModerationServer {
+	events := moderation.NewDecisionBroker()
 	pipeline := core.NewPipeline(core.AbortOnError).
 		Use(moderation.NewProfanityFilterPlugin()).
 		Use(moderation.NewSpamDetectorPlugin()).
 		Use(moderation.NewSentimentAnalyzerPlugin()).
 		Use(moderation.NewScoringPlugin()).
 		Use(moderation.NewDecisionRouterPlugin()).
-		Use(moderation.NewActionHandlerPlugin())
+		Use(moderation.NewActionHandlerPluginWithConfig(moderation.ActionHandlerConfig{
+			Publisher: events,
+		}))
 
 	return &ModerationServer{
 		pipeline: pipeline,
+		metrics:  moderation.NewMetrics(),
+		events:   events,
 	}
 }
 
@@ -76,8 +100,142 @@ func (s *ModerationServer) HandleModerate(w http.ResponseWriter, r *http.Request
 		json.NewEncoder(w).Encode(ErrorResponse{Error: "Text field is required"})
 		return
 	}
+
+	response, err := s.moderate(req)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	// Send response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// HandleModerateBatch processes a JSON array of content items and returns the results in the same order.
+// Items are moderated concurrently by a bounded pool of workers; an item that fails carries its
+// error in the response instead of failing the whole batch.
+func (s *ModerationServer) HandleModerateBatch(w http.ResponseWriter, r *http.Request) {
+	// Only accept POST requests
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "Method not allowed"})
+		return
+	}
+
+	// Parse request body
+	var reqs []ModerationRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	if len(reqs) > maxBatchSize {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: fmt.Sprintf("Batch exceeds %d items", maxBatchSize)})
+		return
+	}
+
+	// Moderate items with a bounded worker pool, writing each result to its own slot
+	responses := make([]ModerationResponse, len(reqs))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+
+	for worker := 0; worker < batchWorkers; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				responses[i] = s.moderateBatchItem(reqs[i])
+			}
+		}()
+	}
+
+	for i := range reqs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	// Send response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(responses)
+}
+
+// HandleModerateStream moderates a newline-delimited JSON stream, writing one JSON result
+// line per input line and flushing each result as soon as it is ready. The request is read
+// line by line rather than buffered, so a client can keep streaming content indefinitely.
+// A malformed line produces a result carrying the error and processing continues.
+func (s *ModerationServer) HandleModerateStream(w http.ResponseWriter, r *http.Request) {
+	// Only accept POST requests
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "Method not allowed"})
+		return
+	}
+
+	// Allow writing results while the request body is still being read
+	controller := http.NewResponseController(w)
+	controller.EnableFullDuplex()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(w)
+
+	// Lines are decoded individually so a malformed line can be skipped;
+	// a single json.Decoder cannot resynchronize after a syntax error
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLineSize)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var response ModerationResponse
+		var req ModerationRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			response = ModerationResponse{Error: "Invalid JSON line"}
+		} else {
+			response = s.moderateBatchItem(req)
+		}
+
+		if err := encoder.Encode(response); err != nil {
+			return
+		}
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		encoder.Encode(ModerationResponse{Error: fmt.Sprintf("Stream aborted: %v", err)})
+		controller.Flush()
+	}
+}
+
+// moderateBatchItem moderates a single batch item, reporting failures in the response
+func (s *ModerationServer) moderateBatchItem(req ModerationRequest) ModerationResponse {
+	if req.Text == "" {
+		return ModerationResponse{ContentID: req.ID, Error: "Text field is required"}
+	}
+
+	response, err := s.moderate(req)
+	if err != nil {
+		return ModerationResponse{ContentID: req.ID, Error: err.Error()}
+	}
+	return response
+}
+
+// moderate runs a single request through the pipeline, filling in defaults for optional fields
+func (s *ModerationServer) moderate(req ModerationRequest) (ModerationResponse, error) {
 	if req.ID == "" {
-		req.ID = fmt.Sprintf("content-%d", time.Now().Unix())
+		// The counter keeps IDs unique within a batch; the time keeps them unique across restarts
+		req.ID = fmt.Sprintf("content-%d-%d", time.Now().Unix(), s.nextID.Add(1))
 	}
 	if req.AuthorID == "" {
 		req.AuthorID = "anonymous"
@@ -85,44 +243,76 @@ func (s *ModerationServer) HandleModerate(w http.ResponseWriter, r *http.Request
 
 	// Create content
 	content := moderation.Content{
-		ID:        req.ID,
-		Text:      req.Text,
-		AuthorID:  req.AuthorID,
-		Timestamp: time.Now(),
+		ID:          req.ID,
+		Text:        req.Text,
+		AuthorID:    req.AuthorID,
+		Timestamp:   time.Now(),
+		Attachments: req.Attachments,
 	}
 
 	// Create context and execute pipeline
 	ctx := core.NewContext(&content)
+	start := time.Now()
 	if err := s.pipeline.Execute(ctx); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(ErrorResponse{Error: fmt.Sprintf("Pipeline error: %v", err)})
-		return
+		s.metrics.ObserveError(time.Since(start))
+		return ModerationResponse{}, fmt.Errorf("Pipeline error: %v", err)
 	}
+	duration := time.Since(start)
 
 	// Extract result
 	result, ok := ctx.GetData().(*moderation.ModerationResult)
 	if !ok {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(ErrorResponse{Error: "Unexpected result type"})
-		return
+		s.metrics.ObserveError(duration)
+		return ModerationResponse{}, fmt.Errorf("Unexpected result type")
 	}
+	s.metrics.ObserveDecision(result.Decision.Action, duration)
 
-	// Send response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(ModerationResponse{
-		ContentID: result.Content.ID,
-		Action:    result.Decision.Action,
-		Flagged:   result.Decision.Flagged,
-		Reason:    result.Decision.Reason,
-		Score:     result.Decision.Score,
-		Timestamp: result.Content.Timestamp,
-	})
+	return ModerationResponse{
+		ContentID:   result.Content.ID,
+		Action:      result.Decision.Action,
+		Flagged:     result.Decision.Flagged,
+		Reason:      result.Decision.Reason,
+		Score:       result.Decision.Score,
+		Explanation: &result.Explanation,
+		Timestamp:   result.Content.Timestamp,
+	}, nil
 }
 
-// HandleHealth provides a health check endpoint
+// HandleEvents streams every completed moderation decision as a Server-Sent Event named
+// "decision" until the client disconnects. Clients that fall too far behind miss events
+// instead of slowing down moderation.
+func (s *ModerationServer) HandleEvents(w http.ResponseWriter, r *http.Request) {
+	results, unsubscribe := s.events.Subscribe(moderation.DefaultSubscriberBuffer)
+	defer unsubscribe()
+
+	events := httphandler.NewSSEWriter(w)
+	for {
+		select {
+		case result := <-results:
+			if err := events.Send("decision", result); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// HandleHealth provides a health check endpoint. It returns 503 while a pipeline
+// dependency, such as a store, reports itself unhealthy.
 func (s *ModerationServer) HandleHealth(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
+
+	if err := s.pipeline.Health(); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{
+			"status": "unhealthy",
+			"error":  err.Error(),
+			"time":   time.Now().Format(time.RFC3339),
+		})
+		return
+	}
+
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]string{
 		"status": "healthy",
@@ -133,9 +323,18 @@ func (s *ModerationServer) HandleHealth(w http.ResponseWriter, r *http.Request)
 func main() {
 	server := NewModerationServer()
 
+	// Run expensive plugin setup now rather than on the first request
+	if err := server.pipeline.Init(); err != nil {
+		log.Fatal(err)
+	}
+
 	// Register handlers
 	http.HandleFunc("/moderate", server.HandleModerate)
+	http.HandleFunc("/moderate/batch", server.HandleModerateBatch)
+	http.HandleFunc("/moderate/stream", server.HandleModerateStream)
+	http.HandleFunc("/events", server.HandleEvents)
 	http.HandleFunc("/health", server.HandleHealth)
+	http.Handle("/metrics", server.metrics)
 
 	// Start server
 	port := ":8081"
@@ -143,14 +342,18 @@ func main() {
 	fmt.Println("\nExample curl commands:")
 	fmt.Println("\n# Health check:")
 	fmt.Println("curl http://localhost:8081/health")
+	fmt.Println("\n# Live feed of moderation decisions (Server-Sent Events):")
+	fmt.Println("curl -N http://localhost:8081/events")
+	fmt.Println("\n# Prometheus metrics:")
+	fmt.Println("curl http://localhost:8081/metrics")
 	fmt.Println("\n# Moderate clean content (should approve):")
 	fmt.Println(`curl -X POST http://localhost:8081/moderate \`)
 	fmt.Println(`  -H "Content-Type: application/json" \`)
 	fmt.Println(`  -d '{"text":"This is a great product! I love it.","author_id":"user123","id":"content-001"}'`)
-	fmt.Println("\n# Moderate negative content (should review):")
+	fmt.Println("\n# Moderate insulting content (should review):")
 	fmt.Println(`curl -X POST http://localhost:8081/moderate \`)
 	fmt.Println(`  -H "Content-Type: application/json" \`)
-	fmt.Println(`  -d '{"text":"This is terrible and awful. I hate this.","author_id":"user456","id":"content-002"}'`)
+	fmt.Println(`  -d '{"text":"You are a pathetic idiot and a worthless loser.","author_id":"user456","id":"content-002"}'`)
 	fmt.Println("\n# Moderate profane content (should reject):")
 	fmt.Println(`curl -X POST http://localhost:8081/moderate \`)
 	fmt.Println(`  -H "Content-Type: application/json" \`)
@@ -159,9 +362,20 @@ func main() {
 	fmt.Println(`curl -X POST http://localhost:8081/moderate \`)
 	fmt.Println(`  -H "Content-Type: application/json" \`)
 	fmt.Println(`  -d '{"text":"BUY NOW!!! https://spam.com https://scam.com https://fake.com https://bad.com","author_id":"spammer","id":"content-004"}'`)
+	fmt.Println("\n# Moderate a batch of content:")
+	fmt.Println(`curl -X POST http://localhost:8081/moderate/batch \`)
+	fmt.Println(`  -H "Content-Type: application/json" \`)
+	fmt.Println(`  -d '[{"id":"a","text":"Great product!"},{"id":"b","text":"offensive vulgar obscene explicit content"}]'`)
+	fmt.Println("\n# Moderate a JSON-lines stream:")
+	fmt.Println(`printf '{"id":"a","text":"Great product!"}\n{"id":"b","text":"vulgar obscene"}\n' | \`)
+	fmt.Println(`  curl -N -X POST http://localhost:8081/moderate/stream -H "Content-Type: application/x-ndjson" --data-binary @-`)
 	fmt.Println()
 
-	log.Fatal(http.ListenAndServe(port, nil))
+	// Serve until SIGINT/SIGTERM, letting in-flight requests finish
+	if err := httphandler.RunServer(port, nil); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Server stopped")
 }
 
 /*
@@ -170,15 +384,18 @@ Example curl commands for testing:
 # Health check
 curl http://localhost:8081/health
 
+# Prometheus metrics (decision counters and pipeline latency histogram)
+curl http://localhost:8081/metrics
+
 # Clean content (approve)
 curl -X POST http://localhost:8081/moderate \
   -H "Content-Type: application/json" \
   -d '{"text":"This is a great product! I love it and would recommend it.","author_id":"user123","id":"content-001"}'
 
-# Negative sentiment (review)
+# Insults (review)
 curl -X POST http://localhost:8081/moderate \
   -H "Content-Type: application/json" \
-  -d '{"text":"This is terrible and awful. I hate this product so much.","author_id":"user456","id":"content-002"}'
+  -d '{"text":"You are a pathetic idiot and a worthless loser.","author_id":"user456","id":"content-002"}'
 
 # Profanity (reject)
 curl -X POST http://localhost:8081/moderate \
@@ -209,4 +426,14 @@ curl -X POST http://localhost:8081/moderate \
 curl -X POST http://localhost:8081/moderate \
   -H "Content-Type: application/json" \
   -d '{"text":"This product is obscene and profanity filled with badword2 content.","author_id":"test-user"}'
+
+# Batch moderation (results are returned in request order)
+curl -X POST http://localhost:8081/moderate/batch \
+  -H "Content-Type: application/json" \
+  -d '[{"id":"a","text":"Great product!"},{"id":"b","text":"offensive vulgar obscene explicit content"},{"id":"c","text":""}]'
+
+# Streaming moderation (one JSON object per line in, one result per line out)
+printf '{"id":"a","text":"Great product!"}\nnot json\n{"id":"b","text":"vulgar obscene"}\n' | \
+  curl -N -X POST http://localhost:8081/moderate/stream \
+  -H "Content-Type: application/x-ndjson" --data-binary @-
 */
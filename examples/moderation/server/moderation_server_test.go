@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleModerateBatchUniqueIDs(t *testing.T) {
+	server := NewModerationServer()
+	body := `[{"text": "hello there"}, {"text": "great post"}, {"text": "have a nice day"}, {"id": "given", "text": "hi"}]`
+	req := httptest.NewRequest(http.MethodPost, "/moderate/batch", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	server.HandleModerateBatch(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	var responses []ModerationResponse
+	if err := json.NewDecoder(rec.Body).Decode(&responses); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(responses) != 4 {
+		t.Fatalf("got %d responses, want 4", len(responses))
+	}
+
+	seen := make(map[string]bool)
+	for _, response := range responses {
+		if response.Error != "" {
+			t.Errorf("item %q failed: %s", response.ContentID, response.Error)
+		}
+		if response.ContentID == "" || seen[response.ContentID] {
+			t.Errorf("content ID %q is empty or duplicated", response.ContentID)
+		}
+		seen[response.ContentID] = true
+	}
+	if responses[3].ContentID != "given" {
+		t.Errorf("content ID = %q, want the submitted ID to be kept", responses[3].ContentID)
+	}
+}
+
+func TestHandleModerateMetrics(t *testing.T) {
+	server := NewModerationServer()
+	for _, text := range []string{"hello there", "have a nice day"} {
+		req := httptest.NewRequest(http.MethodPost, "/moderate", strings.NewReader(`{"text": "`+text+`"}`))
+		rec := httptest.NewRecorder()
+		server.HandleModerate(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	server.metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		`moderation_decisions_total{action="approve"} 2`,
+		`moderation_pipeline_duration_seconds_count 2`,
+	} {
+		if !strings.Contains(body, want+"\n") {
+			t.Errorf("scrape is missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestHandleModerateStream(t *testing.T) {
+	server := NewModerationServer()
+	body := `{"id": "a", "text": "hello there"}` + "\n" +
+		"\n" +
+		`not json` + "\n" +
+		`{"id": "b"}` + "\n"
+	req := httptest.NewRequest(http.MethodPost, "/moderate/stream", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	server.HandleModerateStream(rec, req)
+
+	if got := rec.Header().Get("Content-Type"); got != "application/x-ndjson" {
+		t.Errorf("Content-Type = %q, want application/x-ndjson", got)
+	}
+	decoder := json.NewDecoder(rec.Body)
+	var responses []ModerationResponse
+	for decoder.More() {
+		var response ModerationResponse
+		if err := decoder.Decode(&response); err != nil {
+			t.Fatalf("decode response line: %v", err)
+		}
+		responses = append(responses, response)
+	}
+
+	if len(responses) != 3 {
+		t.Fatalf("got %d result lines, want 3: %+v", len(responses), responses)
+	}
+	if responses[0].ContentID != "a" || responses[0].Action != "approve" || responses[0].Error != "" {
+		t.Errorf("line 1 = %+v, want a approved", responses[0])
+	}
+	if responses[1].Error != "Invalid JSON line" {
+		t.Errorf("line 2 error = %q, want Invalid JSON line", responses[1].Error)
+	}
+	if responses[2].ContentID != "b" || responses[2].Error == "" {
+		t.Errorf("line 3 = %+v, want an error for the missing text", responses[2])
+	}
+}
+
+func TestHandleModerateStreamIncremental(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(NewModerationServer().HandleModerateStream))
+	defer server.Close()
+
+	requestBody, writer := io.Pipe()
+	defer writer.Close()
+	responses := make(chan *http.Response, 1)
+	go func() {
+		resp, err := http.Post(server.URL, "application/x-ndjson", requestBody)
+		if err != nil {
+			t.Errorf("POST: %v", err)
+			close(responses)
+			return
+		}
+		responses <- resp
+	}()
+
+	var reader *bufio.Reader
+	// Each result arrives before the next line is sent
+	for _, id := range []string{"first", "second"} {
+		if _, err := io.WriteString(writer, `{"id": "`+id+`", "text": "hello"}`+"\n"); err != nil {
+			t.Fatalf("write line: %v", err)
+		}
+		if reader == nil {
+			// The response headers are sent with the first result
+			resp, ok := <-responses
+			if !ok {
+				return
+			}
+			defer resp.Body.Close()
+			reader = bufio.NewReader(resp.Body)
+		}
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			t.Fatalf("read result: %v", err)
+		}
+		var response ModerationResponse
+		if err := json.Unmarshal(line, &response); err != nil || response.ContentID != id {
+			t.Errorf("result = %s (%v), want content %q", line, err, id)
+		}
+	}
+}
+
+func TestHandleEvents(t *testing.T) {
+	server := NewModerationServer()
+	events := httptest.NewServer(http.HandlerFunc(server.HandleEvents))
+	defer events.Close()
+
+	resp, err := http.Get(events.URL)
+	if err != nil {
+		t.Fatalf("GET /events: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// The subscription exists once the headers have been sent
+	req := httptest.NewRequest(http.MethodPost, "/moderate", strings.NewReader(`{"id": "live", "text": "hello"}`))
+	server.HandleModerate(httptest.NewRecorder(), req)
+
+	reader := bufio.NewReader(resp.Body)
+	event, err := reader.ReadString('\n')
+	if err != nil || event != "event: decision\n" {
+		t.Fatalf("event line = %q (%v), want a decision event", event, err)
+	}
+	data, err := reader.ReadString('\n')
+	if err != nil || !strings.Contains(data, `"id":"live"`) {
+		t.Errorf("data line = %q (%v), want the live content", data, err)
+	}
+}
@@ -0,0 +1,97 @@
+// Package grpc serves the moderation pipeline over gRPC, following the contract in
+// moderation.proto. The generated messages and service stubs live in the moderationpb
+// package; regenerate them with go generate after changing the contract. buf.gen.yaml
+// pins the plugin versions, and buf compiles the proto, so the generated headers report
+// the protoc version as unknown.
+package grpc
+
+//go:generate go run github.com/bufbuild/buf/cmd/buf@v1.34.0 generate
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+	"github.com/dvictor357/pipeline-plugin-system/grpc/moderationpb"
+	"github.com/dvictor357/pipeline-plugin-system/moderation"
+)
+
+// ModerationService implements moderationpb.ModerationServiceServer on top of a
+// moderation pipeline
+type ModerationService struct {
+	moderationpb.UnimplementedModerationServiceServer
+
+	pipeline *core.Pipeline
+	nextID   atomic.Uint64 // numbers content submitted without an ID
+}
+
+// NewModerationService creates a new service that runs requests through the given pipeline.
+// The pipeline must end with moderation.ActionHandlerPlugin so it produces a ModerationResult.
+func NewModerationService(pipeline *core.Pipeline) *ModerationService {
+	return &ModerationService{
+		pipeline: pipeline,
+	}
+}
+
+// Register registers the service with a gRPC server, such as a *grpc.Server
+func (s *ModerationService) Register(server gogrpc.ServiceRegistrar) {
+	moderationpb.RegisterModerationServiceServer(server, s)
+}
+
+// Process moderates a single piece of content. A request without text fails with
+// codes.InvalidArgument and a pipeline failure with codes.Internal.
+func (s *ModerationService) Process(ctx context.Context, req *moderationpb.ModerationRequest) (*moderationpb.ModerationResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	if req.GetText() == "" {
+		return nil, status.Error(codes.InvalidArgument, "text is required")
+	}
+
+	// Fill in defaults for optional fields
+	content := moderation.Content{
+		ID:        req.GetId(),
+		Text:      req.GetText(),
+		AuthorID:  req.GetAuthorId(),
+		Timestamp: time.Now(),
+	}
+	if content.ID == "" {
+		content.ID = fmt.Sprintf("content-%d-%d", content.Timestamp.Unix(), s.nextID.Add(1))
+	}
+	if content.AuthorID == "" {
+		content.AuthorID = "anonymous"
+	}
+
+	// Create context and execute pipeline
+	pipelineCtx := core.NewContext(&content)
+	if err := s.pipeline.Execute(pipelineCtx); err != nil {
+		return nil, status.Errorf(codes.Internal, "pipeline error: %v", err)
+	}
+
+	// Extract result
+	result, ok := pipelineCtx.GetData().(*moderation.ModerationResult)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "unexpected result type %T", pipelineCtx.GetData())
+	}
+
+	score := result.Decision.Score
+	return &moderationpb.ModerationResponse{
+		ContentId: result.Content.ID,
+		Action:    result.Decision.Action,
+		Flagged:   result.Decision.Flagged,
+		Reason:    result.Decision.Reason,
+		Score: &moderationpb.ModerationScore{
+			ProfanityScore: score.ProfanityScore,
+			SpamScore:      score.SpamScore,
+			ToxicityScore:  score.ToxicityScore,
+			OverallScore:   score.OverallScore,
+		},
+		TimestampUnixNano: result.Content.Timestamp.UnixNano(),
+	}, nil
+}
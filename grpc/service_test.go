@@ -0,0 +1,104 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+	"github.com/dvictor357/pipeline-plugin-system/grpc/moderationpb"
+	"github.com/dvictor357/pipeline-plugin-system/moderation"
+)
+
+// dialService serves a ModerationService over an in-memory connection and returns a client.
+func dialService(t *testing.T, pipeline *core.Pipeline) moderationpb.ModerationServiceClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := gogrpc.NewServer()
+	NewModerationService(pipeline).Register(server)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := gogrpc.NewClient("passthrough:///bufnet",
+		gogrpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		gogrpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return moderationpb.NewModerationServiceClient(conn)
+}
+
+// moderationPipeline returns the standard moderation pipeline.
+func moderationPipeline() *core.Pipeline {
+	return core.NewPipeline(core.AbortOnError).
+		Use(moderation.NewProfanityFilterPlugin()).
+		Use(moderation.NewSpamDetectorPlugin()).
+		Use(moderation.NewSentimentAnalyzerPlugin()).
+		Use(moderation.NewScoringPlugin()).
+		Use(moderation.NewDecisionRouterPlugin()).
+		Use(moderation.NewActionHandlerPlugin())
+}
+
+func TestProcess(t *testing.T) {
+	client := dialService(t, moderationPipeline())
+
+	tests := []struct {
+		text    string
+		action  string
+		flagged bool
+	}{
+		{"Thanks, this is a great and helpful post!", "approve", false},
+		{"badword1 badword2 offensive vulgar obscene explicit", "review", true},
+	}
+	for _, test := range tests {
+		resp, err := client.Process(context.Background(), &moderationpb.ModerationRequest{Id: "c1", Text: test.text})
+		if err != nil {
+			t.Fatalf("Process(%q): %v", test.text, err)
+		}
+		if resp.GetContentId() != "c1" || resp.GetAction() != test.action || resp.GetFlagged() != test.flagged {
+			t.Errorf("Process(%q) = %v, want action %s", test.text, resp, test.action)
+		}
+		if resp.GetScore() == nil || resp.GetTimestampUnixNano() == 0 {
+			t.Errorf("Process(%q) response is missing its score or timestamp", test.text)
+		}
+	}
+}
+
+func TestProcessDefaults(t *testing.T) {
+	client := dialService(t, moderationPipeline())
+
+	first, err := client.Process(context.Background(), &moderationpb.ModerationRequest{Text: "hello"})
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	second, err := client.Process(context.Background(), &moderationpb.ModerationRequest{Text: "hello"})
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if first.GetContentId() == "" || first.GetContentId() == second.GetContentId() {
+		t.Errorf("generated IDs %q and %q, want distinct non-empty IDs", first.GetContentId(), second.GetContentId())
+	}
+}
+
+func TestProcessErrors(t *testing.T) {
+	client := dialService(t, moderationPipeline())
+	if _, err := client.Process(context.Background(), &moderationpb.ModerationRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Process without text = %v, want InvalidArgument", err)
+	}
+
+	// A pipeline that doesn't produce a ModerationResult is an internal error
+	broken := dialService(t, core.NewPipeline(core.AbortOnError).Use(moderation.NewProfanityFilterPlugin()))
+	if _, err := broken.Process(context.Background(), &moderationpb.ModerationRequest{Text: "hello"}); status.Code(err) != codes.Internal {
+		t.Errorf("Process with an incomplete pipeline = %v, want Internal", err)
+	}
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+)
+
+// StatusError is an error that carries the HTTP status code to respond with.
+// Plugins return it to turn a failure into a client error instead of a 500.
+type StatusError struct {
+	Code int
+	Err  error
+}
+
+// NewStatusError creates a StatusError with the given status code and underlying error.
+func NewStatusError(code int, err error) *StatusError {
+	return &StatusError{
+		Code: code,
+		Err:  err,
+	}
+}
+
+// Error implements the error interface.
+func (e *StatusError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error for error chain support.
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// statusCode returns the status code of the first StatusError in err's chain,
+// or 500 Internal Server Error if there is none.
+func statusCode(err error) int {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code
+	}
+	return http.StatusInternalServerError
+}
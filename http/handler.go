@@ -2,8 +2,10 @@ package http
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/dvictor357/pipeline-plugin-system/core"
 )
@@ -11,7 +13,12 @@ import (
 // HTTPHandler adapts a Pipeline to work as an http.Handler.
 // It converts HTTP requests into pipeline Context and writes responses.
 type HTTPHandler struct {
-	pipeline *core.Pipeline
+	pipeline  *core.Pipeline
+	pattern   string
+	dataField []string
+
+	includeFields map[string]bool
+	excludeFields map[string]bool
 }
 
 // NewHTTPHandler creates a new HTTPHandler with the given pipeline.
@@ -21,9 +28,66 @@ func NewHTTPHandler(pipeline *core.Pipeline) *HTTPHandler {
 	}
 }
 
+// NewHTTPHandlerWithPattern creates a new HTTPHandler that only serves paths matching pattern.
+// Segments written as {name} match any single path segment and are stored in the
+// "path_params" metadata map. Requests whose path does not match receive a 404.
+func NewHTTPHandlerWithPattern(pattern string, pipeline *core.Pipeline) *HTTPHandler {
+	return &HTTPHandler{
+		pipeline: pipeline,
+		pattern:  pattern,
+	}
+}
+
+// WithDataField makes the value at a dot-separated field path of the JSON body, such as
+// "data" or "envelope.data", the Context data, and returns the handler for method chaining.
+// The fields next to it are stored in metadata under their own names; the keys the handler
+// sets itself, such as "headers" and "query", take precedence. Requests without the field
+// receive a 400. An empty path uses the whole body, which is the default.
+func (h *HTTPHandler) WithDataField(path string) *HTTPHandler {
+	h.dataField = nil
+	if path != "" {
+		h.dataField = strings.Split(path, ".")
+	}
+	return h
+}
+
+// WithResponseFields limits the response body to the given top-level JSON keys and returns
+// the handler for method chaining. It applies when the pipeline's data is a map or marshals
+// to a JSON object; other data is written unchanged. No fields returns everything, which is
+// the default.
+func (h *HTTPHandler) WithResponseFields(fields ...string) *HTTPHandler {
+	h.includeFields = fieldSet(fields)
+	return h
+}
+
+// WithoutResponseFields removes the given top-level JSON keys, such as internal fields,
+// from the response body and returns the handler for method chaining. Like
+// WithResponseFields, it only applies to data that marshals to a JSON object.
+func (h *HTTPHandler) WithoutResponseFields(fields ...string) *HTTPHandler {
+	h.excludeFields = fieldSet(fields)
+	return h
+}
+
 // ServeHTTP implements the http.Handler interface.
 // It extracts request data into a Context, executes the pipeline, and writes the response.
+// The request's X-Request-ID, or a generated UUID if it has none, is stored under
+// RequestIDKey and echoed in the response's X-Request-ID header.
 func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Correlate the request across logs, including error responses
+	id := requestID(r)
+	w.Header().Set(RequestIDHeader, id)
+
+	// Match path against the configured pattern, if any
+	pathParams := make(map[string]string)
+	if h.pattern != "" {
+		params, ok := matchPath(h.pattern, r.URL.Path)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		pathParams = params
+	}
+
 	// Extract request body
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -44,7 +108,20 @@ func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create Context with request data
-	ctx := core.NewContext(data)
+	var ctx *core.Context
+	if len(h.dataField) > 0 {
+		value, siblings, ok := lookupField(data, h.dataField)
+		if !ok {
+			http.Error(w, fmt.Sprintf("Missing %q field in request body", strings.Join(h.dataField, ".")), http.StatusBadRequest)
+			return
+		}
+		ctx = core.NewContext(value)
+		for key, sibling := range siblings {
+			ctx.Set(key, sibling)
+		}
+	} else {
+		ctx = core.NewContext(data)
+	}
 
 	// Extract headers into metadata
 	headers := make(map[string]any)
@@ -68,14 +145,18 @@ func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	ctx.Set("query", queryParams)
 
-	// Store HTTP method and path
+	// Store named path segments
+	ctx.Set("path_params", pathParams)
+
+	// Store HTTP method, path and request ID
 	ctx.Set("method", r.Method)
 	ctx.Set("path", r.URL.Path)
+	ctx.Set(RequestIDKey, id)
 
 	// Execute pipeline
 	if err := h.pipeline.Execute(ctx); err != nil {
-		// Pipeline execution failed
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		// Pipeline execution failed; plugins choose the status with a StatusError
+		http.Error(w, err.Error(), statusCode(err))
 		return
 	}
 
@@ -90,15 +171,85 @@ func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Drop fields clients shouldn't see
+	result, err := h.filterFields(ctx.GetData())
+	if err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
 	// Write successful response
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(ctx.GetData()); err != nil {
+	if err := json.NewEncoder(w).Encode(result); err != nil {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
 }
 
+// filterFields applies the response field lists to data. Data that doesn't marshal to a
+// JSON object is returned unchanged.
+func (h *HTTPHandler) filterFields(data any) (any, error) {
+	if h.includeFields == nil && h.excludeFields == nil {
+		return data, nil
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &fields); err != nil || fields == nil {
+		return data, nil
+	}
+
+	for key := range fields {
+		if (h.includeFields != nil && !h.includeFields[key]) || h.excludeFields[key] {
+			delete(fields, key)
+		}
+	}
+	return fields, nil
+}
+
+// fieldSet returns the fields as a set, or nil if there are none.
+func fieldSet(fields []string) map[string]bool {
+	if len(fields) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		set[field] = true
+	}
+	return set
+}
+
+// lookupField returns the value at path within data and the other fields of the object
+// holding it. Returns false if any field along the path is missing or not an object.
+func lookupField(data map[string]any, path []string) (any, map[string]any, bool) {
+	parent := data
+	for _, field := range path[:len(path)-1] {
+		child, ok := parent[field].(map[string]any)
+		if !ok {
+			return nil, nil, false
+		}
+		parent = child
+	}
+
+	last := path[len(path)-1]
+	value, exists := parent[last]
+	if !exists {
+		return nil, nil, false
+	}
+
+	siblings := make(map[string]any, len(parent)-1)
+	for key, sibling := range parent {
+		if key != last {
+			siblings[key] = sibling
+		}
+	}
+	return value, siblings, true
+}
+
 // formatErrors converts a slice of errors into a slice of error messages.
 func formatErrors(errors []error) []string {
 	messages := make([]string, len(errors))
@@ -107,3 +258,29 @@ func formatErrors(errors []error) []string {
 	}
 	return messages
 }
+
+// matchPath matches a URL path against a pattern such as "/moderate/{channel}".
+// Returns the named segment values and a boolean indicating whether the path matched.
+func matchPath(pattern, path string) (map[string]string, bool) {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(patternSegments) != len(pathSegments) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if pathSegments[i] == "" {
+				return nil, false
+			}
+			params[segment[1:len(segment)-1]] = pathSegments[i]
+			continue
+		}
+		if segment != pathSegments[i] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// pluginFunc adapts a function to the core.Plugin interface.
+type pluginFunc func(*core.Context) error
+
+func (f pluginFunc) Execute(ctx *core.Context) error { return f(ctx) }
+
+// serve runs a request with the given body through handler and returns the recorder.
+func serve(handler http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHTTPHandlerPathParams(t *testing.T) {
+	var params map[string]string
+	pipeline := core.NewPipeline(core.AbortOnError).Use(pluginFunc(func(ctx *core.Context) error {
+		params, _ = core.Value[map[string]string](ctx, "path_params")
+		return nil
+	}))
+	handler := NewHTTPHandlerWithPattern("/moderate/{channel}/{id}", pipeline)
+
+	rec := serve(handler, http.MethodPost, "/moderate/general/42", `{}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if params["channel"] != "general" || params["id"] != "42" {
+		t.Errorf("path_params = %v, want channel=general id=42", params)
+	}
+}
+
+func TestHTTPHandlerPathMismatch(t *testing.T) {
+	handler := NewHTTPHandlerWithPattern("/moderate/{channel}", core.NewPipeline(core.AbortOnError))
+
+	for _, path := range []string{"/moderate", "/moderate/", "/moderate/a/b", "/other/a"} {
+		if rec := serve(handler, http.MethodPost, path, `{}`); rec.Code != http.StatusNotFound {
+			t.Errorf("%s: status = %d, want %d", path, rec.Code, http.StatusNotFound)
+		}
+	}
+}
+
+func TestHTTPHandlerWithoutPattern(t *testing.T) {
+	var params map[string]string
+	pipeline := core.NewPipeline(core.AbortOnError).Use(pluginFunc(func(ctx *core.Context) error {
+		params, _ = core.Value[map[string]string](ctx, "path_params")
+		return nil
+	}))
+
+	rec := serve(NewHTTPHandler(pipeline), http.MethodPost, "/anything/here", `{}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if params == nil || len(params) != 0 {
+		t.Errorf("path_params = %v, want an empty map", params)
+	}
+}
+
+func TestHTTPHandlerDataField(t *testing.T) {
+	var data any
+	var version, method any
+	pipeline := core.NewPipeline(core.AbortOnError).Use(pluginFunc(func(ctx *core.Context) error {
+		data = ctx.GetData()
+		version, _ = ctx.Get("version")
+		method, _ = ctx.Get("method")
+		return nil
+	}))
+	handler := NewHTTPHandler(pipeline).WithDataField("envelope.data")
+
+	rec := serve(handler, http.MethodPost, "/", `{"envelope": {"data": {"text": "hi"}, "version": 2, "method": "spoofed"}}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if fields, ok := data.(map[string]any); !ok || fields["text"] != "hi" {
+		t.Errorf("data = %v, want the envelope.data object", data)
+	}
+	if version != float64(2) {
+		t.Errorf("version = %v, want the sibling field in metadata", version)
+	}
+	if method != http.MethodPost {
+		t.Errorf("method = %v, want the handler's own key to take precedence", method)
+	}
+
+	for _, body := range []string{`{"data": {}}`, `{"envelope": "flat"}`, `{"envelope": {}}`} {
+		if rec := serve(handler, http.MethodPost, "/", body); rec.Code != http.StatusBadRequest {
+			t.Errorf("body %s: status = %d, want 400", body, rec.Code)
+		}
+	}
+}
+
+func TestHTTPHandlerResponseFields(t *testing.T) {
+	type result struct {
+		Action   string `json:"action"`
+		Score    int    `json:"score"`
+		Internal string `json:"internal"`
+	}
+	pipeline := core.NewPipeline(core.AbortOnError).Use(pluginFunc(func(ctx *core.Context) error {
+		ctx.SetData(result{Action: "approve", Score: 3, Internal: "secret"})
+		return nil
+	}))
+
+	tests := []struct {
+		handler *HTTPHandler
+		want    string
+	}{
+		{NewHTTPHandler(pipeline), `{"action":"approve","score":3,"internal":"secret"}`},
+		{NewHTTPHandler(pipeline).WithResponseFields("action", "score"), `{"action":"approve","score":3}`},
+		{NewHTTPHandler(pipeline).WithoutResponseFields("internal"), `{"action":"approve","score":3}`},
+		{NewHTTPHandler(pipeline).WithResponseFields("action", "internal").WithoutResponseFields("internal"), `{"action":"approve"}`},
+	}
+	for i, test := range tests {
+		rec := serve(test.handler, http.MethodPost, "/", `{}`)
+		if got := strings.TrimSpace(rec.Body.String()); got != test.want {
+			t.Errorf("case %d: body = %s, want %s", i, got, test.want)
+		}
+	}
+
+	// Data that isn't a JSON object is written unchanged
+	list := core.NewPipeline(core.AbortOnError).Use(pluginFunc(func(ctx *core.Context) error {
+		ctx.SetData([]int{1, 2})
+		return nil
+	}))
+	rec := serve(NewHTTPHandler(list).WithResponseFields("action"), http.MethodPost, "/", `{}`)
+	if got := strings.TrimSpace(rec.Body.String()); got != "[1,2]" {
+		t.Errorf("body = %s, want [1,2]", got)
+	}
+}
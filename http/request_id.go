@@ -0,0 +1,32 @@
+package http
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+// RequestIDHeader is the header carrying the correlation ID of a request.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDKey is the metadata key under which HTTPHandler stores the request ID, so
+// plugins can include it in their logs.
+const RequestIDKey = "request_id"
+
+// requestID returns the request's X-Request-ID header, or a new random UUID if it has none.
+func requestID(r *http.Request) string {
+	if id := r.Header.Get(RequestIDHeader); id != "" {
+		return id
+	}
+	return newUUID()
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	var b [16]byte
+	// Read only fails if the OS has no randomness source, which crashes the program
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,39 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+func TestHTTPHandlerRequestID(t *testing.T) {
+	var seen string
+	pipeline := core.NewPipeline(core.AbortOnError).Use(pluginFunc(func(ctx *core.Context) error {
+		seen, _ = core.Value[string](ctx, RequestIDKey)
+		return nil
+	}))
+	handler := NewHTTPHandler(pipeline)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+	req.Header.Set(RequestIDHeader, "abc-123")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if seen != "abc-123" || rec.Header().Get(RequestIDHeader) != "abc-123" {
+		t.Errorf("request ID = %q, header = %q, want the client's abc-123", seen, rec.Header().Get(RequestIDHeader))
+	}
+
+	// Without a header a UUID is generated, even for rejected requests
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	rec = serve(handler, http.MethodPost, "/", `{}`)
+	if id := rec.Header().Get(RequestIDHeader); !uuid.MatchString(id) || id != seen {
+		t.Errorf("generated request ID = %q (plugin saw %q), want a matching version 4 UUID", id, seen)
+	}
+	rec = serve(handler, http.MethodPost, "/", `not json`)
+	if rec.Code != http.StatusBadRequest || !uuid.MatchString(rec.Header().Get(RequestIDHeader)) {
+		t.Errorf("status = %d, request ID = %q, want a 400 with a request ID", rec.Code, rec.Header().Get(RequestIDHeader))
+	}
+}
@@ -0,0 +1,56 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// shutdownTimeout bounds how long in-flight requests may take to drain on shutdown.
+const shutdownTimeout = 30 * time.Second
+
+// RunServer serves handler on addr until the process receives SIGINT or SIGTERM, then
+// shuts down gracefully: it stops accepting connections and waits for in-flight requests,
+// and the pipelines they are running, to finish. Returns nil after a clean shutdown.
+func RunServer(addr string, handler http.Handler) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return Serve(ctx, addr, handler)
+}
+
+// Serve serves handler on addr until ctx is done, then shuts down gracefully like RunServer.
+// If in-flight requests do not finish within 30 seconds, Serve returns the shutdown error.
+// Hijacked connections, such as WebSockets, are not waited for.
+func Serve(ctx context.Context, addr string, handler http.Handler) error {
+	server := &http.Server{
+		Addr:    addr,
+		Handler: handler,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		// The server failed before shutdown was requested, e.g. the address is in use
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SSEWriter writes Server-Sent Events to an HTTP response, flushing each event so the
+// client receives it immediately. Event data is encoded as JSON on a single line.
+type SSEWriter struct {
+	w          http.ResponseWriter
+	controller *http.ResponseController
+}
+
+// NewSSEWriter writes and flushes the event stream headers with a 200 status and returns
+// a writer for the events. Flushing lets clients see the stream open before the first event.
+func NewSSEWriter(w http.ResponseWriter) *SSEWriter {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	controller := http.NewResponseController(w)
+	controller.Flush()
+
+	return &SSEWriter{
+		w:          w,
+		controller: controller,
+	}
+}
+
+// Send writes an event with the given name and JSON-encoded data and flushes it.
+// An empty event name sends an unnamed event, which clients receive as "message".
+func (s *SSEWriter) Send(event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode event data: %w", err)
+	}
+
+	if event != "" {
+		if _, err := fmt.Fprintf(s.w, "event: %s\n", event); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(s.w, "data: %s\n\n", payload); err != nil {
+		return err
+	}
+	return s.controller.Flush()
+}
@@ -0,0 +1,33 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSSEWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	events := NewSSEWriter(rec)
+	if err := events.Send("chunk", map[string]string{"text": "Hi "}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if err := events.Send("", "done"); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "text/event-stream" {
+		t.Errorf("status = %d, Content-Type = %q, want an event stream", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !rec.Flushed {
+		t.Error("events were not flushed")
+	}
+	want := "event: chunk\ndata: {\"text\":\"Hi \"}\n\n" + "data: \"done\"\n\n"
+	if got := rec.Body.String(); got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+
+	if err := events.Send("bad", make(chan int)); err == nil {
+		t.Error("Send with unencodable data succeeded, want an error")
+	}
+}
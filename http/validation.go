@@ -0,0 +1,141 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// FieldType is the JSON type expected for a request field.
+type FieldType int
+
+const (
+	// FieldAny accepts a value of any type.
+	FieldAny FieldType = iota
+	// FieldString expects a JSON string.
+	FieldString
+	// FieldNumber expects a JSON number.
+	FieldNumber
+	// FieldBool expects a JSON boolean.
+	FieldBool
+	// FieldObject expects a JSON object.
+	FieldObject
+	// FieldArray expects a JSON array.
+	FieldArray
+)
+
+// String returns the JSON name of the field type.
+func (t FieldType) String() string {
+	switch t {
+	case FieldAny:
+		return "any"
+	case FieldString:
+		return "string"
+	case FieldNumber:
+		return "number"
+	case FieldBool:
+		return "boolean"
+	case FieldObject:
+		return "object"
+	case FieldArray:
+		return "array"
+	default:
+		return fmt.Sprintf("FieldType(%d)", int(t))
+	}
+}
+
+// FieldRule describes one field of a JSON request body.
+type FieldRule struct {
+	Name     string
+	Type     FieldType
+	Required bool
+}
+
+// ValidationPlugin checks a decoded JSON request body against a set of field rules.
+// It is meant to run first in pipelines served by HTTPHandler, so malformed input is
+// rejected with 400 Bad Request before reaching plugins that assert on field types.
+type ValidationPlugin struct {
+	rules []FieldRule
+}
+
+// NewValidationPlugin creates a ValidationPlugin that enforces the given rules.
+func NewValidationPlugin(rules ...FieldRule) *ValidationPlugin {
+	return &ValidationPlugin{
+		rules: rules,
+	}
+}
+
+// Execute validates the Context data, which must be a map[string]any.
+// All rule violations are reported together in a StatusError with code 400.
+// Null values are treated as missing.
+func (p *ValidationPlugin) Execute(ctx *core.Context) error {
+	data, ok := ctx.GetData().(map[string]any)
+	if !ok {
+		return NewStatusError(http.StatusBadRequest, fmt.Errorf("expected JSON object, got %T", ctx.GetData()))
+	}
+
+	problems := make([]string, 0)
+	for _, rule := range p.rules {
+		value, exists := data[rule.Name]
+		if !exists || value == nil {
+			if rule.Required {
+				problems = append(problems, fmt.Sprintf("missing required field %q", rule.Name))
+			}
+			continue
+		}
+		if !matchesFieldType(value, rule.Type) {
+			problems = append(problems, fmt.Sprintf("field %q must be %s, got %s", rule.Name, rule.Type, jsonTypeName(value)))
+		}
+	}
+
+	if len(problems) > 0 {
+		return NewStatusError(http.StatusBadRequest, errors.New("validation failed: "+strings.Join(problems, "; ")))
+	}
+	return nil
+}
+
+// matchesFieldType reports whether a value decoded by encoding/json has the given type.
+func matchesFieldType(value any, fieldType FieldType) bool {
+	switch fieldType {
+	case FieldString:
+		_, ok := value.(string)
+		return ok
+	case FieldNumber:
+		_, ok := value.(float64)
+		return ok
+	case FieldBool:
+		_, ok := value.(bool)
+		return ok
+	case FieldObject:
+		_, ok := value.(map[string]any)
+		return ok
+	case FieldArray:
+		_, ok := value.([]any)
+		return ok
+	default:
+		return true
+	}
+}
+
+// jsonTypeName returns the JSON type name of a value decoded by encoding/json.
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case string:
+		return FieldString.String()
+	case float64:
+		return FieldNumber.String()
+	case bool:
+		return FieldBool.String()
+	case map[string]any:
+		return FieldObject.String()
+	case []any:
+		return FieldArray.String()
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
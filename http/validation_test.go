@@ -0,0 +1,90 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+func TestValidationPlugin(t *testing.T) {
+	plugin := NewValidationPlugin(
+		FieldRule{Name: "text", Type: FieldString, Required: true},
+		FieldRule{Name: "count", Type: FieldNumber},
+		FieldRule{Name: "tags", Type: FieldArray},
+	)
+
+	tests := []struct {
+		name    string
+		data    any
+		wantErr string
+	}{
+		{"valid", map[string]any{"text": "hi", "count": 2.0, "tags": []any{"a"}}, ""},
+		{"optional fields omitted", map[string]any{"text": "hi"}, ""},
+		{"missing required", map[string]any{"count": 2.0}, `missing required field "text"`},
+		{"null required", map[string]any{"text": nil}, `missing required field "text"`},
+		{"wrong type", map[string]any{"text": 42.0}, `field "text" must be string, got number`},
+		{"not an object", []any{"text"}, "expected JSON object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := plugin.Execute(core.NewContext(tt.data))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Execute: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want it to contain %q", err, tt.wantErr)
+			}
+			var statusErr *StatusError
+			if !errors.As(err, &statusErr) || statusErr.Code != http.StatusBadRequest {
+				t.Errorf("err = %#v, want a StatusError with code 400", err)
+			}
+		})
+	}
+}
+
+func TestValidationPluginReportsAllProblems(t *testing.T) {
+	plugin := NewValidationPlugin(
+		FieldRule{Name: "text", Type: FieldString, Required: true},
+		FieldRule{Name: "flag", Type: FieldBool},
+	)
+
+	err := plugin.Execute(core.NewContext(map[string]any{"flag": "yes"}))
+	if err == nil {
+		t.Fatal("Execute succeeded, want a validation error")
+	}
+	for _, want := range []string{`missing required field "text"`, `field "flag" must be boolean, got string`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %q, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestHTTPHandlerValidationStatus(t *testing.T) {
+	pipeline := core.NewPipeline(core.AbortOnError).
+		Use(NewValidationPlugin(FieldRule{Name: "text", Type: FieldString, Required: true}))
+	handler := NewHTTPHandler(pipeline)
+
+	if rec := serve(handler, http.MethodPost, "/", `{"text": 1}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid body: status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if rec := serve(handler, http.MethodPost, "/", `{"text": "hi"}`); rec.Code != http.StatusOK {
+		t.Errorf("valid body: status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestStatusCode(t *testing.T) {
+	wrapped := errors.Join(errors.New("other"), NewStatusError(http.StatusConflict, errors.New("conflict")))
+	if got := statusCode(wrapped); got != http.StatusConflict {
+		t.Errorf("statusCode(wrapped StatusError) = %d, want %d", got, http.StatusConflict)
+	}
+	if got := statusCode(errors.New("boom")); got != http.StatusInternalServerError {
+		t.Errorf("statusCode(plain error) = %d, want %d", got, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,330 @@
+package http
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// WebSocket opcodes (RFC 6455 section 5.2)
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+// wsAcceptGUID is appended to the client key when computing Sec-WebSocket-Accept
+const wsAcceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// wsMaxMessageSize limits the size of a single (possibly fragmented) message
+const wsMaxMessageSize = 1 << 20
+
+// WSDecoder converts a WebSocket message into the Context data for one pipeline execution.
+// The sessionID identifies the connection and is stable for its lifetime.
+type WSDecoder func(payload []byte, sessionID string) (any, error)
+
+// WSHandler adapts a Pipeline to a persistent WebSocket connection.
+// Each message received on the connection runs through the pipeline and the resulting
+// Context data is written back as JSON. All executions on one connection share the
+// same Context state, so stateful plugins keep their state for the whole session.
+type WSHandler struct {
+	pipeline *core.Pipeline
+	decode   WSDecoder
+}
+
+// NewWSHandler creates a new WSHandler that decodes each message as a JSON object.
+func NewWSHandler(pipeline *core.Pipeline) *WSHandler {
+	return NewWSHandlerWithDecoder(pipeline, decodeJSONMap)
+}
+
+// NewWSHandlerWithDecoder creates a new WSHandler that uses decode to build the Context data.
+func NewWSHandlerWithDecoder(pipeline *core.Pipeline, decode WSDecoder) *WSHandler {
+	return &WSHandler{
+		pipeline: pipeline,
+		decode:   decode,
+	}
+}
+
+// ServeHTTP implements the http.Handler interface.
+// It upgrades the connection and processes messages until the client disconnects.
+func (h *WSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgradeWebSocket(w, r)
+	if errors.Is(err, errHandshakeWrite) {
+		// The connection was hijacked and closed, so w can no longer be written to
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer conn.Close()
+
+	sessionID := newSessionID()
+	session := core.NewContext(nil)
+
+	for {
+		payload, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		data, err := h.decode(payload, sessionID)
+		if err != nil {
+			if err := conn.WriteJSON(map[string]any{"error": err.Error()}); err != nil {
+				return
+			}
+			continue
+		}
+
+		// Create Context sharing the session state
+		ctx := session.WithData(data)
+		ctx.Set("session_id", sessionID)
+
+		if err := conn.WriteJSON(h.execute(ctx)); err != nil {
+			return
+		}
+	}
+}
+
+// execute runs the pipeline and returns the value to write back to the client.
+func (h *WSHandler) execute(ctx *core.Context) any {
+	if err := h.pipeline.Execute(ctx); err != nil {
+		return map[string]any{"error": err.Error()}
+	}
+	if len(ctx.Errors) > 0 {
+		return map[string]any{"errors": formatErrors(ctx.Errors)}
+	}
+	return ctx.GetData()
+}
+
+// decodeJSONMap is the default WSDecoder, parsing each message into a map.
+func decodeJSONMap(payload []byte, sessionID string) (any, error) {
+	data := make(map[string]any)
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, &data); err != nil {
+			return nil, errors.New("invalid JSON message")
+		}
+	}
+	return data, nil
+}
+
+// newSessionID returns a random identifier for a WebSocket connection.
+func newSessionID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "session"
+	}
+	return hex.EncodeToString(buf)
+}
+
+// wsConn is a minimal server-side WebSocket connection.
+type wsConn struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+}
+
+// errHandshakeWrite reports that the handshake response couldn't be written to the
+// hijacked connection, which upgradeWebSocket has closed.
+var errHandshakeWrite = errors.New("failed to write websocket handshake")
+
+// upgradeWebSocket validates the handshake request and switches the connection protocol.
+// Errors other than errHandshakeWrite occur before the connection is hijacked, so they can
+// still be reported with an HTTP response.
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	if r.Method != http.MethodGet {
+		return nil, errors.New("websocket upgrade requires GET")
+	}
+	if !headerHasToken(r.Header, "Connection", "upgrade") || !headerHasToken(r.Header, "Upgrade", "websocket") {
+		return nil, errors.New("websocket upgrade required")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		return nil, errors.New("unsupported websocket version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		return nil, errors.New("missing Sec-WebSocket-Key")
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, errors.New("websocket not supported by response writer")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("failed to hijack connection: %w", err)
+	}
+
+	sum := sha1.Sum([]byte(key + wsAcceptGUID))
+	accept := base64.StdEncoding.EncodeToString(sum[:])
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", accept)
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("%w: %v", errHandshakeWrite, err)
+	}
+
+	return &wsConn{conn: conn, rw: rw}, nil
+}
+
+// headerHasToken reports whether a comma-separated header contains token (case-insensitive).
+func headerHasToken(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ReadMessage returns the payload of the next text or binary message.
+// Fragmented messages are reassembled and control frames are handled inline.
+// Returns io.EOF when the client closes the connection.
+func (c *wsConn) ReadMessage() ([]byte, error) {
+	var message []byte
+	started := false
+
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpClose:
+			c.writeFrame(wsOpClose, nil)
+			return nil, io.EOF
+		case wsOpText, wsOpBinary:
+			if started {
+				return nil, errors.New("websocket: new message before previous one finished")
+			}
+			started = true
+		case wsOpContinuation:
+			if !started {
+				return nil, errors.New("websocket: unexpected continuation frame")
+			}
+		default:
+			return nil, fmt.Errorf("websocket: unknown opcode %d", opcode)
+		}
+
+		if len(message)+len(payload) > wsMaxMessageSize {
+			return nil, errors.New("websocket: message too large")
+		}
+		message = append(message, payload...)
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+// readFrame reads and unmasks a single frame.
+func (c *wsConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(c.rw, header); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err := io.ReadFull(c.rw, ext); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext))
+	case 127:
+		ext := make([]byte, 8)
+		if _, err := io.ReadFull(c.rw, ext); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext)
+	}
+
+	// Clients must mask every frame (RFC 6455 section 5.1)
+	if !masked {
+		return false, 0, nil, errors.New("websocket: client frame is not masked")
+	}
+	if length > wsMaxMessageSize {
+		return false, 0, nil, errors.New("websocket: frame too large")
+	}
+
+	maskKey := make([]byte, 4)
+	if _, err := io.ReadFull(c.rw, maskKey); err != nil {
+		return false, 0, nil, err
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.rw, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= maskKey[i%4]
+	}
+
+	return fin, opcode, payload, nil
+}
+
+// WriteJSON encodes v as JSON and writes it as a single text message.
+func (c *wsConn) WriteJSON(v any) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.writeFrame(wsOpText, payload)
+}
+
+// writeFrame writes a single unmasked, final frame.
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+	length := len(payload)
+
+	switch {
+	case length < 126:
+		header = append(header, byte(length))
+	case length <= 0xFFFF:
+		header = append(header, 126, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(length))
+	default:
+		header = append(header, 127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(length))
+	}
+
+	if _, err := c.rw.Write(header); err != nil {
+		return err
+	}
+	if _, err := c.rw.Write(payload); err != nil {
+		return err
+	}
+	return c.rw.Flush()
+}
+
+// Close closes the underlying network connection.
+func (c *wsConn) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,201 @@
+package http
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// wsClient is a minimal WebSocket client for exercising WSHandler.
+type wsClient struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// dialWS performs the opening handshake against server and checks the accept key.
+func dialWS(t *testing.T, server *httptest.Server) *wsClient {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	// Key and accept value from the example in RFC 6455 section 1.3
+	fmt.Fprint(conn, "GET /chat HTTP/1.1\r\nHost: example.com\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n"+
+		"Sec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n")
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatalf("ReadResponse: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake status = %d, want %d", resp.StatusCode, http.StatusSwitchingProtocols)
+	}
+	if accept := resp.Header.Get("Sec-WebSocket-Accept"); accept != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("Sec-WebSocket-Accept = %q", accept)
+	}
+	return &wsClient{t: t, conn: conn, r: r}
+}
+
+// send writes a single masked frame.
+func (c *wsClient) send(opcode byte, payload string) {
+	c.t.Helper()
+	mask := []byte{0x12, 0x34, 0x56, 0x78}
+	frame := []byte{0x80 | opcode, 0x80 | byte(len(payload))}
+	frame = append(frame, mask...)
+	for i := 0; i < len(payload); i++ {
+		frame = append(frame, payload[i]^mask[i%4])
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		c.t.Fatalf("write frame: %v", err)
+	}
+}
+
+// read returns the opcode and payload of the next frame.
+func (c *wsClient) read() (byte, string) {
+	c.t.Helper()
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(c.r, header); err != nil {
+		c.t.Fatalf("read frame: %v", err)
+	}
+	length := int(header[1] & 0x7F)
+	if length == 126 {
+		ext := make([]byte, 2)
+		io.ReadFull(c.r, ext)
+		length = int(binary.BigEndian.Uint16(ext))
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		c.t.Fatalf("read payload: %v", err)
+	}
+	return header[0] & 0x0F, string(payload)
+}
+
+// readJSON reads the next text frame into a map.
+func (c *wsClient) readJSON() map[string]any {
+	c.t.Helper()
+	opcode, payload := c.read()
+	if opcode != wsOpText {
+		c.t.Fatalf("opcode = %d, want text", opcode)
+	}
+	var data map[string]any
+	if err := json.Unmarshal([]byte(payload), &data); err != nil {
+		c.t.Fatalf("invalid JSON %q: %v", payload, err)
+	}
+	return data
+}
+
+// countingPipeline numbers each message of a session using the shared Context state.
+func countingPipeline() *core.Pipeline {
+	return core.NewPipeline(core.AbortOnError).Use(pluginFunc(func(ctx *core.Context) error {
+		count, _ := ctx.GetState("count")
+		n, _ := count.(int)
+		ctx.SetState("count", n+1)
+
+		data := ctx.GetData().(map[string]any)
+		data["count"] = n + 1
+		data["session"], _ = ctx.Get("session_id")
+		return nil
+	}))
+}
+
+func TestWSHandlerExchange(t *testing.T) {
+	server := httptest.NewServer(NewWSHandler(countingPipeline()))
+	defer server.Close()
+	client := dialWS(t, server)
+
+	client.send(wsOpText, `{"text": "hello"}`)
+	first := client.readJSON()
+	client.send(wsOpText, `{"text": "again"}`)
+	second := client.readJSON()
+
+	if first["text"] != "hello" || first["count"] != 1.0 {
+		t.Errorf("first reply = %v, want text=hello count=1", first)
+	}
+	if second["count"] != 2.0 {
+		t.Errorf("second reply count = %v, want 2 since state is kept for the session", second["count"])
+	}
+	if first["session"] == "" || first["session"] != second["session"] {
+		t.Errorf("session IDs = %v, %v, want the same non-empty ID", first["session"], second["session"])
+	}
+
+	// A second connection starts a new session
+	other := dialWS(t, server)
+	other.send(wsOpText, `{}`)
+	if reply := other.readJSON(); reply["count"] != 1.0 || reply["session"] == first["session"] {
+		t.Errorf("new connection reply = %v, want a fresh session", reply)
+	}
+}
+
+func TestWSHandlerControlFrames(t *testing.T) {
+	server := httptest.NewServer(NewWSHandler(countingPipeline()))
+	defer server.Close()
+	client := dialWS(t, server)
+
+	client.send(wsOpPing, "are you there")
+	if opcode, payload := client.read(); opcode != wsOpPong || payload != "are you there" {
+		t.Errorf("ping reply = %d %q, want a pong echoing the payload", opcode, payload)
+	}
+
+	client.send(wsOpText, `not json`)
+	if reply := client.readJSON(); reply["error"] != "invalid JSON message" {
+		t.Errorf("reply to invalid JSON = %v, want an error", reply)
+	}
+
+	client.send(wsOpClose, "")
+	if opcode, _ := client.read(); opcode != wsOpClose {
+		t.Errorf("close reply opcode = %d, want %d", opcode, wsOpClose)
+	}
+}
+
+func TestWSHandlerRejectsPlainRequests(t *testing.T) {
+	rec := serve(NewWSHandler(countingPipeline()), http.MethodGet, "/chat", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+// hijackRecorder is a ResponseRecorder whose connection can be hijacked.
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	conn net.Conn
+}
+
+func (r *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return r.conn, bufio.NewReadWriter(bufio.NewReader(r.conn), bufio.NewWriter(r.conn)), nil
+}
+
+func TestWSHandlerHandshakeWriteFailure(t *testing.T) {
+	server, client := net.Pipe()
+	client.Close() // the handshake response can't be written
+
+	req := httptest.NewRequest(http.MethodGet, "/chat", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	rec := &hijackRecorder{ResponseRecorder: httptest.NewRecorder(), conn: server}
+
+	NewWSHandler(core.NewPipeline(core.AbortOnError)).ServeHTTP(rec, req)
+
+	// Once hijacked, the ResponseWriter must not be written to
+	if rec.Body.Len() != 0 || rec.Code != http.StatusOK {
+		t.Errorf("response written after hijack: %d %q", rec.Code, rec.Body)
+	}
+	if _, err := server.Write([]byte("x")); err == nil {
+		t.Error("hijacked connection was not closed")
+	}
+}
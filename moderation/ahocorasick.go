@@ -0,0 +1,100 @@
+package moderation
+
+// acMatcher is an Aho-Corasick automaton that finds which of a fixed set of patterns
+// occur in a text in a single pass, in time linear in the text length regardless of the
+// number of patterns. Matching is byte-wise, so results equal strings.Contains per pattern.
+type acMatcher struct {
+	nodes    []acNode
+	patterns int
+	empty    []int // indexes of empty patterns, which match every text
+}
+
+// acNode is a state of the automaton
+type acNode struct {
+	next   map[byte]int
+	fail   int
+	output []int // indexes of patterns ending at this state, including via fail links
+}
+
+// newACMatcher builds the automaton for patterns
+func newACMatcher(patterns []string) *acMatcher {
+	m := &acMatcher{
+		nodes:    []acNode{{next: make(map[byte]int)}},
+		patterns: len(patterns),
+	}
+
+	// Build the trie
+	for i, pattern := range patterns {
+		if pattern == "" {
+			m.empty = append(m.empty, i)
+			continue
+		}
+		state := 0
+		for j := 0; j < len(pattern); j++ {
+			child, ok := m.nodes[state].next[pattern[j]]
+			if !ok {
+				child = len(m.nodes)
+				m.nodes = append(m.nodes, acNode{next: make(map[byte]int)})
+				m.nodes[state].next[pattern[j]] = child
+			}
+			state = child
+		}
+		m.nodes[state].output = append(m.nodes[state].output, i)
+	}
+
+	// Compute fail links breadth-first so parents are done before children
+	queue := make([]int, 0, len(m.nodes))
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for b, child := range m.nodes[state].next {
+			fail := m.nodes[state].fail
+			for {
+				if target, ok := m.nodes[fail].next[b]; ok && target != child {
+					m.nodes[child].fail = target
+					break
+				}
+				if fail == 0 {
+					m.nodes[child].fail = 0
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+			failOutput := m.nodes[m.nodes[child].fail].output
+			m.nodes[child].output = append(m.nodes[child].output, failOutput...)
+			queue = append(queue, child)
+		}
+	}
+
+	return m
+}
+
+// matches reports, for each pattern index, whether the pattern occurs in text
+func (m *acMatcher) matches(text string) []bool {
+	found := make([]bool, m.patterns)
+	for _, i := range m.empty {
+		found[i] = true
+	}
+
+	state := 0
+	for i := 0; i < len(text); i++ {
+		b := text[i]
+		for {
+			if next, ok := m.nodes[state].next[b]; ok {
+				state = next
+				break
+			}
+			if state == 0 {
+				break
+			}
+			state = m.nodes[state].fail
+		}
+		for _, pattern := range m.nodes[state].output {
+			found[pattern] = true
+		}
+	}
+	return found
+}
@@ -0,0 +1,108 @@
+package moderation
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// containsAll is the strings.Contains loop the automaton replaced.
+func containsAll(patterns []string, text string) []bool {
+	found := make([]bool, len(patterns))
+	for i, pattern := range patterns {
+		found[i] = strings.Contains(text, pattern)
+	}
+	return found
+}
+
+func TestACMatcherMatchesContains(t *testing.T) {
+	patterns := []string{"he", "she", "his", "hers", "", "aaa", "a", "ers", "hehe", "héé"}
+	matcher := newACMatcher(patterns)
+
+	fixed := []string{"", "ushers", "ahishers", "aaaa", "hehehe", "xyz", "héé!", "h"}
+	random := rand.New(rand.NewSource(1))
+	alphabet := []rune("aehrsé ")
+	for i := 0; i < 500; i++ {
+		runes := make([]rune, random.Intn(12))
+		for j := range runes {
+			runes[j] = alphabet[random.Intn(len(alphabet))]
+		}
+		fixed = append(fixed, string(runes))
+	}
+
+	for _, text := range fixed {
+		if got, want := matcher.matches(text), containsAll(patterns, text); !reflect.DeepEqual(got, want) {
+			t.Errorf("matches(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
+
+// largeWordList returns n distinct made-up words.
+func largeWordList(n int) []string {
+	words := make([]string, n)
+	for i := range words {
+		words[i] = fmt.Sprintf("slur%dx", i)
+	}
+	return words
+}
+
+// largeText returns a long comment that contains a few of the listed words.
+func largeText(words []string) string {
+	var text strings.Builder
+	for i := 0; i < 200; i++ {
+		text.WriteString("this is a perfectly ordinary sentence about nothing much ")
+		if i%50 == 0 {
+			text.WriteString(strings.ToUpper(words[i*7]) + " ")
+		}
+	}
+	return text.String()
+}
+
+func TestProfanityFilterLargeListMatchesContains(t *testing.T) {
+	words := largeWordList(2000)
+	plugin := NewProfanityFilterPluginWithConfig(ProfanityConfig{
+		Tiers: []ProfanityTier{{Name: ProfanityTierMild, Words: words, Weight: 0.1}},
+	})
+	text := largeText(words)
+
+	ctx := core.NewContext(&Content{Text: text})
+	if err := plugin.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	got, _ := core.Value[[]string](ctx, "profanity_matches")
+
+	want := make([]string, 0)
+	for i, found := range containsAll(words, strings.ToLower(text)) {
+		if found {
+			want = append(want, words[i])
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("profanity_matches = %v, want %v", got, want)
+	}
+	if len(want) != 4 {
+		t.Errorf("test text contains %d listed words, want 4", len(want))
+	}
+}
+
+func BenchmarkProfanityMatch(b *testing.B) {
+	words := largeWordList(2000)
+	text := strings.ToLower(largeText(words))
+
+	b.Run("contains", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			containsAll(words, text)
+		}
+	})
+	b.Run("automaton", func(b *testing.B) {
+		matcher := newACMatcher(words)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			matcher.matches(text)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package moderation
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// AllowlistReason is the decision reason recorded for content from trusted authors
+const AllowlistReason = "Author is allowlisted"
+
+// AllowlistPlugin lets content from trusted authors, such as staff or verified partners,
+// skip moderation. Content from a trusted author is approved immediately and the rest of
+// the pipeline is skipped with core.ErrSkipRemaining; other content passes through.
+// Place it first so the expensive checks are skipped.
+type AllowlistPlugin struct {
+	mu      sync.RWMutex
+	authors map[string]bool
+}
+
+// NewAllowlistPlugin creates an allowlist of trusted author IDs
+func NewAllowlistPlugin(trustedAuthors []string) *AllowlistPlugin {
+	authors := make(map[string]bool, len(trustedAuthors))
+	for _, authorID := range trustedAuthors {
+		authors[authorID] = true
+	}
+
+	return &AllowlistPlugin{
+		authors: authors,
+	}
+}
+
+// Add trusts an author
+func (p *AllowlistPlugin) Add(authorID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.authors[authorID] = true
+}
+
+// Remove stops trusting an author
+func (p *AllowlistPlugin) Remove(authorID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.authors, authorID)
+}
+
+// Trusted reports whether an author is on the allowlist
+func (p *AllowlistPlugin) Trusted(authorID string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return authorID != "" && p.authors[authorID]
+}
+
+// Execute sets "allowlisted" and, for trusted authors, replaces the data with an approved
+// ModerationResult, as ActionHandlerPlugin would, and stops the pipeline
+func (p *AllowlistPlugin) Execute(ctx *core.Context) error {
+	content, ok := ctx.GetData().(*Content)
+	if !ok {
+		return fmt.Errorf("expected *Content, got %T", ctx.GetData())
+	}
+
+	trusted := p.Trusted(content.AuthorID)
+	ctx.Set("allowlisted", trusted)
+	if !trusted {
+		return nil
+	}
+
+	decideEarly(ctx, content, ModerationDecision{
+		Action: "approve",
+		Reason: AllowlistReason,
+	})
+	return core.ErrSkipRemaining
+}
+
+// Requires returns the metadata keys AllowlistPlugin reads
+func (p *AllowlistPlugin) Requires() []string { return nil }
+
+// Provides returns the metadata keys AllowlistPlugin sets, including the decision and
+// action keys recorded for trusted authors
+func (p *AllowlistPlugin) Provides() []string {
+	return []string{"allowlisted", "moderation_decision", "action_executed", "action_executed_at"}
+}
@@ -0,0 +1,45 @@
+package moderation
+
+import (
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+func TestAllowlistApprovesTrustedAuthors(t *testing.T) {
+	broker := NewDecisionBroker()
+	results, unsubscribe := broker.Subscribe(1)
+	defer unsubscribe()
+
+	allowlist := NewAllowlistPlugin([]string{"staff"})
+	pipeline := core.NewPipeline(core.AbortOnError).
+		Use(allowlist).
+		Use(NewProfanityFilterPlugin()).
+		UseFinally(NewActionHandlerPluginWithConfig(ActionHandlerConfig{Publisher: broker}))
+
+	ctx := core.NewContext(&Content{ID: "c1", Text: "badword1", AuthorID: "staff"})
+	if err := pipeline.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	result, ok := ctx.GetData().(*ModerationResult)
+	if !ok || result.Decision.Action != "approve" || result.Decision.Reason != AllowlistReason {
+		t.Fatalf("data = %+v, want an allowlist approval", ctx.GetData())
+	}
+	if _, ran := ctx.Get("profanity_score"); ran {
+		t.Error("profanity filter ran for a trusted author")
+	}
+
+	select {
+	case published := <-results:
+		if published.Content.ID != "c1" {
+			t.Errorf("published content %q, want c1", published.Content.ID)
+		}
+	default:
+		t.Error("early decision was not published")
+	}
+
+	allowlist.Remove("staff")
+	if allowlist.Trusted("staff") || allowlist.Trusted("") {
+		t.Error("removed or empty author is trusted")
+	}
+}
@@ -0,0 +1,99 @@
+package moderation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// AuditSink retains a record of each moderation decision, for compliance.
+// ActionHandlerPlugin calls Record on the pipeline's goroutine for every decision it
+// executes; an error fails the pipeline, so decisions are never executed unrecorded.
+type AuditSink interface {
+	Record(result ModerationResult) error
+}
+
+// MemoryAuditSink keeps audit records in memory, for tests and short-lived processes.
+// It is safe for concurrent use.
+type MemoryAuditSink struct {
+	mu      sync.Mutex
+	records []ModerationResult
+}
+
+// NewMemoryAuditSink creates an empty in-memory audit sink
+func NewMemoryAuditSink() *MemoryAuditSink {
+	return &MemoryAuditSink{}
+}
+
+// Record appends the result to the records
+func (s *MemoryAuditSink) Record(result ModerationResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, result)
+	return nil
+}
+
+// Records returns a copy of the records in the order they were recorded
+func (s *MemoryAuditSink) Records() []ModerationResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records := make([]ModerationResult, len(s.records))
+	copy(records, s.records)
+	return records
+}
+
+// JSONLAuditSink writes each audit record as one line of JSON. It is safe for concurrent
+// use.
+type JSONLAuditSink struct {
+	mu     sync.Mutex
+	writer io.Writer
+	closer io.Closer // set when the sink opened the file itself
+}
+
+// NewJSONLAuditSink creates an audit sink writing to w
+func NewJSONLAuditSink(w io.Writer) *JSONLAuditSink {
+	return &JSONLAuditSink{
+		writer: w,
+	}
+}
+
+// OpenJSONLAuditSink creates an audit sink appending to the file at path, creating it if
+// needed. Close the sink to close the file.
+func OpenJSONLAuditSink(path string) (*JSONLAuditSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &JSONLAuditSink{
+		writer: file,
+		closer: file,
+	}, nil
+}
+
+// Record writes the result as a line of JSON
+func (s *JSONLAuditSink) Record(result ModerationResult) error {
+	line, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+	line = append(line, '\n')
+
+	// A single write keeps concurrent records on separate lines
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.writer.Write(line); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	return nil
+}
+
+// Close closes the file opened by OpenJSONLAuditSink. It does nothing for a sink created
+// with NewJSONLAuditSink.
+func (s *JSONLAuditSink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
@@ -0,0 +1,121 @@
+package moderation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// auditPipeline returns the standard moderation pipeline recording decisions in sink.
+func auditPipeline(sink AuditSink) *core.Pipeline {
+	return core.NewPipeline(core.AbortOnError).
+		Use(NewProfanityFilterPlugin()).
+		Use(NewSpamDetectorPlugin()).
+		Use(NewSentimentAnalyzerPlugin()).
+		Use(NewScoringPlugin()).
+		Use(NewDecisionRouterPlugin()).
+		Use(NewActionHandlerPluginWithConfig(ActionHandlerConfig{Audit: sink}))
+}
+
+// failingAuditSink rejects every record.
+type failingAuditSink struct{}
+
+func (failingAuditSink) Record(ModerationResult) error { return errors.New("disk full") }
+
+func TestAuditSinkRecordsDecisions(t *testing.T) {
+	sink := NewMemoryAuditSink()
+	pipeline := auditPipeline(sink)
+
+	_, first := moderate(t, pipeline, "Hello everyone, have a nice day")
+	_, second := moderate(t, pipeline, "badword1 offensive vulgar obscene explicit")
+
+	records := sink.Records()
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	if records[0].Decision != first.Decision || records[1].Decision != second.Decision {
+		t.Errorf("records = %+v, want the decisions in order", records)
+	}
+
+	// Records returns a copy
+	records[0].Decision.Action = "changed"
+	if sink.Records()[0].Decision.Action == "changed" {
+		t.Error("Records returned the sink's own slice")
+	}
+}
+
+func TestAuditSinkSkipsDryRun(t *testing.T) {
+	sink := NewMemoryAuditSink()
+	ctx := core.NewContext(&Content{ID: "1", Text: "hello"})
+	if err := auditPipeline(sink).DryRun(ctx); err != nil {
+		t.Fatalf("DryRun: %v", err)
+	}
+	if records := sink.Records(); len(records) != 0 {
+		t.Errorf("dry run recorded %d decisions, want none", len(records))
+	}
+}
+
+func TestAuditSinkFailureFailsPipeline(t *testing.T) {
+	ctx := core.NewContext(&Content{ID: "c1", Text: "hello"})
+	err := auditPipeline(failingAuditSink{}).Execute(ctx)
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("Execute = %v, want the audit error", err)
+	}
+	if _, executed := ctx.Get("action_executed"); executed {
+		t.Error("decision executed without being audited")
+	}
+}
+
+func TestJSONLAuditSink(t *testing.T) {
+	var buf bytes.Buffer
+	pipeline := auditPipeline(NewJSONLAuditSink(&buf))
+	moderate(t, pipeline, "first message")
+	moderate(t, pipeline, "second message")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2: %q", len(lines), buf.String())
+	}
+	var record ModerationResult
+	if err := json.Unmarshal([]byte(lines[1]), &record); err != nil {
+		t.Fatalf("invalid JSON line: %v", err)
+	}
+	if record.Content.Text != "second message" {
+		t.Errorf("content = %q, want %q", record.Content.Text, "second message")
+	}
+}
+
+func TestOpenJSONLAuditSinkAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	for i := 0; i < 2; i++ {
+		sink, err := OpenJSONLAuditSink(path)
+		if err != nil {
+			t.Fatalf("OpenJSONLAuditSink: %v", err)
+		}
+		if err := sink.Record(ModerationResult{Content: Content{ID: "c1"}}); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+		if err := sink.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+	}
+
+	sink := NewJSONLAuditSink(&bytes.Buffer{})
+	if err := sink.Close(); err != nil {
+		t.Errorf("Close of a writer sink: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read audit log: %v", err)
+	}
+	if got := strings.Count(string(data), "\n"); got != 2 {
+		t.Errorf("audit log has %d lines, want 2", got)
+	}
+}
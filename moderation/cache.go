@@ -0,0 +1,20 @@
+package moderation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// ContentCacheKey is a key function for core.NewCachePlugin that hashes Content.Text.
+// Content with identical text shares a cache entry regardless of its ID or author.
+// Returns an empty key, bypassing the cache, if the data is not *Content.
+func ContentCacheKey(ctx *core.Context) string {
+	content, ok := ctx.GetData().(*Content)
+	if !ok {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(content.Text))
+	return hex.EncodeToString(sum[:])
+}
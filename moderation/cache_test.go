@@ -0,0 +1,23 @@
+package moderation
+
+import (
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+func TestContentCacheKey(t *testing.T) {
+	a := core.NewContext(&Content{ID: "1", AuthorID: "alice", Text: "hello"})
+	b := core.NewContext(&Content{ID: "2", AuthorID: "bob", Text: "hello"})
+	c := core.NewContext(&Content{ID: "3", Text: "goodbye"})
+
+	if ContentCacheKey(a) == "" || ContentCacheKey(a) != ContentCacheKey(b) {
+		t.Error("content with the same text got different keys")
+	}
+	if ContentCacheKey(a) == ContentCacheKey(c) {
+		t.Error("content with different text got the same key")
+	}
+	if key := ContentCacheKey(core.NewContext("text")); key != "" {
+		t.Errorf("key for non-Content data = %q, want empty", key)
+	}
+}
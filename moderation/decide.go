@@ -0,0 +1,42 @@
+package moderation
+
+import (
+	"time"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// Terminate records decision as the final moderation decision and returns
+// core.ErrSkipRemaining, for a plugin to return when it can decide on its own, such as
+// on a severe slur. The checks after it are skipped; ActionHandlerPlugin still finalizes
+// the result if it was added with UseFinally.
+func Terminate(ctx *core.Context, decision ModerationDecision) error {
+	ctx.Set("moderation_decision", decision)
+	return core.ErrSkipRemaining
+}
+
+// decideEarly records decision and replaces the data with its ModerationResult, as
+// DecisionRouterPlugin and ActionHandlerPlugin would, for plugins that stop the pipeline
+// with core.ErrSkipRemaining
+func decideEarly(ctx *core.Context, content *Content, decision ModerationDecision) {
+	decidedAt := time.Now()
+	ctx.Set("moderation_decision", decision)
+	ctx.SetData(&ModerationResult{
+		Content:  *content,
+		Decision: decision,
+		Explanation: Explanation{
+			ProfanityMatches: []string{},
+			SpamSignals:      []string{},
+			PositiveWords:    []string{},
+			NegativeWords:    []string{},
+		},
+		RequestID: requestIDFromContext(ctx),
+		DecidedAt: decidedAt,
+	})
+
+	// Skip side effects in dry-run mode
+	if !ctx.IsDryRun() {
+		ctx.Set("action_executed", true)
+		ctx.Set("action_executed_at", decidedAt)
+	}
+}
@@ -0,0 +1,117 @@
+package moderation
+
+import "github.com/dvictor357/pipeline-plugin-system/core"
+
+// Metadata dependencies of the moderation plugins, declared through core.DependentPlugin
+// so that core.Pipeline.Validate can detect mis-ordered pipelines.
+
+// Requires returns the metadata keys ProfanityFilterPlugin reads
+func (p *ProfanityFilterPlugin) Requires() []string { return nil }
+
+// Provides returns the metadata keys ProfanityFilterPlugin sets, including the decision
+// it records when a Reject tier matches
+func (p *ProfanityFilterPlugin) Provides() []string {
+	keys := []string{"profanity_score", "profanity_matches", core.ScoresKey}
+	if p.mask != 0 {
+		keys = append(keys, "masked_text")
+	}
+	if p.canReject() {
+		keys = append(keys, "moderation_decision")
+	}
+	return keys
+}
+
+// canReject reports whether any word or pattern belongs to a Reject tier
+func (p *ProfanityFilterPlugin) canReject() bool {
+	for _, reject := range p.rejects {
+		if reject {
+			return true
+		}
+	}
+	for _, pattern := range p.patterns {
+		if pattern.reject {
+			return true
+		}
+	}
+	return false
+}
+
+// Requires returns the metadata keys SpamDetectorPlugin reads
+func (p *SpamDetectorPlugin) Requires() []string { return nil }
+
+// Provides returns the metadata keys SpamDetectorPlugin sets
+func (p *SpamDetectorPlugin) Provides() []string {
+	return []string{"spam_score", "spam_signals", core.ScoresKey}
+}
+
+// Requires returns the metadata keys SentimentAnalyzerPlugin reads
+func (p *SentimentAnalyzerPlugin) Requires() []string { return nil }
+
+// Provides returns the metadata keys SentimentAnalyzerPlugin sets
+func (p *SentimentAnalyzerPlugin) Provides() []string {
+	return []string{"sentiment_score", "toxicity_score", "positive_words", "negative_words", "toxic_words", core.ScoresKey}
+}
+
+// Requires returns the metadata keys ScoringPlugin reads
+func (p *ScoringPlugin) Requires() []string {
+	return []string{core.ScoresKey}
+}
+
+// Provides returns the metadata keys ScoringPlugin sets
+func (p *ScoringPlugin) Provides() []string {
+	return []string{"moderation_score"}
+}
+
+// Requires returns the metadata keys DecisionRouterPlugin reads, including "topic" when
+// topic thresholds are configured and "author_reputation" when leniency is
+func (p *DecisionRouterPlugin) Requires() []string {
+	keys := []string{"moderation_score"}
+	if len(p.topicThresholds) > 0 {
+		keys = append(keys, "topic")
+	}
+	if p.leniency != 0 {
+		keys = append(keys, "author_reputation")
+	}
+	return keys
+}
+
+// Provides returns the metadata keys DecisionRouterPlugin sets
+func (p *DecisionRouterPlugin) Provides() []string {
+	return []string{"moderation_decision"}
+}
+
+// Requires returns the metadata keys ActionHandlerPlugin reads
+func (p *ActionHandlerPlugin) Requires() []string {
+	return []string{"moderation_decision"}
+}
+
+// Provides returns the metadata keys ActionHandlerPlugin sets
+func (p *ActionHandlerPlugin) Provides() []string {
+	return []string{"action_executed", "action_executed_at"}
+}
+
+// Requires returns the metadata keys URLAnalyzerPlugin reads
+func (p *URLAnalyzerPlugin) Requires() []string { return nil }
+
+// Provides returns the metadata keys URLAnalyzerPlugin sets
+func (p *URLAnalyzerPlugin) Provides() []string {
+	return []string{"extracted_urls", "url_risk_score", core.ScoresKey}
+}
+
+// Requires returns the metadata keys RateLimitPlugin reads. It raises the spam score when
+// one is set, but a missing score counts as zero, so it doesn't require SpamDetectorPlugin.
+func (p *RateLimitPlugin) Requires() []string { return nil }
+
+// Provides returns the metadata keys RateLimitPlugin sets, including the spam score and
+// signals it raises when the limit is exceeded
+func (p *RateLimitPlugin) Provides() []string {
+	return []string{"rate_limit_exceeded", "spam_score", "spam_signals", core.ScoresKey}
+}
+
+// Requires returns the metadata keys FingerprintPlugin reads
+func (p *FingerprintPlugin) Requires() []string { return nil }
+
+// Provides returns the metadata keys FingerprintPlugin sets
+func (p *FingerprintPlugin) Provides() []string {
+	return []string{"fingerprint", "near_duplicate", "duplicate_distance"}
+}
@@ -0,0 +1,100 @@
+package moderation
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+func TestModerationPipelineValidates(t *testing.T) {
+	if err := moderationPipeline().Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	misordered := core.NewPipeline(core.AbortOnError).
+		Use(NewScoringPlugin()).
+		Use(NewProfanityFilterPlugin()).
+		Use(NewDecisionRouterPlugin()).
+		Use(NewActionHandlerPlugin())
+	var dependencyErr *core.DependencyError
+	if err := misordered.Validate(); !errors.As(err, &dependencyErr) || dependencyErr.Key != core.ScoresKey {
+		t.Errorf("Validate = %v, want a missing %q dependency", err, core.ScoresKey)
+	}
+}
+
+func TestRateLimitPipelineValidatesWithoutSpamDetector(t *testing.T) {
+	pipeline := core.NewPipeline(core.AbortOnError).
+		Use(NewRateLimitPlugin(5, time.Minute)).
+		Use(NewScoringPlugin()).
+		Use(NewDecisionRouterPlugin()).
+		Use(NewActionHandlerPlugin())
+	if err := pipeline.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
+
+// TestProvidesListsEverySetKey runs each plugin down the path that sets the most keys
+// and checks that every key it set is declared in Provides.
+func TestProvidesListsEverySetKey(t *testing.T) {
+	rejectTier := ProfanityConfig{Tiers: []ProfanityTier{
+		{Name: ProfanityTierSevere, Words: []string{"slur"}, Weight: 1, Reject: true},
+	}}
+	rateLimit := NewRateLimitPlugin(1, time.Minute)
+	rateLimit.Execute(core.NewContext(&Content{Text: "first", AuthorID: "alice"}))
+	fingerprint := NewFingerprintPlugin(0, 0)
+	fingerprint.Remember(Fingerprint("same text again"))
+
+	tests := []struct {
+		name   string
+		plugin core.DependentPlugin
+		text   string
+	}{
+		{"profanity", NewProfanityFilterPlugin().WithMask('*'), "vulgar words"},
+		{"profanity reject", NewProfanityFilterPluginWithConfig(rejectTier), "a slur"},
+		{"spam", NewSpamDetectorPlugin(), "BUY NOW http://a.example http://b.example!!!!!"},
+		{"sentiment", NewSentimentAnalyzerPlugin(), "great but terrible, you idiot"},
+		{"scoring", NewScoringPlugin(), "text"},
+		{"decision router", NewDecisionRouterPlugin(), "text"},
+		{"action handler", NewActionHandlerPlugin(), "text"},
+		{"url analyzer", NewURLAnalyzerPlugin([]string{"bad.example"}, nil), "see http://bad.example/x"},
+		{"rate limit", rateLimit, "second"},
+		{"fingerprint", fingerprint, "same text again"},
+		{"allowlist", NewAllowlistPlugin([]string{"alice"}), "text"},
+		{"min length", NewMinLengthPlugin(10, "reject"), "hi"},
+		{"reputation", NewReputationPlugin(NewMemoryReputationStore()), "text"},
+		{"topic", NewTopicClassifierPlugin(), "the election and the senate vote"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := core.NewContext(&Content{ID: "1", Text: tt.text, AuthorID: "alice"})
+			ctx.Set("spam_score", 0.1)
+			ctx.AddScore(ScoreCategorySpam, 0.1)
+			ctx.Set("moderation_score", ModerationScore{OverallScore: 0.1})
+			ctx.Set("moderation_decision", ModerationDecision{Action: "approve"})
+			before := make(map[string]any, len(ctx.Metadata))
+			for key, value := range ctx.Metadata {
+				before[key] = value
+			}
+
+			if err := tt.plugin.Execute(ctx); err != nil && !errors.Is(err, core.ErrSkipRemaining) {
+				t.Fatalf("Execute: %v", err)
+			}
+
+			provided := make(map[string]bool)
+			for _, key := range tt.plugin.Provides() {
+				provided[key] = true
+			}
+			for key, value := range ctx.Metadata {
+				old, existed := before[key]
+				changed := !existed || !reflect.DeepEqual(old, value)
+				if changed && !provided[key] {
+					t.Errorf("sets %q, which Provides does not list", key)
+				}
+			}
+		})
+	}
+}
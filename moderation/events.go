@@ -0,0 +1,84 @@
+package moderation
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultSubscriberBuffer is the number of results a DecisionBroker subscriber can fall
+// behind by before results are dropped for it
+const DefaultSubscriberBuffer = 64
+
+// Publisher receives each completed moderation result from ActionHandlerPlugin.
+// Publish is called on the pipeline's goroutine, so it must not block.
+type Publisher interface {
+	Publish(result ModerationResult)
+}
+
+// DecisionBroker fans out published moderation results to any number of subscribers,
+// such as live dashboard connections. Each subscriber has a buffered channel; when a
+// slow subscriber's buffer is full its results are dropped rather than blocking the
+// pipeline. It is safe for concurrent use.
+type DecisionBroker struct {
+	mu          sync.RWMutex
+	subscribers map[chan ModerationResult]struct{}
+	dropped     atomic.Int64
+}
+
+// NewDecisionBroker creates a broker without subscribers
+func NewDecisionBroker() *DecisionBroker {
+	return &DecisionBroker{
+		subscribers: make(map[chan ModerationResult]struct{}),
+	}
+}
+
+// Subscribe registers a subscriber with room for buffer pending results (zero or less
+// uses DefaultSubscriberBuffer). It returns the channel of results and a function that
+// unsubscribes and closes the channel; call it when the subscriber goes away.
+func (b *DecisionBroker) Subscribe(buffer int) (<-chan ModerationResult, func()) {
+	if buffer <= 0 {
+		buffer = DefaultSubscriberBuffer
+	}
+
+	ch := make(chan ModerationResult, buffer)
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+	return ch, unsubscribe
+}
+
+// Publish sends result to every subscriber without blocking
+func (b *DecisionBroker) Publish(result ModerationResult) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- result:
+		default:
+			b.dropped.Add(1)
+		}
+	}
+}
+
+// Subscribers returns the number of current subscribers
+func (b *DecisionBroker) Subscribers() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscribers)
+}
+
+// Dropped returns the number of results dropped for slow subscribers
+func (b *DecisionBroker) Dropped() int64 {
+	return b.dropped.Load()
+}
@@ -0,0 +1,57 @@
+package moderation
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestDecisionBroker(t *testing.T) {
+	broker := NewDecisionBroker()
+	fast, unsubscribeFast := broker.Subscribe(2)
+	slow, unsubscribeSlow := broker.Subscribe(1)
+	if broker.Subscribers() != 2 {
+		t.Fatalf("Subscribers = %d, want 2", broker.Subscribers())
+	}
+
+	broker.Publish(ModerationResult{Content: Content{ID: "a"}})
+	broker.Publish(ModerationResult{Content: Content{ID: "b"}})
+
+	if first, second := <-fast, <-fast; first.Content.ID != "a" || second.Content.ID != "b" {
+		t.Errorf("fast subscriber got %q, %q, want a, b", first.Content.ID, second.Content.ID)
+	}
+	if got := <-slow; got.Content.ID != "a" {
+		t.Errorf("slow subscriber got %q, want a", got.Content.ID)
+	}
+	if broker.Dropped() != 1 {
+		t.Errorf("Dropped = %d, want 1 for the full slow subscriber", broker.Dropped())
+	}
+
+	unsubscribeSlow()
+	unsubscribeSlow()
+	if _, open := <-slow; open {
+		t.Error("channel still open after unsubscribing")
+	}
+	unsubscribeFast()
+	if broker.Subscribers() != 0 {
+		t.Errorf("Subscribers = %d, want 0", broker.Subscribers())
+	}
+	broker.Publish(ModerationResult{})
+}
+
+func TestDecisionBrokerConcurrent(t *testing.T) {
+	broker := NewDecisionBroker()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, unsubscribe := broker.Subscribe(0)
+			defer unsubscribe()
+		}()
+		go func() {
+			defer wg.Done()
+			broker.Publish(ModerationResult{})
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,161 @@
+package moderation
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"sync"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// shingleSize is the number of runes in each shingle hashed into a fingerprint
+const shingleSize = 4
+
+// FingerprintPlugin computes a SimHash fingerprint of content and flags content that is
+// a near duplicate of something seen recently. Texts that differ only in case and
+// whitespace get the same fingerprint, and small edits change only a few bits.
+type FingerprintPlugin struct {
+	threshold  int
+	recentSize int
+
+	mu     sync.Mutex
+	recent []uint64
+	next   int
+}
+
+// NewFingerprintPlugin creates a fingerprint plugin that remembers the last recentSize
+// fingerprints and treats content within threshold differing bits as a near duplicate.
+// Non-positive values default to a threshold of 6 bits and 1000 recent fingerprints.
+func NewFingerprintPlugin(threshold, recentSize int) *FingerprintPlugin {
+	if threshold <= 0 {
+		threshold = 6
+	}
+	if recentSize <= 0 {
+		recentSize = 1000
+	}
+	return &FingerprintPlugin{
+		threshold:  threshold,
+		recentSize: recentSize,
+		recent:     make([]uint64, 0, recentSize),
+	}
+}
+
+// Execute stores the content fingerprint under "fingerprint", compares it against recent
+// fingerprints, and stores "near_duplicate" and, on a match, "duplicate_distance".
+func (p *FingerprintPlugin) Execute(ctx *core.Context) error {
+	content, ok := ctx.GetData().(*Content)
+	if !ok {
+		return fmt.Errorf("expected *Content, got %T", ctx.GetData())
+	}
+
+	fingerprint := Fingerprint(content.Text)
+	ctx.Set("fingerprint", fingerprint)
+
+	distance, found := p.MatchAndRemember(fingerprint)
+	ctx.Set("near_duplicate", found)
+	if found {
+		ctx.Set("duplicate_distance", distance)
+	}
+
+	return nil
+}
+
+// Match compares fingerprint against the recent fingerprints and returns the smallest
+// Hamming distance, and whether it is within the threshold.
+func (p *FingerprintPlugin) Match(fingerprint uint64) (int, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.match(fingerprint)
+}
+
+// Remember adds fingerprint to the recent set, replacing the oldest once it is full.
+func (p *FingerprintPlugin) Remember(fingerprint uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.remember(fingerprint)
+}
+
+// MatchAndRemember matches fingerprint like Match and then remembers it, as one step.
+// Of two near duplicates submitted concurrently, exactly one is reported as a duplicate
+// of the other.
+func (p *FingerprintPlugin) MatchAndRemember(fingerprint uint64) (int, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	distance, found := p.match(fingerprint)
+	p.remember(fingerprint)
+	return distance, found
+}
+
+// match implements Match. The caller must hold the lock.
+func (p *FingerprintPlugin) match(fingerprint uint64) (int, bool) {
+	best := -1
+	for _, seen := range p.recent {
+		distance := HammingDistance(fingerprint, seen)
+		if best < 0 || distance < best {
+			best = distance
+		}
+	}
+	if best < 0 || best > p.threshold {
+		return best, false
+	}
+	return best, true
+}
+
+// remember implements Remember. The caller must hold the lock.
+func (p *FingerprintPlugin) remember(fingerprint uint64) {
+	if len(p.recent) < p.recentSize {
+		p.recent = append(p.recent, fingerprint)
+		return
+	}
+	p.recent[p.next] = fingerprint
+	p.next = (p.next + 1) % p.recentSize
+}
+
+// Fingerprint returns the 64-bit SimHash of text over overlapping character shingles.
+// Text is lowercased and its whitespace collapsed before hashing.
+func Fingerprint(text string) uint64 {
+	runes := []rune(strings.ToLower(strings.Join(strings.Fields(text), " ")))
+	if len(runes) == 0 {
+		return 0
+	}
+
+	var weights [64]int
+	add := func(shingle []rune) {
+		h := fnv.New64a()
+		h.Write([]byte(string(shingle)))
+		sum := h.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if sum&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	if len(runes) < shingleSize {
+		add(runes)
+	} else {
+		for i := 0; i+shingleSize <= len(runes); i++ {
+			add(runes[i : i+shingleSize])
+		}
+	}
+
+	var fingerprint uint64
+	for bit, weight := range weights {
+		if weight > 0 {
+			fingerprint |= 1 << bit
+		}
+	}
+	return fingerprint
+}
+
+// HammingDistance returns the number of bits that differ between two fingerprints
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
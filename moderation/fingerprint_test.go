@@ -0,0 +1,77 @@
+package moderation
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+func TestFingerprintNormalizes(t *testing.T) {
+	if Fingerprint("Buy cheap  watches NOW") != Fingerprint("buy cheap watches now") {
+		t.Error("case and whitespace changed the fingerprint")
+	}
+	a := Fingerprint("Buy cheap watches now at our online store")
+	b := Fingerprint("Buy cheap watches now at our online shop")
+	c := Fingerprint("The weather was lovely at the beach today")
+	if HammingDistance(a, b) >= HammingDistance(a, c) {
+		t.Errorf("distance to a small edit (%d) is not below distance to unrelated text (%d)",
+			HammingDistance(a, b), HammingDistance(a, c))
+	}
+}
+
+func TestFingerprintPluginNearDuplicate(t *testing.T) {
+	plugin := NewFingerprintPlugin(6, 10)
+
+	first := core.NewContext(&Content{Text: "Buy cheap watches now at our online store"})
+	plugin.Execute(first)
+	if duplicate, _ := core.Value[bool](first, "near_duplicate"); duplicate {
+		t.Error("first content flagged as a near duplicate")
+	}
+
+	second := core.NewContext(&Content{Text: "buy cheap watches now at our online store!"})
+	plugin.Execute(second)
+	if duplicate, _ := core.Value[bool](second, "near_duplicate"); !duplicate {
+		t.Error("near-identical content not flagged")
+	}
+	if _, ok := core.Value[int](second, "duplicate_distance"); !ok {
+		t.Error("duplicate_distance not set for a near duplicate")
+	}
+}
+
+func TestFingerprintPluginForgetsOldest(t *testing.T) {
+	plugin := NewFingerprintPlugin(0, 2)
+	plugin.Remember(Fingerprint("first message"))
+	plugin.Remember(Fingerprint("second message here"))
+	plugin.Remember(Fingerprint("a third, unrelated message"))
+
+	if _, found := plugin.Match(Fingerprint("first message")); found {
+		t.Error("oldest fingerprint still matched after being replaced")
+	}
+	if _, found := plugin.Match(Fingerprint("second message here")); !found {
+		t.Error("recent fingerprint not matched")
+	}
+}
+
+func TestFingerprintPluginConcurrentDuplicates(t *testing.T) {
+	for round := 0; round < 50; round++ {
+		plugin := NewFingerprintPlugin(6, 10)
+
+		var wg sync.WaitGroup
+		duplicates := make([]bool, 2)
+		for i := range duplicates {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				ctx := core.NewContext(&Content{Text: "Same spam text"})
+				plugin.Execute(ctx)
+				duplicates[i], _ = core.Value[bool](ctx, "near_duplicate")
+			}(i)
+		}
+		wg.Wait()
+
+		if duplicates[0] == duplicates[1] {
+			t.Fatalf("round %d: near_duplicate = %v, want exactly one of two identical submissions flagged", round, duplicates)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package moderation
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// LoadSentimentLexicon parses a sentiment lexicon with one "word<TAB>score" entry per line,
+// such as AFINN. Words are lowercased; blank lines and lines starting with # are skipped.
+func LoadSentimentLexicon(r io.Reader) (map[string]float64, error) {
+	lexicon := make(map[string]float64)
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		word, value, found := strings.Cut(line, "\t")
+		if !found {
+			return nil, fmt.Errorf("lexicon line %d: expected word<TAB>score", lineNumber)
+		}
+
+		word = strings.ToLower(strings.TrimSpace(word))
+		score, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if word == "" || err != nil {
+			return nil, fmt.Errorf("lexicon line %d: invalid entry %q", lineNumber, line)
+		}
+		lexicon[word] = score
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read lexicon: %w", err)
+	}
+	return lexicon, nil
+}
@@ -0,0 +1,60 @@
+package moderation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadSentimentLexicon(t *testing.T) {
+	input := "# AFINN-style lexicon\n" +
+		"superb\t5\n" +
+		"\n" +
+		"Good\t1\n" +
+		"dreadful\t-4\n"
+
+	lexicon, err := LoadSentimentLexicon(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("LoadSentimentLexicon: %v", err)
+	}
+	want := map[string]float64{"superb": 5, "good": 1, "dreadful": -4}
+	if len(lexicon) != len(want) {
+		t.Fatalf("lexicon = %v, want %v", lexicon, want)
+	}
+	for word, score := range want {
+		if lexicon[word] != score {
+			t.Errorf("lexicon[%q] = %v, want %v", word, lexicon[word], score)
+		}
+	}
+}
+
+func TestLoadSentimentLexiconErrors(t *testing.T) {
+	for _, input := range []string{"good 1\n", "good\tvery\n", "\t3\n"} {
+		if _, err := LoadSentimentLexicon(strings.NewReader(input)); err == nil {
+			t.Errorf("LoadSentimentLexicon(%q) succeeded, want an error", input)
+		}
+	}
+}
+
+func TestSentimentAnalyzerWeightedLexicon(t *testing.T) {
+	lexicon, err := LoadSentimentLexicon(strings.NewReader("superb\t5\ngood\t1\ndreadful\t-4\n"))
+	if err != nil {
+		t.Fatalf("LoadSentimentLexicon: %v", err)
+	}
+	plugin := NewSentimentAnalyzerPluginWithLexicon(lexicon)
+
+	// Pad with neutral words so the scores stay below the clamp at 1.0
+	filler := strings.Repeat(" the", 99)
+	strong := plugin.Analyze("superb" + filler)
+	weak := plugin.Analyze("good" + filler)
+	if strong.Sentiment <= weak.Sentiment {
+		t.Errorf("sentiment of superb (%v) not above good (%v)", strong.Sentiment, weak.Sentiment)
+	}
+
+	negative := plugin.Analyze("dreadful" + filler)
+	if negative.Sentiment >= 0 {
+		t.Errorf("sentiment of dreadful = %v, want negative", negative.Sentiment)
+	}
+	if len(negative.NegativeWords) != 1 || negative.NegativeWords[0] != "dreadful" {
+		t.Errorf("NegativeWords = %v, want [dreadful]", negative.NegativeWords)
+	}
+}
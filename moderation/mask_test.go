@@ -0,0 +1,60 @@
+package moderation
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+func TestProfanityFilterMask(t *testing.T) {
+	plugin := NewProfanityFilterPlugin().WithMask('#')
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"that is vulgar", "that is v#####"},
+		{"VULGAR and Offensive!", "V##### and O########!"},
+		// Only whole words are masked
+		{"explicitly fine", "explicitly fine"},
+		{"clean text", "clean text"},
+		// Multi-byte text keeps its characters and offsets
+		{"café vulgar ünd", "café v##### ünd"},
+		// Invalid UTF-8 is kept as is
+		{"\xffbad vulgar \xfe", "\xffbad v##### \xfe"},
+		{"\xff", "\xff"},
+	}
+	for _, tt := range tests {
+		if got := plugin.Mask(tt.text); got != tt.want {
+			t.Errorf("Mask(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestProfanityFilterMaskPatterns(t *testing.T) {
+	plugin := NewProfanityFilterPluginWithConfig(ProfanityConfig{Tiers: []ProfanityTier{
+		{Weight: 0.5, Words: []string{"darn"}, Patterns: []*regexp.Regexp{EvasionPattern("darn")}},
+	}}).WithMask('*')
+
+	if got, want := plugin.Mask("oh d.a.r.n it"), "oh d****** it"; got != want {
+		t.Errorf("Mask = %q, want %q", got, want)
+	}
+}
+
+func TestProfanityFilterMaskedText(t *testing.T) {
+	ctx := core.NewContext(&Content{ID: "1", Text: "so vulgar"})
+	if err := NewProfanityFilterPlugin().WithMask('*').Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if got, _ := core.Value[string](ctx, "masked_text"); got != "so v*****" {
+		t.Errorf("masked_text = %q, want %q", got, "so v*****")
+	}
+
+	ctx = core.NewContext(&Content{ID: "1", Text: "so vulgar"})
+	if err := NewProfanityFilterPlugin().Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if _, ok := ctx.Get("masked_text"); ok {
+		t.Error("masked_text set without a mask")
+	}
+}
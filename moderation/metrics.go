@@ -0,0 +1,135 @@
+package moderation
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the pipeline latency histogram
+var DefaultLatencyBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}
+
+// Metrics counts moderation decisions and records pipeline latency.
+// It serves the values in the Prometheus text exposition format, so it can be
+// mounted at /metrics and scraped without a client library.
+type Metrics struct {
+	buckets []float64
+
+	mu           sync.Mutex
+	decisions    map[string]uint64
+	errors       uint64
+	bucketCounts []uint64
+	latencySum   float64
+	latencyCount uint64
+}
+
+// NewMetrics creates a metrics collector with DefaultLatencyBuckets
+func NewMetrics() *Metrics {
+	return &Metrics{
+		buckets:      DefaultLatencyBuckets,
+		decisions:    make(map[string]uint64),
+		bucketCounts: make([]uint64, len(DefaultLatencyBuckets)),
+	}
+}
+
+// ObserveDecision records a completed moderation with its action and pipeline duration
+func (m *Metrics) ObserveDecision(action string, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.decisions[action]++
+	m.observeLatency(duration)
+}
+
+// ObserveError records a failed moderation and its pipeline duration
+func (m *Metrics) ObserveError(duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.errors++
+	m.observeLatency(duration)
+}
+
+// DecisionCount returns the number of decisions recorded for action
+func (m *Metrics) DecisionCount(action string) uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.decisions[action]
+}
+
+// observeLatency adds a duration to the histogram; the caller must hold m.mu
+func (m *Metrics) observeLatency(duration time.Duration) {
+	seconds := duration.Seconds()
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			m.bucketCounts[i]++
+		}
+	}
+	m.latencySum += seconds
+	m.latencyCount++
+}
+
+// WriteTo writes all metrics in the Prometheus text exposition format
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cw := &countingWriter{w: w}
+
+	fmt.Fprintln(cw, "# HELP moderation_decisions_total Moderation decisions by action.")
+	fmt.Fprintln(cw, "# TYPE moderation_decisions_total counter")
+	// The standard actions are always exported, followed by any custom ones
+	extra := make([]string, 0)
+	for action := range m.decisions {
+		if action != "approve" && action != "review" && action != "reject" {
+			extra = append(extra, action)
+		}
+	}
+	sort.Strings(extra)
+	for _, action := range append([]string{"approve", "review", "reject"}, extra...) {
+		fmt.Fprintf(cw, "moderation_decisions_total{action=%q} %d\n", action, m.decisions[action])
+	}
+
+	fmt.Fprintln(cw, "# HELP moderation_errors_total Moderation requests that failed in the pipeline.")
+	fmt.Fprintln(cw, "# TYPE moderation_errors_total counter")
+	fmt.Fprintf(cw, "moderation_errors_total %d\n", m.errors)
+
+	fmt.Fprintln(cw, "# HELP moderation_pipeline_duration_seconds Moderation pipeline latency.")
+	fmt.Fprintln(cw, "# TYPE moderation_pipeline_duration_seconds histogram")
+	for i, bound := range m.buckets {
+		le := strconv.FormatFloat(bound, 'g', -1, 64)
+		fmt.Fprintf(cw, "moderation_pipeline_duration_seconds_bucket{le=%q} %d\n", le, m.bucketCounts[i])
+	}
+	fmt.Fprintf(cw, "moderation_pipeline_duration_seconds_bucket{le=\"+Inf\"} %d\n", m.latencyCount)
+	fmt.Fprintf(cw, "moderation_pipeline_duration_seconds_sum %s\n", strconv.FormatFloat(m.latencySum, 'g', -1, 64))
+	fmt.Fprintf(cw, "moderation_pipeline_duration_seconds_count %d\n", m.latencyCount)
+
+	return cw.n, cw.err
+}
+
+// ServeHTTP serves the metrics for a Prometheus scrape
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// countingWriter tracks bytes written and the first write error
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
@@ -0,0 +1,68 @@
+package moderation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scrape serves metrics for a scrape and returns the response body.
+func scrape(t *testing.T, metrics *Metrics) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Errorf("Content-Type = %q, want text/plain", rec.Header().Get("Content-Type"))
+	}
+	return rec.Body.String()
+}
+
+func TestMetricsDecisionCounters(t *testing.T) {
+	metrics := NewMetrics()
+	metrics.ObserveDecision("approve", time.Millisecond)
+	metrics.ObserveDecision("approve", time.Millisecond)
+	metrics.ObserveDecision("reject", time.Millisecond)
+	metrics.ObserveDecision("escalate", time.Millisecond)
+	metrics.ObserveError(time.Millisecond)
+
+	if got := metrics.DecisionCount("approve"); got != 2 {
+		t.Errorf("DecisionCount(approve) = %d, want 2", got)
+	}
+
+	body := scrape(t, metrics)
+	for _, want := range []string{
+		`moderation_decisions_total{action="approve"} 2`,
+		`moderation_decisions_total{action="review"} 0`,
+		`moderation_decisions_total{action="reject"} 1`,
+		`moderation_decisions_total{action="escalate"} 1`,
+		`moderation_errors_total 1`,
+	} {
+		if !strings.Contains(body, want+"\n") {
+			t.Errorf("scrape is missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestMetricsLatencyHistogram(t *testing.T) {
+	metrics := NewMetrics()
+	metrics.ObserveDecision("approve", 2*time.Millisecond)
+	metrics.ObserveDecision("approve", 200*time.Millisecond)
+	metrics.ObserveDecision("approve", 2*time.Second)
+
+	body := scrape(t, metrics)
+	for _, want := range []string{
+		`moderation_pipeline_duration_seconds_bucket{le="0.001"} 0`,
+		`moderation_pipeline_duration_seconds_bucket{le="0.0025"} 1`,
+		`moderation_pipeline_duration_seconds_bucket{le="0.25"} 2`,
+		`moderation_pipeline_duration_seconds_bucket{le="1"} 2`,
+		`moderation_pipeline_duration_seconds_bucket{le="+Inf"} 3`,
+		`moderation_pipeline_duration_seconds_sum 2.202`,
+		`moderation_pipeline_duration_seconds_count 3`,
+	} {
+		if !strings.Contains(body, want+"\n") {
+			t.Errorf("scrape is missing %q:\n%s", want, body)
+		}
+	}
+}
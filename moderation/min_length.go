@@ -0,0 +1,66 @@
+package moderation
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// MinLengthReason is the decision reason recorded for content that is too short
+const MinLengthReason = "Content is too short to moderate"
+
+// MinLengthPlugin guards against content too short to score meaningfully, such as a
+// single character or whitespace. Content shorter than the minimum is routed to the
+// configured action immediately and the rest of the pipeline is skipped with
+// core.ErrSkipRemaining; other content passes through. Place it first.
+type MinLengthPlugin struct {
+	minLength int
+	action    string
+}
+
+// NewMinLengthPlugin creates a guard for content shorter than minLength runes after
+// trimming and collapsing whitespace. Short content is routed to action, which must be
+// "review" or "reject"; any other value uses "review".
+func NewMinLengthPlugin(minLength int, action string) *MinLengthPlugin {
+	if action != "reject" {
+		action = "review"
+	}
+	return &MinLengthPlugin{
+		minLength: minLength,
+		action:    action,
+	}
+}
+
+// Execute sets "too_short" and, for short content, replaces the data with a
+// ModerationResult for the configured action, as ActionHandlerPlugin would, and stops the pipeline
+func (p *MinLengthPlugin) Execute(ctx *core.Context) error {
+	content, ok := ctx.GetData().(*Content)
+	if !ok {
+		return fmt.Errorf("expected *Content, got %T", ctx.GetData())
+	}
+
+	normalized := strings.Join(strings.Fields(content.Text), " ")
+	tooShort := utf8.RuneCountInString(normalized) < p.minLength
+	ctx.Set("too_short", tooShort)
+	if !tooShort {
+		return nil
+	}
+
+	decideEarly(ctx, content, ModerationDecision{
+		Action:  p.action,
+		Reason:  MinLengthReason,
+		Flagged: true,
+	})
+	return core.ErrSkipRemaining
+}
+
+// Requires returns the metadata keys MinLengthPlugin reads
+func (p *MinLengthPlugin) Requires() []string { return nil }
+
+// Provides returns the metadata keys MinLengthPlugin sets, including the decision and
+// action keys recorded for short content
+func (p *MinLengthPlugin) Provides() []string {
+	return []string{"too_short", "moderation_decision", "action_executed", "action_executed_at"}
+}
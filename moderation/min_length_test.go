@@ -0,0 +1,42 @@
+package moderation
+
+import (
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+func TestMinLength(t *testing.T) {
+	tests := []struct {
+		text   string
+		action string
+		want   string
+	}{
+		{"  a   b ", "reject", "reject"},
+		{"k", "", "review"},
+		{"k", "approve", "review"},
+		{"ok then", "reject", ""},
+		{"héllo", "reject", ""},
+	}
+
+	for _, test := range tests {
+		ctx := core.NewContext(&Content{Text: test.text})
+		err := NewMinLengthPlugin(5, test.action).Execute(ctx)
+		tooShort, _ := core.Value[bool](ctx, "too_short")
+
+		if test.want == "" {
+			if err != nil || tooShort {
+				t.Errorf("%q: err = %v, too_short = %v, want the content to pass", test.text, err, tooShort)
+			}
+			continue
+		}
+		if err != core.ErrSkipRemaining || !tooShort {
+			t.Errorf("%q: err = %v, too_short = %v, want ErrSkipRemaining", test.text, err, tooShort)
+			continue
+		}
+		result, ok := ctx.GetData().(*ModerationResult)
+		if !ok || result.Decision.Action != test.want || result.Decision.Reason != MinLengthReason {
+			t.Errorf("%q: data = %+v, want a %s decision", test.text, ctx.GetData(), test.want)
+		}
+	}
+}
@@ -11,10 +11,26 @@ const (
 
 // Content represents user-generated content to be moderated
 type Content struct {
-	ID        string    `json:"id"`
-	Text      string    `json:"text"`
-	AuthorID  string    `json:"author_id"`
-	Timestamp time.Time `json:"timestamp"`
+	ID          string       `json:"id"`
+	Text        string       `json:"text"`
+	AuthorID    string       `json:"author_id"`
+	Timestamp   time.Time    `json:"timestamp"`
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+// Attachment is a file or image submitted with content. The built-in plugins moderate
+// only the text; attachments are carried for plugins that inspect them.
+type Attachment struct {
+	Type     string `json:"type"`      // image, file, video, etc.
+	URL      string `json:"url"`       // where the attachment can be fetched
+	MimeType string `json:"mime_type"` // e.g. image/png
+}
+
+// ExtractedURL is a URL found in content by URLAnalyzerPlugin
+type ExtractedURL struct {
+	URL    string `json:"url"`
+	Host   string `json:"host"`
+	Status string `json:"status"` // blocked, allowed, unknown
 }
 
 // ModerationScore contains scores from various moderation checks
@@ -33,8 +49,19 @@ type ModerationDecision struct {
 	Flagged bool            `json:"flagged"`
 }
 
+// Explanation lists what triggered the individual moderation scores
+type Explanation struct {
+	ProfanityMatches []string `json:"profanity_matches"` // Profane words found in the content
+	SpamSignals      []string `json:"spam_signals"`      // Spam checks that fired: links, repeats, caps
+	PositiveWords    []string `json:"positive_words"`    // Words that raised the sentiment score
+	NegativeWords    []string `json:"negative_words"`    // Words that lowered the sentiment score
+}
+
 // ModerationResult is the final result of the moderation pipeline
 type ModerationResult struct {
-	Content  Content            `json:"content"`
-	Decision ModerationDecision `json:"decision"`
+	Content     Content            `json:"content"`
+	Decision    ModerationDecision `json:"decision"`
+	Explanation Explanation        `json:"explanation"`
+	RequestID   string             `json:"request_id,omitempty"` // ID of the HTTP request that submitted the content
+	DecidedAt   time.Time          `json:"decided_at"`           // when the decision was made
 }
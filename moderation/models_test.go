@@ -0,0 +1,27 @@
+package moderation
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestContentAttachmentsJSON(t *testing.T) {
+	var content Content
+	body := `{"text": "look", "attachments": [{"type": "image", "url": "https://example.com/a.png", "mime_type": "image/png"}]}`
+	if err := json.Unmarshal([]byte(body), &content); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	want := Attachment{Type: "image", URL: "https://example.com/a.png", MimeType: "image/png"}
+	if len(content.Attachments) != 1 || content.Attachments[0] != want {
+		t.Errorf("attachments = %+v, want [%+v]", content.Attachments, want)
+	}
+
+	encoded, err := json.Marshal(Content{Text: "plain"})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if strings.Contains(string(encoded), "attachments") {
+		t.Errorf("encoded = %s, want attachments omitted when empty", encoded)
+	}
+}
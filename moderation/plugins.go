@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/dvictor357/pipeline-plugin-system/core"
 )
@@ -11,19 +14,236 @@ import (
 // ProfanityFilterPlugin detects inappropriate language in content
 type ProfanityFilterPlugin struct {
 	profanityWords []string
+	weights        []float64 // score added by each word, by index in profanityWords
+	rejects        []bool    // whether each word rejects outright, by index in profanityWords
+	wordIndex      map[string]int
+	matcher        *acMatcher
+	patterns       []profanityPattern
+	wordPattern    *regexp.Regexp // matches listed words as whole words, for masking
+	mask           rune           // masks profanity in "masked_text" when set
 }
 
-// NewProfanityFilterPlugin creates a new profanity filter with a default word list
+// profanityPattern is a regex pattern and the score it adds when it matches
+type profanityPattern struct {
+	pattern *regexp.Regexp
+	weight  float64
+	reject  bool
+}
+
+// SevereProfanityReason is the decision reason recorded when a Reject tier matches
+const SevereProfanityReason = "Content contains severe profanity"
+
+// Profanity tier names used by DefaultProfanityConfig
+const (
+	ProfanityTierMild     = "mild"
+	ProfanityTierModerate = "moderate"
+	ProfanityTierSevere   = "severe"
+)
+
+// ProfanityTier is a list of words and patterns that each add Weight to the profanity
+// score when found
+type ProfanityTier struct {
+	Name  string
+	Words []string
+	// Patterns catch evasions of the plain words, such as "f u c k" or "fuuuck" (see
+	// EvasionPattern). They are matched against the lowercased text. Go's regexp package
+	// runs in linear time, so patterns can't backtrack catastrophically.
+	Patterns []*regexp.Regexp
+	Weight   float64
+	// Reject stops the pipeline with a reject decision as soon as a word or pattern of the
+	// tier is found, skipping the remaining checks (see Terminate). Add ActionHandlerPlugin
+	// with UseFinally so the result is still finalized.
+	Reject bool
+}
+
+// ProfanityConfig defines the tiered word lists of the profanity filter
+type ProfanityConfig struct {
+	// Tiers lists the word lists and their weights. A word listed in several tiers
+	// counts once, with the highest weight. Nil uses the tiers of DefaultProfanityConfig.
+	Tiers []ProfanityTier
+}
+
+// DefaultProfanityConfig returns the default word list split into mild, moderate and
+// severe tiers. A single severe word scores more than several mild ones.
+func DefaultProfanityConfig() ProfanityConfig {
+	return ProfanityConfig{
+		Tiers: []ProfanityTier{
+			{Name: ProfanityTierMild, Words: []string{"inappropriate", "explicit", "profanity"}, Weight: 0.1},
+			{Name: ProfanityTierModerate, Words: []string{"offensive", "vulgar", "obscene"}, Weight: 0.3},
+			{Name: ProfanityTierSevere, Words: []string{"badword1", "badword2"}, Weight: 0.7},
+		},
+	}
+}
+
+// NewProfanityFilterPlugin creates a new profanity filter with the default tiered word list
 func NewProfanityFilterPlugin() *ProfanityFilterPlugin {
+	return NewProfanityFilterPluginWithConfig(DefaultProfanityConfig())
+}
+
+// NewProfanityFilterPluginWithConfig creates a new profanity filter with the given tiered word lists
+func NewProfanityFilterPluginWithConfig(config ProfanityConfig) *ProfanityFilterPlugin {
+	tiers := config.Tiers
+	if tiers == nil {
+		tiers = DefaultProfanityConfig().Tiers
+	}
+
+	profanityWords := make([]string, 0)
+	weights := make([]float64, 0)
+	rejects := make([]bool, 0)
+	index := make(map[string]int)
+	for _, tier := range tiers {
+		for _, word := range tier.Words {
+			if i, exists := index[strings.ToLower(word)]; exists {
+				weights[i] = max(weights[i], tier.Weight)
+				rejects[i] = rejects[i] || tier.Reject
+				continue
+			}
+			index[strings.ToLower(word)] = len(profanityWords)
+			profanityWords = append(profanityWords, word)
+			weights = append(weights, tier.Weight)
+			rejects = append(rejects, tier.Reject)
+		}
+	}
+
+	patterns := make([]profanityPattern, 0)
+	for _, tier := range tiers {
+		for _, pattern := range tier.Patterns {
+			patterns = append(patterns, profanityPattern{pattern: pattern, weight: tier.Weight, reject: tier.Reject})
+		}
+	}
+
+	// Match all words in one pass over the text, however long the list grows
+	lowered := make([]string, len(profanityWords))
+	for i, word := range profanityWords {
+		lowered[i] = strings.ToLower(word)
+	}
+
 	return &ProfanityFilterPlugin{
-		profanityWords: []string{
-			"badword1", "badword2", "offensive", "inappropriate",
-			"profanity", "vulgar", "obscene", "explicit",
-		},
+		profanityWords: profanityWords,
+		weights:        weights,
+		rejects:        rejects,
+		wordIndex:      index,
+		matcher:        newACMatcher(lowered),
+		patterns:       patterns,
+		wordPattern:    wholeWordPattern(lowered),
+	}
+}
+
+// WithMask makes the filter also store the text with profanity masked under
+// "masked_text", replacing every character of a match but the first with mask, so
+// "vulgar" becomes "v*****" and the text keeps its length. Only whole words are masked,
+// so "explicitly" stays readable even though it scores as "explicit"; evasions caught by
+// tier patterns are masked too. Scoring is unchanged.
+func (p *ProfanityFilterPlugin) WithMask(mask rune) *ProfanityFilterPlugin {
+	p.mask = mask
+	return p
+}
+
+// wholeWordPattern returns a pattern matching any of words as a whole word, or nil for no
+// words
+func wholeWordPattern(words []string) *regexp.Regexp {
+	quoted := make([]string, 0, len(words))
+	for _, word := range words {
+		if word != "" {
+			quoted = append(quoted, regexp.QuoteMeta(word))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`\b(?:` + strings.Join(quoted, "|") + `)\b`)
+}
+
+// Mask returns text with each whole listed word and each pattern match masked
+func (p *ProfanityFilterPlugin) Mask(text string) string {
+	mask := p.mask
+	if mask == 0 {
+		mask = '*'
+	}
+
+	lowered := lowerInPlace(text)
+
+	var spans [][]int
+	if p.wordPattern != nil {
+		spans = append(spans, p.wordPattern.FindAllStringIndex(lowered, -1)...)
+	}
+	for _, entry := range p.patterns {
+		spans = append(spans, entry.pattern.FindAllStringIndex(lowered, -1)...)
+	}
+	if len(spans) == 0 {
+		return text
 	}
+
+	// Mark the bytes to mask, keeping the first character of each match
+	hidden := make([]bool, len(text))
+	for _, span := range spans {
+		_, size := utf8.DecodeRuneInString(text[span[0]:span[1]])
+		for i := span[0] + size; i < span[1]; i++ {
+			hidden[i] = true
+		}
+	}
+
+	var masked strings.Builder
+	masked.Grow(len(text))
+	for i := 0; i < len(text); {
+		_, size := utf8.DecodeRuneInString(text[i:])
+		if hidden[i] {
+			masked.WriteRune(mask)
+		} else {
+			masked.WriteString(text[i : i+size])
+		}
+		i += size
+	}
+	return masked.String()
+}
+
+// lowerInPlace returns text lowercased wherever that keeps byte offsets, so matches in the
+// result index text. Invalid UTF-8 is copied unchanged rather than replaced.
+func lowerInPlace(text string) string {
+	lowered := make([]byte, 0, len(text))
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if lower := unicode.ToLower(r); r != utf8.RuneError && utf8.RuneLen(lower) == size {
+			lowered = utf8.AppendRune(lowered, lower)
+		} else {
+			lowered = append(lowered, text[i:i+size]...)
+		}
+		i += size
+	}
+	return string(lowered)
+}
+
+// countedWord reports whether any of the pattern matches is a listed word found in the
+// text
+func (p *ProfanityFilterPlugin) countedWord(patternMatches []string, found []bool) bool {
+	for _, match := range patternMatches {
+		if i, listed := p.wordIndex[match]; listed && found[i] {
+			return true
+		}
+	}
+	return false
 }
 
-// Execute checks content for profanity and calculates a score
+// EvasionPattern returns a pattern matching word with any letter repeated and with spaces
+// or punctuation between letters, such as "f u c k", "f.u.c.k" and "fuuuck"
+func EvasionPattern(word string) *regexp.Regexp {
+	var pattern strings.Builder
+	pattern.WriteString(`\b`)
+	for i, r := range strings.ToLower(word) {
+		if i > 0 {
+			pattern.WriteString(`[\s\p{P}]*`)
+		}
+		pattern.WriteString(regexp.QuoteMeta(string(r)))
+		pattern.WriteString("+")
+	}
+	pattern.WriteString(`\b`)
+	return regexp.MustCompile(pattern.String())
+}
+
+// Execute checks content for profanity and calculates a score.
+// The matched words are stored under "profanity_matches", and the masked text under
+// "masked_text" when a mask is set. A match in a Reject tier terminates the pipeline with
+// a reject decision.
 func (p *ProfanityFilterPlugin) Execute(ctx *core.Context) error {
 	content, ok := ctx.GetData().(*Content)
 	if !ok {
@@ -31,25 +251,65 @@ func (p *ProfanityFilterPlugin) Execute(ctx *core.Context) error {
 	}
 
 	text := strings.ToLower(content.Text)
-	matchCount := 0
+	matches := make([]string, 0)
 
-	for _, word := range p.profanityWords {
-		if strings.Contains(text, strings.ToLower(word)) {
-			matchCount++
+	// Calculate score: 0.0 (clean) to 1.0 (highly profane)
+	// Each listed word counts once, in list order, wherever it appears in the text,
+	// adding its tier's weight
+	score := 0.0
+	reject := false
+	found := p.matcher.matches(text)
+	for i, word := range p.profanityWords {
+		if found[i] {
+			matches = append(matches, word)
+			score += p.weights[i]
+			reject = reject || p.rejects[i]
 		}
 	}
 
-	// Calculate score: 0.0 (clean) to 1.0 (highly profane)
-	// Each match adds 0.2, capped at 1.0
-	score := float64(matchCount) * 0.2
+	// Each pattern counts once, unless any of its matches is a listed word already counted,
+	// so "d.a.r.n and darn" doesn't count the same word twice
+	for _, entry := range p.patterns {
+		all := entry.pattern.FindAllString(text, -1)
+		if len(all) == 0 || p.countedWord(all, found) {
+			continue
+		}
+		matches = append(matches, all[0])
+		score += entry.weight
+		reject = reject || entry.reject
+	}
+
+	// Cap at 1.0
 	if score > 1.0 {
 		score = 1.0
 	}
 
 	ctx.Set("profanity_score", score)
+	ctx.Set("profanity_matches", matches)
+	ctx.AddScore(ScoreCategoryProfanity, score)
+	if p.mask != 0 {
+		ctx.Set("masked_text", p.Mask(content.Text))
+	}
+
+	if reject {
+		return Terminate(ctx, ModerationDecision{
+			Action:  "reject",
+			Score:   ModerationScore{ProfanityScore: score, OverallScore: score},
+			Reason:  SevereProfanityReason,
+			Flagged: true,
+		})
+	}
 	return nil
 }
 
+// Spam signal names recorded under "spam_signals"
+const (
+	SpamSignalLinks     = "links"      // Multiple links
+	SpamSignalRepeats   = "repeats"    // A character repeated five or more times
+	SpamSignalCaps      = "caps"       // Mostly uppercase letters
+	SpamSignalRateLimit = "rate_limit" // Author exceeded the rate limit (RateLimitPlugin)
+)
+
 // SpamDetectorPlugin identifies spam patterns in content
 type SpamDetectorPlugin struct {
 	linkPattern *regexp.Regexp
@@ -62,7 +322,8 @@ func NewSpamDetectorPlugin() *SpamDetectorPlugin {
 	}
 }
 
-// Execute checks content for spam patterns and calculates a score
+// Execute checks content for spam patterns and calculates a score.
+// The names of the checks that fired are stored under "spam_signals".
 func (p *SpamDetectorPlugin) Execute(ctx *core.Context) error {
 	content, ok := ctx.GetData().(*Content)
 	if !ok {
@@ -70,13 +331,16 @@ func (p *SpamDetectorPlugin) Execute(ctx *core.Context) error {
 	}
 
 	score := 0.0
+	signals := make([]string, 0)
 
 	// Check for excessive links
 	links := p.linkPattern.FindAllString(content.Text, -1)
 	if len(links) > 3 {
 		score += 0.5
+		signals = append(signals, SpamSignalLinks)
 	} else if len(links) > 1 {
 		score += 0.2
+		signals = append(signals, SpamSignalLinks)
 	}
 
 	// Check for repeated characters (e.g., "hellooooo")
@@ -92,19 +356,29 @@ func (p *SpamDetectorPlugin) Execute(ctx *core.Context) error {
 	}
 	if hasRepeated {
 		score += 0.3
+		signals = append(signals, SpamSignalRepeats)
 	}
 
 	// Check for excessive capitalization
+	// Only the letters of words are counted so punctuation, digits and emoji don't dilute the ratio
 	upperCount := 0
-	for _, r := range content.Text {
-		if r >= 'A' && r <= 'Z' {
-			upperCount++
+	letterCount := 0
+	for _, word := range tokenize(content.Text) {
+		for _, r := range word {
+			if !unicode.IsLetter(r) {
+				continue
+			}
+			letterCount++
+			if unicode.IsUpper(r) {
+				upperCount++
+			}
 		}
 	}
-	if len(content.Text) > 0 {
-		upperRatio := float64(upperCount) / float64(len(content.Text))
+	if letterCount > 0 {
+		upperRatio := float64(upperCount) / float64(letterCount)
 		if upperRatio > 0.5 {
 			score += 0.3
+			signals = append(signals, SpamSignalCaps)
 		}
 	}
 
@@ -114,137 +388,205 @@ func (p *SpamDetectorPlugin) Execute(ctx *core.Context) error {
 	}
 
 	ctx.Set("spam_score", score)
+	ctx.Set("spam_signals", signals)
+	ctx.AddScore(ScoreCategorySpam, score)
 	return nil
 }
 
-// SentimentAnalyzerPlugin performs lexicon-based sentiment analysis
+// SentimentAnalyzerPlugin performs lexicon-based sentiment analysis and scores toxicity
+// from a separate lexicon of insulting and aggressive terms
 type SentimentAnalyzerPlugin struct {
-	positiveWords []string
-	negativeWords []string
+	lexicon  map[string]float64
+	toxicity map[string]float64
 }
 
-// NewSentimentAnalyzerPlugin creates a new sentiment analyzer
+// DefaultToxicityLexicon returns the built-in toxicity lexicon, mapping insulting and
+// aggressive words to the weight each adds to the toxicity score
+func DefaultToxicityLexicon() map[string]float64 {
+	return map[string]float64{
+		"idiot": 0.5, "idiots": 0.5, "stupid": 0.4, "moron": 0.5, "morons": 0.5,
+		"dumb": 0.3, "loser": 0.4, "losers": 0.4, "pathetic": 0.4, "worthless": 0.5,
+		"useless": 0.3, "scum": 0.6, "trash": 0.3, "garbage": 0.3, "disgusting": 0.3,
+		"kill": 0.7, "die": 0.5,
+	}
+}
+
+// NewSentimentAnalyzerPlugin creates a new sentiment analyzer with a default word list
+// where each positive word scores +1 and each negative word scores -1
 func NewSentimentAnalyzerPlugin() *SentimentAnalyzerPlugin {
+	positiveWords := []string{
+		"good", "great", "excellent", "amazing", "wonderful",
+		"love", "happy", "fantastic", "awesome", "perfect",
+	}
+	negativeWords := []string{
+		"bad", "terrible", "awful", "horrible", "hate",
+		"angry", "sad", "disgusting", "worst", "pathetic",
+	}
+
+	lexicon := make(map[string]float64, len(positiveWords)+len(negativeWords))
+	for _, word := range positiveWords {
+		lexicon[word] = 1
+	}
+	for _, word := range negativeWords {
+		lexicon[word] = -1
+	}
 	return &SentimentAnalyzerPlugin{
-		positiveWords: []string{
-			"good", "great", "excellent", "amazing", "wonderful",
-			"love", "happy", "fantastic", "awesome", "perfect",
-		},
-		negativeWords: []string{
-			"bad", "terrible", "awful", "horrible", "hate",
-			"angry", "sad", "disgusting", "worst", "pathetic",
-		},
+		lexicon:  lexicon,
+		toxicity: DefaultToxicityLexicon(),
+	}
+}
+
+// NewSentimentAnalyzerPluginWithLexicon creates a sentiment analyzer that scores words
+// by their valence in lexicon, such as one loaded by LoadSentimentLexicon
+func NewSentimentAnalyzerPluginWithLexicon(lexicon map[string]float64) *SentimentAnalyzerPlugin {
+	copied := make(map[string]float64, len(lexicon))
+	for word, valence := range lexicon {
+		copied[strings.ToLower(word)] = valence
+	}
+	return &SentimentAnalyzerPlugin{
+		lexicon:  copied,
+		toxicity: DefaultToxicityLexicon(),
 	}
 }
 
-// Execute analyzes sentiment and stores the score
+// WithToxicityLexicon replaces the toxicity lexicon with one mapping words to the weight
+// each adds to the toxicity score, and returns the plugin for method chaining
+func (p *SentimentAnalyzerPlugin) WithToxicityLexicon(lexicon map[string]float64) *SentimentAnalyzerPlugin {
+	p.toxicity = make(map[string]float64, len(lexicon))
+	for word, weight := range lexicon {
+		p.toxicity[strings.ToLower(word)] = weight
+	}
+	return p
+}
+
+// SentimentAnalysis is the result of SentimentAnalyzerPlugin.Analyze
+type SentimentAnalysis struct {
+	Sentiment     float64  // -1.0 (very negative) to 1.0 (very positive)
+	Toxicity      float64  // 0.0 to 1.0
+	PositiveWords []string // words that raised the sentiment
+	NegativeWords []string // words that lowered the sentiment
+	ToxicWords    []string // words that raised the toxicity
+}
+
+// Execute analyzes sentiment and toxicity and stores both scores.
+// The contributing words are stored under "positive_words", "negative_words" and "toxic_words".
 func (p *SentimentAnalyzerPlugin) Execute(ctx *core.Context) error {
 	content, ok := ctx.GetData().(*Content)
 	if !ok {
 		return fmt.Errorf("expected *Content, got %T", ctx.GetData())
 	}
 
-	text := strings.ToLower(content.Text)
-	words := strings.Fields(text)
+	analysis := p.Analyze(content.Text)
+	ctx.Set("sentiment_score", analysis.Sentiment)
+	ctx.Set("toxicity_score", analysis.Toxicity)
+	ctx.Set("positive_words", analysis.PositiveWords)
+	ctx.Set("negative_words", analysis.NegativeWords)
+	ctx.Set("toxic_words", analysis.ToxicWords)
+	ctx.AddScore(ScoreCategoryToxicity, analysis.Toxicity)
+	return nil
+}
 
-	positiveCount := 0
-	negativeCount := 0
+// Analyze scores the sentiment and toxicity of text
+func (p *SentimentAnalyzerPlugin) Analyze(text string) SentimentAnalysis {
+	words := tokenize(strings.ToLower(text))
 
-	for _, word := range words {
-		// Remove punctuation for matching
-		cleanWord := strings.Trim(word, ".,!?;:")
+	analysis := SentimentAnalysis{
+		PositiveWords: make([]string, 0),
+		NegativeWords: make([]string, 0),
+		ToxicWords:    make([]string, 0),
+	}
+	totalSentiment := 0.0
 
-		for _, posWord := range p.positiveWords {
-			if cleanWord == posWord {
-				positiveCount++
-				break
-			}
+	for _, word := range words {
+		if weight, ok := p.toxicity[word]; ok {
+			analysis.Toxicity += weight
+			analysis.ToxicWords = append(analysis.ToxicWords, word)
 		}
 
-		for _, negWord := range p.negativeWords {
-			if cleanWord == negWord {
-				negativeCount++
-				break
-			}
+		valence, ok := p.lexicon[word]
+		if !ok {
+			continue
+		}
+		totalSentiment += valence
+		if valence > 0 {
+			analysis.PositiveWords = append(analysis.PositiveWords, word)
+		} else if valence < 0 {
+			analysis.NegativeWords = append(analysis.NegativeWords, word)
 		}
 	}
 
 	// Calculate sentiment: -1.0 (very negative) to 1.0 (very positive)
-	totalSentiment := positiveCount - negativeCount
-	var sentimentScore float64
 	if len(words) > 0 {
-		sentimentScore = float64(totalSentiment) / float64(len(words)) * 10
-		if sentimentScore > 1.0 {
-			sentimentScore = 1.0
-		} else if sentimentScore < -1.0 {
-			sentimentScore = -1.0
+		analysis.Sentiment = totalSentiment / float64(len(words)) * 10
+		if analysis.Sentiment > 1.0 {
+			analysis.Sentiment = 1.0
+		} else if analysis.Sentiment < -1.0 {
+			analysis.Sentiment = -1.0
 		}
 	}
 
-	// Convert extreme negativity to toxicity score (0.0 to 1.0)
-	toxicityScore := 0.0
-	if sentimentScore < -0.3 {
-		toxicityScore = -sentimentScore // More negative = higher toxicity
-		if toxicityScore > 1.0 {
-			toxicityScore = 1.0
-		}
+	// Toxicity is scored independently of sentiment, so sad text isn't toxic (0.0 to 1.0)
+	if analysis.Toxicity > 1.0 {
+		analysis.Toxicity = 1.0
 	}
 
-	ctx.Set("sentiment_score", sentimentScore)
-	ctx.Set("toxicity_score", toxicityScore)
-	return nil
+	return analysis
 }
 
-// ScoringPlugin aggregates scores from previous plugins
+// Score categories added to the Context with AddScore by the built-in plugins
+const (
+	ScoreCategoryProfanity = "profanity"
+	ScoreCategorySpam      = "spam"
+	ScoreCategoryToxicity  = "toxicity"
+)
+
+// DefaultScoreWeights returns the weight of each score category in the overall score
+func DefaultScoreWeights() map[string]float64 {
+	return map[string]float64{
+		ScoreCategoryProfanity: 0.4,
+		ScoreCategorySpam:      0.3,
+		ScoreCategoryToxicity:  0.3,
+	}
+}
+
+// ScoringPlugin aggregates the scores that previous plugins added to the Context with
+// AddScore into a weighted overall score. Each category is capped at 1.0 before weighting;
+// categories without a weight don't count toward the overall score.
 type ScoringPlugin struct {
-	profanityWeight float64
-	spamWeight      float64
-	toxicityWeight  float64
+	weights map[string]float64
 }
 
 // NewScoringPlugin creates a new scoring plugin with default weights
 func NewScoringPlugin() *ScoringPlugin {
+	return NewScoringPluginWithWeights(DefaultScoreWeights())
+}
+
+// NewScoringPluginWithWeights creates a scoring plugin that weighs each score category,
+// including custom ones, by the given weights
+func NewScoringPluginWithWeights(weights map[string]float64) *ScoringPlugin {
+	copied := make(map[string]float64, len(weights))
+	for category, weight := range weights {
+		copied[category] = weight
+	}
 	return &ScoringPlugin{
-		profanityWeight: 0.4,
-		spamWeight:      0.3,
-		toxicityWeight:  0.3,
+		weights: copied,
 	}
 }
 
 // Execute calculates the weighted overall moderation score
 func (p *ScoringPlugin) Execute(ctx *core.Context) error {
-	// Retrieve individual scores
-	profanityScore := 0.0
-	if val, ok := ctx.Get("profanity_score"); ok {
-		if score, ok := val.(float64); ok {
-			profanityScore = score
-		}
+	scores := ctx.Scores()
+	overallScore := 0.0
+	for category, score := range scores {
+		scores[category] = max(0.0, min(score, 1.0))
+		overallScore += scores[category] * p.weights[category]
 	}
 
-	spamScore := 0.0
-	if val, ok := ctx.Get("spam_score"); ok {
-		if score, ok := val.(float64); ok {
-			spamScore = score
-		}
-	}
-
-	toxicityScore := 0.0
-	if val, ok := ctx.Get("toxicity_score"); ok {
-		if score, ok := val.(float64); ok {
-			toxicityScore = score
-		}
-	}
-
-	// Calculate weighted overall score
-	overallScore := (profanityScore * p.profanityWeight) +
-		(spamScore * p.spamWeight) +
-		(toxicityScore * p.toxicityWeight)
-
 	// Create ModerationScore struct
 	moderationScore := ModerationScore{
-		ProfanityScore: profanityScore,
-		SpamScore:      spamScore,
-		ToxicityScore:  toxicityScore,
+		ProfanityScore: scores[ScoreCategoryProfanity],
+		SpamScore:      scores[ScoreCategorySpam],
+		ToxicityScore:  scores[ScoreCategoryToxicity],
 		OverallScore:   overallScore,
 	}
 
@@ -256,13 +598,64 @@ func (p *ScoringPlugin) Execute(ctx *core.Context) error {
 type DecisionRouterPlugin struct {
 	approveThreshold float64
 	reviewThreshold  float64
+	reasons          map[string]string
+	leniency         float64
+	topicThresholds  map[string]Thresholds
+}
+
+// Thresholds are the score limits of a moderation decision: scores below Approve are
+// approved, scores below Review are sent to review, and higher scores are rejected
+type Thresholds struct {
+	Approve float64
+	Review  float64
+}
+
+// DecisionRouterConfig defines optional behavior for the decision router
+type DecisionRouterConfig struct {
+	// Reasons maps an action (approve, review, reject) to its reason template.
+	// The placeholders {action} and {score} are replaced with the action and the
+	// overall score formatted to two decimals. Missing actions use DefaultDecisionReasons.
+	Reasons map[string]string
+
+	// ReputationLeniency shifts both thresholds by up to this amount based on the
+	// "author_reputation" set by ReputationPlugin: a fully trusted author (1.0) gets
+	// thresholds raised by the full amount, an untrusted author (0.0) gets them lowered
+	// by it, and a neutral author is unaffected. Zero disables the adjustment.
+	ReputationLeniency float64
+
+	// TopicThresholds replaces the default thresholds for content whose "topic", set by
+	// TopicClassifierPlugin, is listed, such as stricter ones for politics. Other topics
+	// use ApproveThreshold and ReviewThreshold. Reputation leniency applies on top.
+	TopicThresholds map[string]Thresholds
+}
+
+// DefaultDecisionReasons returns the reason recorded for each action when no template is configured
+func DefaultDecisionReasons() map[string]string {
+	return map[string]string{
+		"approve": "Content meets quality standards",
+		"review":  "Content requires manual review",
+		"reject":  "Content violates community guidelines",
+	}
 }
 
 // NewDecisionRouterPlugin creates a new decision router with default thresholds
 func NewDecisionRouterPlugin() *DecisionRouterPlugin {
+	return NewDecisionRouterPluginWithConfig(DecisionRouterConfig{})
+}
+
+// NewDecisionRouterPluginWithConfig creates a new decision router with default thresholds
+// and the given configuration
+func NewDecisionRouterPluginWithConfig(config DecisionRouterConfig) *DecisionRouterPlugin {
+	reasons := DefaultDecisionReasons()
+	for action, template := range config.Reasons {
+		reasons[action] = template
+	}
 	return &DecisionRouterPlugin{
 		approveThreshold: ApproveThreshold,
 		reviewThreshold:  ReviewThreshold,
+		reasons:          reasons,
+		leniency:         config.ReputationLeniency,
+		topicThresholds:  config.TopicThresholds,
 	}
 }
 
@@ -279,25 +672,47 @@ func (p *DecisionRouterPlugin) Execute(ctx *core.Context) error {
 		return fmt.Errorf("expected ModerationScore, got %T", scoreVal)
 	}
 
+	// Some topics need stricter or looser thresholds than the rest
+	approveThreshold, reviewThreshold := p.approveThreshold, p.reviewThreshold
+	if val, ok := ctx.Get("topic"); ok {
+		if topic, ok := val.(string); ok {
+			if thresholds, ok := p.topicThresholds[topic]; ok {
+				approveThreshold, reviewThreshold = thresholds.Approve, thresholds.Review
+			}
+		}
+	}
+
+	// Trusted authors get more leniency, untrusted ones less
+	if p.leniency != 0 {
+		if val, ok := ctx.Get("author_reputation"); ok {
+			if reputation, ok := val.(float64); ok {
+				shift := (reputation - NeutralReputation) * 2 * p.leniency
+				approveThreshold = max(0.0, min(approveThreshold+shift, 1.0))
+				reviewThreshold = max(0.0, min(reviewThreshold+shift, 1.0))
+			}
+		}
+	}
+
 	// Determine action based on thresholds
 	var action string
-	var reason string
 	var flagged bool
 
-	if moderationScore.OverallScore < p.approveThreshold {
+	if moderationScore.OverallScore < approveThreshold {
 		action = "approve"
-		reason = "Content meets quality standards"
 		flagged = false
-	} else if moderationScore.OverallScore < p.reviewThreshold {
+	} else if moderationScore.OverallScore < reviewThreshold {
 		action = "review"
-		reason = "Content requires manual review"
 		flagged = true
 	} else {
 		action = "reject"
-		reason = "Content violates community guidelines"
 		flagged = true
 	}
 
+	reason := strings.NewReplacer(
+		"{action}", action,
+		"{score}", fmt.Sprintf("%.2f", moderationScore.OverallScore),
+	).Replace(p.reasons[action])
+
 	// Create decision
 	decision := ModerationDecision{
 		Action:  action,
@@ -311,15 +726,50 @@ func (p *DecisionRouterPlugin) Execute(ctx *core.Context) error {
 }
 
 // ActionHandlerPlugin executes the moderation decision
-type ActionHandlerPlugin struct{}
+type ActionHandlerPlugin struct {
+	publisher Publisher
+	audit     AuditSink
+}
+
+// ActionHandlerConfig defines optional behavior for the action handler
+type ActionHandlerConfig struct {
+	// Publisher receives every executed result, e.g. a DecisionBroker feeding a live
+	// event stream. Nil disables publishing.
+	Publisher Publisher
+	// Audit records every executed result, including decisions made early by plugins such
+	// as AllowlistPlugin when the handler is added with UseFinally. Nil disables auditing.
+	Audit AuditSink
+}
 
 // NewActionHandlerPlugin creates a new action handler
 func NewActionHandlerPlugin() *ActionHandlerPlugin {
-	return &ActionHandlerPlugin{}
+	return NewActionHandlerPluginWithConfig(ActionHandlerConfig{})
 }
 
-// Execute logs the decision and updates the final result
+// NewActionHandlerPluginWithConfig creates a new action handler with the given configuration
+func NewActionHandlerPluginWithConfig(config ActionHandlerConfig) *ActionHandlerPlugin {
+	return &ActionHandlerPlugin{
+		publisher: config.Publisher,
+		audit:     config.Audit,
+	}
+}
+
+// Execute executes the decision and updates the final result.
+// In dry-run mode the result is still populated but the action is not executed, published
+// or audited.
 func (p *ActionHandlerPlugin) Execute(ctx *core.Context) error {
+	// A plugin that decided early, such as AllowlistPlugin, already finalized the result
+	if result, done := ctx.GetData().(*ModerationResult); done {
+		if ctx.IsDryRun() {
+			return nil
+		}
+		if err := p.record(*result); err != nil {
+			return err
+		}
+		p.publish(*result)
+		return nil
+	}
+
 	// Retrieve content
 	content, ok := ctx.GetData().(*Content)
 	if !ok {
@@ -341,10 +791,68 @@ func (p *ActionHandlerPlugin) Execute(ctx *core.Context) error {
 	result := ModerationResult{
 		Content:  *content,
 		Decision: decision,
+		Explanation: Explanation{
+			ProfanityMatches: stringsFromContext(ctx, "profanity_matches"),
+			SpamSignals:      stringsFromContext(ctx, "spam_signals"),
+			PositiveWords:    stringsFromContext(ctx, "positive_words"),
+			NegativeWords:    stringsFromContext(ctx, "negative_words"),
+		},
+		RequestID: requestIDFromContext(ctx),
+		DecidedAt: time.Now(),
 	}
 
 	// Update context with final result
 	ctx.SetData(&result)
 
+	// Skip side effects in dry-run mode
+	if ctx.IsDryRun() {
+		return nil
+	}
+
+	// Record the decision before executing it, so no executed decision goes unaudited
+	if err := p.record(result); err != nil {
+		return err
+	}
+
+	ctx.Set("action_executed", true)
+	ctx.Set("action_executed_at", result.DecidedAt)
+
+	p.publish(result)
+
 	return nil
 }
+
+// record passes the result to the audit sink, if any
+func (p *ActionHandlerPlugin) record(result ModerationResult) error {
+	if p.audit == nil {
+		return nil
+	}
+	if err := p.audit.Record(result); err != nil {
+		return fmt.Errorf("failed to audit decision for content %q: %w", result.Content.ID, err)
+	}
+	return nil
+}
+
+// publish passes the result to the publisher, if any
+func (p *ActionHandlerPlugin) publish(result ModerationResult) {
+	if p.publisher != nil {
+		p.publisher.Publish(result)
+	}
+}
+
+// requestIDFromContext returns the request ID that http.HTTPHandler stores under
+// "request_id", or "" outside of HTTP requests
+func requestIDFromContext(ctx *core.Context) string {
+	id, _ := core.Value[string](ctx, "request_id")
+	return id
+}
+
+// stringsFromContext returns the []string stored under key, or an empty slice if absent
+func stringsFromContext(ctx *core.Context, key string) []string {
+	if val, ok := ctx.Get(key); ok {
+		if values, ok := val.([]string); ok {
+			return values
+		}
+	}
+	return []string{}
+}
@@ -0,0 +1,323 @@
+package moderation
+
+import (
+	"math"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// moderationPipeline returns the standard moderation pipeline.
+func moderationPipeline() *core.Pipeline {
+	return core.NewPipeline(core.AbortOnError).
+		Use(NewProfanityFilterPlugin()).
+		Use(NewSpamDetectorPlugin()).
+		Use(NewSentimentAnalyzerPlugin()).
+		Use(NewScoringPlugin()).
+		Use(NewDecisionRouterPlugin()).
+		Use(NewActionHandlerPlugin())
+}
+
+// moderate runs text through pipeline and returns the context and final result.
+func moderate(t *testing.T, pipeline *core.Pipeline, text string) (*core.Context, *ModerationResult) {
+	t.Helper()
+	ctx := core.NewContext(&Content{ID: "1", AuthorID: "alice", Text: text})
+	if err := pipeline.Execute(ctx); err != nil {
+		t.Fatalf("Execute(%q): %v", text, err)
+	}
+	result, ok := ctx.GetData().(*ModerationResult)
+	if !ok {
+		t.Fatalf("data = %T, want *ModerationResult", ctx.GetData())
+	}
+	return ctx, result
+}
+
+func TestActionHandlerDryRun(t *testing.T) {
+	ctx := core.NewContext(&Content{ID: "1", Text: "badword1 offensive vulgar obscene explicit"})
+	if err := moderationPipeline().DryRun(ctx); err != nil {
+		t.Fatalf("DryRun: %v", err)
+	}
+
+	result, ok := ctx.GetData().(*ModerationResult)
+	if !ok || result.Decision.Action == "" {
+		t.Fatalf("data = %#v, want a decided *ModerationResult", ctx.GetData())
+	}
+	if _, executed := ctx.Get("action_executed"); executed {
+		t.Error("action_executed set during a dry run")
+	}
+
+	ctx, _ = moderate(t, moderationPipeline(), "hello there")
+	if executed, _ := core.Value[bool](ctx, "action_executed"); !executed {
+		t.Error("action_executed not set outside a dry run")
+	}
+}
+
+func TestSpamDetectorCapsRatio(t *testing.T) {
+	tests := []struct {
+		text string
+		caps bool
+	}{
+		{"NAÏVE ÉLÈVE", true},    // multibyte uppercase letters count once each
+		{"ABC!!! ??? ...", true}, // punctuation doesn't dilute the ratio
+		{"OK 12345 67890", true}, // nor do digits
+		{"Ça va, très bien", false},
+		{"hello WORLD there", false},
+	}
+	for _, test := range tests {
+		ctx := core.NewContext(&Content{Text: test.text})
+		if err := NewSpamDetectorPlugin().Execute(ctx); err != nil {
+			t.Fatalf("Execute(%q): %v", test.text, err)
+		}
+		signals, _ := core.Value[[]string](ctx, "spam_signals")
+		caps := false
+		for _, signal := range signals {
+			caps = caps || signal == SpamSignalCaps
+		}
+		if caps != test.caps {
+			t.Errorf("%q: caps signal = %v, want %v", test.text, caps, test.caps)
+		}
+	}
+}
+
+func TestModerationResultExplanation(t *testing.T) {
+	_, result := moderate(t, moderationPipeline(), "This explicit post is great but the ending was terrible, sooooo long")
+
+	explanation := result.Explanation
+	if !containsString(explanation.ProfanityMatches, "explicit") {
+		t.Errorf("ProfanityMatches = %v, want explicit", explanation.ProfanityMatches)
+	}
+	if !containsString(explanation.SpamSignals, SpamSignalRepeats) {
+		t.Errorf("SpamSignals = %v, want %s", explanation.SpamSignals, SpamSignalRepeats)
+	}
+	if !containsString(explanation.PositiveWords, "great") {
+		t.Errorf("PositiveWords = %v, want great", explanation.PositiveWords)
+	}
+	if !containsString(explanation.NegativeWords, "terrible") {
+		t.Errorf("NegativeWords = %v, want terrible", explanation.NegativeWords)
+	}
+}
+
+func TestModerationResultExplanationClean(t *testing.T) {
+	_, result := moderate(t, moderationPipeline(), "See you at the meeting tomorrow")
+
+	explanation := result.Explanation
+	if len(explanation.ProfanityMatches)+len(explanation.SpamSignals)+
+		len(explanation.PositiveWords)+len(explanation.NegativeWords) != 0 {
+		t.Errorf("Explanation = %+v, want it empty for clean content", explanation)
+	}
+}
+
+// containsString reports whether values contains want.
+func containsString(values []string, want string) bool {
+	for _, value := range values {
+		if value == want {
+			return true
+		}
+	}
+	return false
+}
+
+// decide runs plugin on a context with the given overall score and metadata and returns the decision.
+func decide(t *testing.T, plugin *DecisionRouterPlugin, score float64, metadata map[string]any) ModerationDecision {
+	t.Helper()
+	ctx := core.NewContext(&Content{ID: "1"})
+	ctx.Set("moderation_score", ModerationScore{OverallScore: score})
+	for key, value := range metadata {
+		ctx.Set(key, value)
+	}
+	if err := plugin.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	decision, _ := core.Value[ModerationDecision](ctx, "moderation_decision")
+	return decision
+}
+
+func TestDecisionRouterDefaultReasons(t *testing.T) {
+	plugin := NewDecisionRouterPlugin()
+	reasons := DefaultDecisionReasons()
+
+	for score, action := range map[float64]string{0.0: "approve", 0.5: "review", 0.9: "reject"} {
+		decision := decide(t, plugin, score, nil)
+		if decision.Action != action || decision.Reason != reasons[action] {
+			t.Errorf("score %v: decision = %s %q, want %s %q", score, decision.Action, decision.Reason, action, reasons[action])
+		}
+	}
+}
+
+func TestDecisionRouterReasonTemplates(t *testing.T) {
+	plugin := NewDecisionRouterPluginWithConfig(DecisionRouterConfig{
+		Reasons: map[string]string{
+			"reject": "Contenu refusé ({action}, score {score})",
+		},
+	})
+
+	if decision := decide(t, plugin, 0.87654, nil); decision.Reason != "Contenu refusé (reject, score 0.88)" {
+		t.Errorf("reject reason = %q, want the interpolated template", decision.Reason)
+	}
+	if decision := decide(t, plugin, 0.0, nil); decision.Reason != DefaultDecisionReasons()["approve"] {
+		t.Errorf("approve reason = %q, want the default for an action without a template", decision.Reason)
+	}
+}
+
+// profanity runs plugin on text and returns the profanity score and matches.
+func profanity(t *testing.T, plugin *ProfanityFilterPlugin, text string) (float64, []string) {
+	t.Helper()
+	ctx := core.NewContext(&Content{Text: text})
+	if err := plugin.Execute(ctx); err != nil {
+		t.Fatalf("Execute(%q): %v", text, err)
+	}
+	score, _ := core.Value[float64](ctx, "profanity_score")
+	matches, _ := core.Value[[]string](ctx, "profanity_matches")
+	return score, matches
+}
+
+func TestProfanityFilterTiers(t *testing.T) {
+	plugin := NewProfanityFilterPlugin()
+
+	mild, _ := profanity(t, plugin, "explicit and inappropriate profanity")
+	severe, matches := profanity(t, plugin, "what a badword1")
+	if severe <= mild {
+		t.Errorf("one severe word scored %v, want more than three mild words (%v)", severe, mild)
+	}
+	if len(matches) != 1 || matches[0] != "badword1" {
+		t.Errorf("matches = %v, want [badword1]", matches)
+	}
+	if capped, _ := profanity(t, plugin, "badword1 badword2"); capped != 1.0 {
+		t.Errorf("score = %v, want it capped at 1.0", capped)
+	}
+}
+
+func TestProfanityFilterDuplicateWordKeepsHighestWeight(t *testing.T) {
+	plugin := NewProfanityFilterPluginWithConfig(ProfanityConfig{
+		Tiers: []ProfanityTier{
+			{Name: ProfanityTierMild, Words: []string{"darn"}, Weight: 0.1},
+			{Name: ProfanityTierSevere, Words: []string{"Darn"}, Weight: 0.6},
+		},
+	})
+
+	score, matches := profanity(t, plugin, "darn it")
+	if score != 0.6 || len(matches) != 1 {
+		t.Errorf("score = %v, matches = %v, want one match weighted 0.6", score, matches)
+	}
+}
+
+func TestProfanityFilterEvasionCountsOnce(t *testing.T) {
+	plugin := NewProfanityFilterPluginWithConfig(ProfanityConfig{Tiers: []ProfanityTier{
+		{Words: []string{"darn"}, Patterns: []*regexp.Regexp{EvasionPattern("darn")}, Weight: 0.3},
+	}})
+
+	tests := []struct {
+		text    string
+		matches []string
+	}{
+		{"darn", []string{"darn"}},
+		{"d.a.r.n", []string{"d.a.r.n"}},
+		{"d.a.r.n and darn", []string{"darn"}},
+		{"darn, d a r n, daaarn", []string{"darn"}},
+	}
+	for _, tt := range tests {
+		score, matches := profanity(t, plugin, tt.text)
+		if score != 0.3 || !reflect.DeepEqual(matches, tt.matches) {
+			t.Errorf("%q: score %v, matches %v, want 0.3, %v", tt.text, score, matches, tt.matches)
+		}
+	}
+}
+
+func TestEvasionPattern(t *testing.T) {
+	pattern := EvasionPattern("darn")
+	for _, text := range []string{"darn", "d a r n", "d.a.r.n", "daaarn", "d-a-r-n"} {
+		if !pattern.MatchString(text) {
+			t.Errorf("pattern does not match %q", text)
+		}
+	}
+	for _, text := range []string{"darning", "undarn", "dam"} {
+		if pattern.MatchString(text) {
+			t.Errorf("pattern matches %q", text)
+		}
+	}
+}
+
+func TestProfanityFilterPatterns(t *testing.T) {
+	plugin := NewProfanityFilterPluginWithConfig(ProfanityConfig{
+		Tiers: []ProfanityTier{
+			{Name: ProfanityTierModerate, Words: []string{"darn"}, Patterns: []*regexp.Regexp{EvasionPattern("darn")}, Weight: 0.3},
+		},
+	})
+
+	score, matches := profanity(t, plugin, "D A R N it")
+	if score != 0.3 || len(matches) != 1 || matches[0] != "d a r n" {
+		t.Errorf("score = %v, matches = %v, want the spaced spelling matched once", score, matches)
+	}
+
+	// The plain word is counted by the word list, not again by its pattern
+	score, matches = profanity(t, plugin, "darn it")
+	if score != 0.3 || len(matches) != 1 {
+		t.Errorf("score = %v, matches = %v, want one match", score, matches)
+	}
+}
+
+func TestSentimentAnalyzerToxicity(t *testing.T) {
+	tests := []struct {
+		text     string
+		toxicity float64
+	}{
+		{"I am so sad and disappointed today", 0},
+		{"you are a stupid idiot", 0.9},
+		{"kill them all, die scum", 1.0},
+	}
+
+	for _, test := range tests {
+		ctx := core.NewContext(&Content{Text: test.text})
+		if err := NewSentimentAnalyzerPlugin().Execute(ctx); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		if got, _ := core.Value[float64](ctx, "toxicity_score"); math.Abs(got-test.toxicity) > 1e-9 {
+			t.Errorf("toxicity of %q = %v, want %v", test.text, got, test.toxicity)
+		}
+	}
+}
+
+func TestSentimentAnalyzerCustomToxicityLexicon(t *testing.T) {
+	plugin := NewSentimentAnalyzerPlugin().WithToxicityLexicon(map[string]float64{"Clown": 0.6})
+	ctx := core.NewContext(&Content{Text: "what a clown, you idiot"})
+	if err := plugin.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	score, _ := core.Value[float64](ctx, "toxicity_score")
+	words, _ := core.Value[[]string](ctx, "toxic_words")
+	if score != 0.6 || len(words) != 1 || words[0] != "clown" {
+		t.Errorf("toxicity = %v, toxic_words = %v, want only the custom word", score, words)
+	}
+}
+
+func TestProfanityFilterSevereHitTerminates(t *testing.T) {
+	config := DefaultProfanityConfig()
+	config.Tiers[2].Reject = true
+	pipeline := core.NewPipeline(core.AbortOnError).
+		Use(NewProfanityFilterPluginWithConfig(config)).
+		Use(NewSpamDetectorPlugin()).
+		Use(NewSentimentAnalyzerPlugin()).
+		Use(NewScoringPlugin()).
+		Use(NewDecisionRouterPlugin()).
+		UseFinally(NewActionHandlerPlugin())
+
+	ctx, result := moderate(t, pipeline, "you badword1")
+	if result.Decision.Action != "reject" || result.Decision.Reason != SevereProfanityReason {
+		t.Errorf("decision = %+v, want a severe profanity rejection", result.Decision)
+	}
+	if _, ran := ctx.Get("spam_score"); ran {
+		t.Error("spam detector ran after a severe hit")
+	}
+	if executed, _ := core.Value[bool](ctx, "action_executed"); !executed {
+		t.Error("action handler did not finalize the early decision")
+	}
+
+	// Words in the other tiers only add to the score
+	_, result = moderate(t, pipeline, "how vulgar")
+	if result.Decision.Reason == SevereProfanityReason {
+		t.Errorf("decision = %+v, want the usual routing for a moderate word", result.Decision)
+	}
+}
@@ -0,0 +1,120 @@
+package moderation
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// rateLimitSpamPenalty is added to the spam score when an author exceeds the rate limit
+const rateLimitSpamPenalty = 0.5
+
+// RateLimitPlugin flags content from authors who post more than a maximum number of
+// items within a sliding time window. It should run after SpamDetectorPlugin, since
+// it raises the "spam_score" that plugin sets.
+type RateLimitPlugin struct {
+	maxItems int
+	window   time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	posts     map[string][]time.Time
+	lastSweep time.Time
+}
+
+// NewRateLimitPlugin creates a rate limiter allowing maxItems per author within window.
+// Non-positive values default to 10 items per minute, so a zero limit never flags
+// every post.
+func NewRateLimitPlugin(maxItems int, window time.Duration) *RateLimitPlugin {
+	if maxItems <= 0 {
+		maxItems = 10
+	}
+	if window <= 0 {
+		window = time.Minute
+	}
+	return &RateLimitPlugin{
+		maxItems: maxItems,
+		window:   window,
+		now:      time.Now,
+		posts:    make(map[string][]time.Time),
+	}
+}
+
+// Execute records the post for the content's author and stores "rate_limit_exceeded".
+// When the limit is exceeded the spam score is raised by 0.5, capped at 1.0, and
+// the "rate_limit" spam signal is recorded.
+// Content without an AuthorID is not rate limited.
+func (p *RateLimitPlugin) Execute(ctx *core.Context) error {
+	content, ok := ctx.GetData().(*Content)
+	if !ok {
+		return fmt.Errorf("expected *Content, got %T", ctx.GetData())
+	}
+
+	if content.AuthorID == "" {
+		ctx.Set("rate_limit_exceeded", false)
+		return nil
+	}
+
+	exceeded := p.record(content.AuthorID)
+	ctx.Set("rate_limit_exceeded", exceeded)
+
+	if exceeded {
+		spamScore := 0.0
+		if val, ok := ctx.Get("spam_score"); ok {
+			if score, ok := val.(float64); ok {
+				spamScore = score
+			}
+		}
+		spamScore += rateLimitSpamPenalty
+		if spamScore > 1.0 {
+			spamScore = 1.0
+		}
+		ctx.Set("spam_score", spamScore)
+		ctx.AddScore(ScoreCategorySpam, rateLimitSpamPenalty)
+
+		signals := append(stringsFromContext(ctx, "spam_signals"), SpamSignalRateLimit)
+		ctx.Set("spam_signals", signals)
+	}
+
+	return nil
+}
+
+// record adds a post for authorID and reports whether the author is now over the limit
+func (p *RateLimitPlugin) record(authorID string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	cutoff := now.Add(-p.window)
+	p.sweep(now, cutoff)
+
+	// Drop posts that have left the window
+	posts := p.posts[authorID]
+	kept := posts[:0]
+	for _, postedAt := range posts {
+		if postedAt.After(cutoff) {
+			kept = append(kept, postedAt)
+		}
+	}
+	kept = append(kept, now)
+	p.posts[authorID] = kept
+
+	return len(kept) > p.maxItems
+}
+
+// sweep forgets authors with no posts inside the window, at most once per window, so
+// the map does not grow with every author ever seen. The caller must hold the lock.
+func (p *RateLimitPlugin) sweep(now, cutoff time.Time) {
+	if now.Sub(p.lastSweep) < p.window {
+		return
+	}
+	p.lastSweep = now
+
+	for authorID, posts := range p.posts {
+		if len(posts) == 0 || !posts[len(posts)-1].After(cutoff) {
+			delete(p.posts, authorID)
+		}
+	}
+}
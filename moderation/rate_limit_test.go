@@ -0,0 +1,94 @@
+package moderation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// limitedAt runs content from authorID through plugin and reports rate_limit_exceeded
+func limitedAt(t *testing.T, plugin *RateLimitPlugin, authorID string) (*core.Context, bool) {
+	t.Helper()
+	ctx := core.NewContext(&Content{Text: "hello", AuthorID: authorID})
+	if err := plugin.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	exceeded, _ := core.Value[bool](ctx, "rate_limit_exceeded")
+	return ctx, exceeded
+}
+
+func TestRateLimitPluginPerAuthor(t *testing.T) {
+	plugin := NewRateLimitPlugin(3, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		if _, exceeded := limitedAt(t, plugin, "spammer"); exceeded {
+			t.Fatalf("post %d flagged within the limit", i+1)
+		}
+	}
+	ctx, exceeded := limitedAt(t, plugin, "spammer")
+	if !exceeded {
+		t.Fatal("fourth post within the window not flagged")
+	}
+	if score, _ := core.Value[float64](ctx, "spam_score"); score != rateLimitSpamPenalty {
+		t.Errorf("spam_score = %v, want %v", score, rateLimitSpamPenalty)
+	}
+	if signals, _ := core.Value[[]string](ctx, "spam_signals"); len(signals) != 1 || signals[0] != SpamSignalRateLimit {
+		t.Errorf("spam_signals = %v, want [%s]", signals, SpamSignalRateLimit)
+	}
+
+	if _, exceeded := limitedAt(t, plugin, "regular"); exceeded {
+		t.Error("another author flagged by the spammer's posts")
+	}
+}
+
+func TestRateLimitPluginWindowSlides(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	plugin := NewRateLimitPlugin(1, time.Minute)
+	plugin.now = func() time.Time { return now }
+
+	limitedAt(t, plugin, "alice")
+	if _, exceeded := limitedAt(t, plugin, "alice"); !exceeded {
+		t.Fatal("second post within the window not flagged")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, exceeded := limitedAt(t, plugin, "alice"); exceeded {
+		t.Error("post flagged after earlier posts left the window")
+	}
+}
+
+func TestRateLimitPluginForgetsIdleAuthors(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	plugin := NewRateLimitPlugin(5, time.Minute)
+	plugin.now = func() time.Time { return now }
+
+	for _, author := range []string{"a", "b", "c"} {
+		limitedAt(t, plugin, author)
+	}
+	now = now.Add(2 * time.Minute)
+	limitedAt(t, plugin, "d")
+
+	if len(plugin.posts) != 1 {
+		t.Errorf("tracked authors = %d, want only the recent one", len(plugin.posts))
+	}
+}
+
+func TestRateLimitPluginDefaults(t *testing.T) {
+	plugin := NewRateLimitPlugin(0, 0)
+	if plugin.maxItems <= 0 || plugin.window <= 0 {
+		t.Fatalf("maxItems = %d, window = %v, want positive defaults", plugin.maxItems, plugin.window)
+	}
+	if _, exceeded := limitedAt(t, plugin, "alice"); exceeded {
+		t.Error("first post flagged with the default limit")
+	}
+}
+
+func TestRateLimitPluginAnonymous(t *testing.T) {
+	plugin := NewRateLimitPlugin(1, time.Minute)
+	for i := 0; i < 3; i++ {
+		if _, exceeded := limitedAt(t, plugin, ""); exceeded {
+			t.Fatal("content without an author was rate limited")
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package moderation
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// NeutralReputation is the reputation of authors without any history
+const NeutralReputation = 0.5
+
+// ReputationStore provides author reputations between 0.0 (untrusted) and 1.0 (trusted).
+// Implementations must be safe for concurrent use. Stores backed by an external service
+// should also implement core.HealthChecker.
+type ReputationStore interface {
+	Score(authorID string) (float64, error)
+}
+
+// MemoryReputationStore is an in-process ReputationStore backed by a map.
+// Authors without a stored score have NeutralReputation.
+type MemoryReputationStore struct {
+	mu     sync.RWMutex
+	scores map[string]float64
+}
+
+// NewMemoryReputationStore creates an empty in-memory reputation store
+func NewMemoryReputationStore() *MemoryReputationStore {
+	return &MemoryReputationStore{
+		scores: make(map[string]float64),
+	}
+}
+
+// Score returns the stored reputation for the author, or NeutralReputation
+func (s *MemoryReputationStore) Score(authorID string) (float64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	score, exists := s.scores[authorID]
+	if !exists {
+		return NeutralReputation, nil
+	}
+	return score, nil
+}
+
+// SetScore stores a reputation for the author, clamped to [0.0, 1.0]
+func (s *MemoryReputationStore) SetScore(authorID string, score float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.scores[authorID] = max(0.0, min(score, 1.0))
+}
+
+// ReputationPlugin loads the reputation of the content's author. DecisionRouterPlugin
+// adjusts its thresholds by the reputation when configured with ReputationLeniency.
+type ReputationPlugin struct {
+	store ReputationStore
+}
+
+// NewReputationPlugin creates a reputation plugin backed by store.
+// A nil store uses an empty MemoryReputationStore, giving every author a neutral reputation.
+func NewReputationPlugin(store ReputationStore) *ReputationPlugin {
+	if store == nil {
+		store = NewMemoryReputationStore()
+	}
+	return &ReputationPlugin{
+		store: store,
+	}
+}
+
+// Execute stores the author's reputation under "author_reputation".
+// Content without an AuthorID gets NeutralReputation.
+func (p *ReputationPlugin) Execute(ctx *core.Context) error {
+	content, ok := ctx.GetData().(*Content)
+	if !ok {
+		return fmt.Errorf("expected *Content, got %T", ctx.GetData())
+	}
+
+	reputation := NeutralReputation
+	if content.AuthorID != "" {
+		score, err := p.store.Score(content.AuthorID)
+		if err != nil {
+			return fmt.Errorf("failed to load reputation for %q: %w", content.AuthorID, err)
+		}
+		reputation = score
+	}
+
+	ctx.Set("author_reputation", reputation)
+	return nil
+}
+
+// HealthCheck reports the health of the reputation store, if it implements core.HealthChecker
+func (p *ReputationPlugin) HealthCheck() error {
+	if checker, ok := p.store.(core.HealthChecker); ok {
+		return checker.HealthCheck()
+	}
+	return nil
+}
+
+// Requires returns the metadata keys ReputationPlugin reads
+func (p *ReputationPlugin) Requires() []string { return nil }
+
+// Provides returns the metadata keys ReputationPlugin sets
+func (p *ReputationPlugin) Provides() []string {
+	return []string{"author_reputation"}
+}
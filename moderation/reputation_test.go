@@ -0,0 +1,78 @@
+package moderation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// failingReputationStore fails every lookup
+type failingReputationStore struct{}
+
+func (failingReputationStore) Score(string) (float64, error) { return 0, errors.New("unavailable") }
+
+func TestReputationPlugin(t *testing.T) {
+	store := NewMemoryReputationStore()
+	store.SetScore("trusted", 1.5)
+	store.SetScore("spammer", 0.1)
+	plugin := NewReputationPlugin(store)
+
+	tests := []struct {
+		authorID string
+		want     float64
+	}{
+		{"trusted", 1.0},
+		{"spammer", 0.1},
+		{"newcomer", NeutralReputation},
+		{"", NeutralReputation},
+	}
+	for _, tt := range tests {
+		ctx := core.NewContext(&Content{Text: "hi", AuthorID: tt.authorID})
+		if err := plugin.Execute(ctx); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		if got, _ := core.Value[float64](ctx, "author_reputation"); got != tt.want {
+			t.Errorf("author %q: author_reputation = %v, want %v", tt.authorID, got, tt.want)
+		}
+	}
+}
+
+func TestReputationPluginStoreError(t *testing.T) {
+	plugin := NewReputationPlugin(failingReputationStore{})
+	if err := plugin.Execute(core.NewContext(&Content{Text: "hi", AuthorID: "alice"})); err == nil {
+		t.Error("Execute succeeded with a failing store")
+	}
+}
+
+func TestDecisionRouterReputationLeniency(t *testing.T) {
+	plugin := NewDecisionRouterPluginWithConfig(DecisionRouterConfig{ReputationLeniency: 0.2})
+	score := ApproveThreshold + 0.15
+
+	tests := []struct {
+		reputation any
+		want       string
+	}{
+		{NeutralReputation, "review"},
+		{1.0, "approve"},
+		{nil, "review"},
+	}
+	for _, tt := range tests {
+		metadata := map[string]any{}
+		if tt.reputation != nil {
+			metadata["author_reputation"] = tt.reputation
+		}
+		if decision := decide(t, plugin, score, metadata); decision.Action != tt.want {
+			t.Errorf("reputation %v: action = %s, want %s", tt.reputation, decision.Action, tt.want)
+		}
+	}
+
+	// An untrusted author is rejected at a score a neutral author only gets reviewed for
+	almostReject := ReviewThreshold - 0.05
+	if decision := decide(t, plugin, almostReject, map[string]any{"author_reputation": 0.0}); decision.Action != "reject" {
+		t.Errorf("untrusted author: action = %s, want reject", decision.Action)
+	}
+	if decision := decide(t, NewDecisionRouterPlugin(), score, map[string]any{"author_reputation": 1.0}); decision.Action != "review" {
+		t.Errorf("without leniency: action = %s, want reputation ignored", decision.Action)
+	}
+}
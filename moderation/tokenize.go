@@ -0,0 +1,30 @@
+package moderation
+
+import (
+	"strings"
+	"unicode"
+)
+
+// tokenize splits text into words on Unicode word boundaries.
+// Letters, digits, combining marks, and in-word apostrophes form words; all other
+// characters, including Unicode punctuation, symbols, and emoji, separate them.
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return !isWordRune(r)
+	})
+
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		// Apostrophes only belong inside words ("don't"), not around them ('quoted')
+		token := strings.Trim(field, "'’")
+		if token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// isWordRune reports whether r can be part of a word token
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r) || r == '\'' || r == '’'
+}
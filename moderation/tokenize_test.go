@@ -0,0 +1,58 @@
+package moderation
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"great!!! 😍😍 love it", []string{"great", "love", "it"}},
+		{"«terrible»… truly—awful", []string{"terrible", "truly", "awful"}},
+		{"café naïve résumé", []string{"café", "naïve", "résumé"}},
+		{"don't 'quote' me", []string{"don't", "quote", "me"}},
+		{"it’s fine", []string{"it’s", "fine"}},
+		{"👍🎉", []string{}},
+	}
+	for _, test := range tests {
+		if got := tokenize(test.text); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("tokenize(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestTokenizeKeepsRunes(t *testing.T) {
+	tokens := tokenize("naïve 🙂 crème")
+	if len(tokens) != 2 {
+		t.Fatalf("tokens = %q, want 2", tokens)
+	}
+	if n := utf8.RuneCountInString(tokens[0]); n != 5 {
+		t.Errorf("rune count of %q = %d, want 5", tokens[0], n)
+	}
+	if n := utf8.RuneCountInString(tokens[1]); n != 5 {
+		t.Errorf("rune count of %q = %d, want 5", tokens[1], n)
+	}
+}
+
+func TestSentimentAnalyzerEmojiText(t *testing.T) {
+	analysis := NewSentimentAnalyzerPlugin().Analyze("😍great😍, just AWFUL!!!")
+	if !reflect.DeepEqual(analysis.PositiveWords, []string{"great"}) {
+		t.Errorf("PositiveWords = %q, want [great]", analysis.PositiveWords)
+	}
+	if !reflect.DeepEqual(analysis.NegativeWords, []string{"awful"}) {
+		t.Errorf("NegativeWords = %q, want [awful]", analysis.NegativeWords)
+	}
+}
+
+func TestSpamDetectorCapsIgnoresEmoji(t *testing.T) {
+	// Emoji and symbols between the words mustn't count toward or against the caps ratio
+	ctx, _ := moderate(t, moderationPipeline(), "WOW 🔥🔥🔥 BUY NOW ✨✨ cheap")
+	signals, _ := ctx.Get("spam_signals")
+	if !reflect.DeepEqual(signals, []string{SpamSignalCaps}) {
+		t.Errorf("spam_signals = %v, want [%s]", signals, SpamSignalCaps)
+	}
+}
@@ -0,0 +1,126 @@
+package moderation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// Topic names used by DefaultTopicBuckets
+const (
+	TopicGeneral  = "general" // Content matching no bucket
+	TopicPolitics = "politics"
+	TopicSports   = "sports"
+	TopicFinance  = "finance"
+	TopicHealth   = "health"
+)
+
+// TopicBucket is a topic and the keywords that indicate it. Keywords may be phrases.
+type TopicBucket struct {
+	Name     string
+	Keywords []string
+}
+
+// DefaultTopicBuckets returns the built-in topics and their keywords
+func DefaultTopicBuckets() []TopicBucket {
+	return []TopicBucket{
+		{Name: TopicPolitics, Keywords: []string{
+			"election", "vote", "voting", "president", "senate", "congress", "parliament",
+			"government", "policy", "democrat", "republican", "campaign", "politics", "political",
+		}},
+		{Name: TopicSports, Keywords: []string{
+			"game", "match", "team", "score", "goal", "league", "season", "player",
+			"coach", "championship", "football", "soccer", "basketball", "tournament",
+		}},
+		{Name: TopicFinance, Keywords: []string{
+			"stock", "stocks", "invest", "investment", "crypto", "bitcoin", "market",
+			"trading", "loan", "interest rate", "profit", "dividend",
+		}},
+		{Name: TopicHealth, Keywords: []string{
+			"doctor", "medicine", "vaccine", "symptom", "symptoms", "treatment", "diet",
+			"disease", "hospital", "therapy", "mental health",
+		}},
+	}
+}
+
+// TopicClassifierPlugin tags content with the topic whose keywords it mentions most, so
+// DecisionRouterPlugin can apply topic-specific thresholds. Content matching no bucket is
+// tagged TopicGeneral; ties go to the bucket listed first.
+type TopicClassifierPlugin struct {
+	buckets []TopicBucket
+}
+
+// NewTopicClassifierPlugin creates a topic classifier with the default buckets
+func NewTopicClassifierPlugin() *TopicClassifierPlugin {
+	return NewTopicClassifierPluginWithBuckets(DefaultTopicBuckets())
+}
+
+// NewTopicClassifierPluginWithBuckets creates a topic classifier with custom buckets
+func NewTopicClassifierPluginWithBuckets(buckets []TopicBucket) *TopicClassifierPlugin {
+	lowered := make([]TopicBucket, len(buckets))
+	for i, bucket := range buckets {
+		keywords := make([]string, len(bucket.Keywords))
+		for j, keyword := range bucket.Keywords {
+			keywords[j] = strings.ToLower(keyword)
+		}
+		lowered[i] = TopicBucket{Name: bucket.Name, Keywords: keywords}
+	}
+
+	return &TopicClassifierPlugin{
+		buckets: lowered,
+	}
+}
+
+// Execute stores the topic under "topic" and the keywords that matched it under
+// "topic_keywords"
+func (p *TopicClassifierPlugin) Execute(ctx *core.Context) error {
+	content, ok := ctx.GetData().(*Content)
+	if !ok {
+		return fmt.Errorf("expected *Content, got %T", ctx.GetData())
+	}
+
+	topic, keywords := p.Classify(content.Text)
+	ctx.Set("topic", topic)
+	ctx.Set("topic_keywords", keywords)
+	return nil
+}
+
+// Classify returns the topic of text and the keywords that matched it
+func (p *TopicClassifierPlugin) Classify(text string) (string, []string) {
+	// Match whole words, and phrases against the words joined by single spaces
+	words := tokenize(strings.ToLower(text))
+	wordSet := make(map[string]bool, len(words))
+	for _, word := range words {
+		wordSet[word] = true
+	}
+	joined := " " + strings.Join(words, " ") + " "
+
+	topic := TopicGeneral
+	best := make([]string, 0)
+	for _, bucket := range p.buckets {
+		matches := make([]string, 0)
+		for _, keyword := range bucket.Keywords {
+			if strings.Contains(keyword, " ") {
+				if strings.Contains(joined, " "+keyword+" ") {
+					matches = append(matches, keyword)
+				}
+			} else if wordSet[keyword] {
+				matches = append(matches, keyword)
+			}
+		}
+		if len(matches) > len(best) {
+			topic = bucket.Name
+			best = matches
+		}
+	}
+	return topic, best
+}
+
+// Requires returns the metadata keys TopicClassifierPlugin reads
+func (p *TopicClassifierPlugin) Requires() []string { return nil }
+
+// Provides returns the metadata keys TopicClassifierPlugin sets
+func (p *TopicClassifierPlugin) Provides() []string {
+	return []string{"topic", "topic_keywords"}
+}
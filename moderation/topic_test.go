@@ -0,0 +1,59 @@
+package moderation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+func TestTopicClassifier(t *testing.T) {
+	plugin := NewTopicClassifierPlugin()
+	tests := []struct {
+		text     string
+		topic    string
+		keywords []string
+	}{
+		{"Who will win the election? Vote for the senate!", TopicPolitics, []string{"election", "vote", "senate"}},
+		{"Talk to your doctor about mental health", TopicHealth, []string{"doctor", "mental health"}},
+		{"The team won the game", TopicSports, []string{"game", "team"}},
+		{"Nice weather today", TopicGeneral, []string{}},
+		// Whole words only: "votes" and "gamer" don't match
+		{"gamer votes", TopicGeneral, []string{}},
+	}
+
+	for _, test := range tests {
+		topic, keywords := plugin.Classify(test.text)
+		if topic != test.topic || !reflect.DeepEqual(keywords, test.keywords) {
+			t.Errorf("Classify(%q) = %q, %v, want %q, %v", test.text, topic, keywords, test.topic, test.keywords)
+		}
+	}
+}
+
+func TestDecisionRouterTopicThresholds(t *testing.T) {
+	plugin := NewDecisionRouterPluginWithConfig(DecisionRouterConfig{
+		TopicThresholds: map[string]Thresholds{TopicPolitics: {Approve: 0.1, Review: 0.3}},
+	})
+
+	if decision := decide(t, plugin, 0.2, map[string]any{"topic": TopicPolitics}); decision.Action != "review" {
+		t.Errorf("politics at 0.2: action = %q, want review under the stricter thresholds", decision.Action)
+	}
+	if decision := decide(t, plugin, 0.2, map[string]any{"topic": TopicSports}); decision.Action != "approve" {
+		t.Errorf("sports at 0.2: action = %q, want approve under the default thresholds", decision.Action)
+	}
+
+	// The router must run after the topic classifier
+	if !containsString(plugin.Requires(), "topic") {
+		t.Errorf("Requires = %v, want topic", plugin.Requires())
+	}
+	pipeline := core.NewPipeline(core.AbortOnError).
+		Use(NewProfanityFilterPlugin()).
+		Use(NewScoringPlugin()).
+		Use(plugin)
+	if err := pipeline.Validate(); err == nil {
+		t.Error("Validate succeeded without a topic classifier")
+	}
+	if containsString(NewDecisionRouterPlugin().Requires(), "topic") {
+		t.Error("router without topic thresholds requires topic")
+	}
+}
@@ -0,0 +1,109 @@
+package moderation
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// URL classification statuses
+const (
+	URLStatusBlocked = "blocked" // Host matches the blocklist
+	URLStatusAllowed = "allowed" // Host matches the allowlist
+	URLStatusUnknown = "unknown" // Host matches neither list
+)
+
+// URLAnalyzerPlugin extracts URLs from content and classifies their hosts against
+// a blocklist and an allowlist. A domain in either list also matches its subdomains.
+type URLAnalyzerPlugin struct {
+	urlPattern *regexp.Regexp
+	blocklist  []string
+	allowlist  []string
+}
+
+// NewURLAnalyzerPlugin creates a new URL analyzer with the given domain lists
+func NewURLAnalyzerPlugin(blocklist, allowlist []string) *URLAnalyzerPlugin {
+	return &URLAnalyzerPlugin{
+		urlPattern: regexp.MustCompile(`https?://[^\s]+`),
+		blocklist:  normalizeDomains(blocklist),
+		allowlist:  normalizeDomains(allowlist),
+	}
+}
+
+// Execute extracts URLs, stores them under "extracted_urls", and stores a "url_risk_score".
+// Each blocklisted URL adds 0.5 and each unknown URL adds 0.1 to the risk score, capped at 1.0;
+// allowlisted URLs add nothing. The risk score is also added to the "spam" score category, on
+// top of what SpamDetectorPlugin found.
+func (p *URLAnalyzerPlugin) Execute(ctx *core.Context) error {
+	content, ok := ctx.GetData().(*Content)
+	if !ok {
+		return fmt.Errorf("expected *Content, got %T", ctx.GetData())
+	}
+
+	extracted := make([]ExtractedURL, 0)
+	score := 0.0
+
+	for _, raw := range p.urlPattern.FindAllString(content.Text, -1) {
+		// Drop punctuation that ends the surrounding sentence
+		raw = strings.TrimRight(raw, ".,;:!?)]}'\"")
+
+		parsed, err := url.Parse(raw)
+		if err != nil || parsed.Hostname() == "" {
+			continue
+		}
+
+		host := strings.ToLower(parsed.Hostname())
+		status := URLStatusUnknown
+		switch {
+		case matchesDomain(host, p.blocklist):
+			status = URLStatusBlocked
+			score += 0.5
+		case matchesDomain(host, p.allowlist):
+			status = URLStatusAllowed
+		default:
+			score += 0.1
+		}
+
+		extracted = append(extracted, ExtractedURL{
+			URL:    raw,
+			Host:   host,
+			Status: status,
+		})
+	}
+
+	// Cap at 1.0
+	if score > 1.0 {
+		score = 1.0
+	}
+
+	ctx.Set("extracted_urls", extracted)
+	ctx.Set("url_risk_score", score)
+	// Risky links are a spam signal
+	ctx.AddScore(ScoreCategorySpam, score)
+	return nil
+}
+
+// normalizeDomains lowercases domains and removes leading dots
+func normalizeDomains(domains []string) []string {
+	normalized := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), ".")
+		if domain != "" {
+			normalized = append(normalized, domain)
+		}
+	}
+	return normalized
+}
+
+// matchesDomain reports whether host is one of the domains or a subdomain of one
+func matchesDomain(host string, domains []string) bool {
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package moderation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+func TestURLAnalyzer(t *testing.T) {
+	plugin := NewURLAnalyzerPlugin([]string{"Scam.com"}, []string{".example.org"})
+	ctx := core.NewContext(&Content{Text: "see https://www.SCAM.com/x, https://docs.example.org and (https://other.net)."})
+	if err := plugin.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	want := []ExtractedURL{
+		{URL: "https://www.SCAM.com/x", Host: "www.scam.com", Status: URLStatusBlocked},
+		{URL: "https://docs.example.org", Host: "docs.example.org", Status: URLStatusAllowed},
+		{URL: "https://other.net", Host: "other.net", Status: URLStatusUnknown},
+	}
+	if got, _ := core.Value[[]ExtractedURL](ctx, "extracted_urls"); !reflect.DeepEqual(got, want) {
+		t.Errorf("extracted_urls = %+v, want %+v", got, want)
+	}
+	if score, _ := core.Value[float64](ctx, "url_risk_score"); score != 0.6 {
+		t.Errorf("url_risk_score = %v, want 0.6", score)
+	}
+}
+
+func TestURLAnalyzerRiskCapped(t *testing.T) {
+	plugin := NewURLAnalyzerPlugin([]string{"scam.com"}, nil)
+	ctx := core.NewContext(&Content{Text: "http://scam.com/a http://a.scam.com http://b.scam.com"})
+	plugin.Execute(ctx)
+
+	if score, _ := core.Value[float64](ctx, "url_risk_score"); score != 1.0 {
+		t.Errorf("url_risk_score = %v, want 1.0", score)
+	}
+}
+
+func TestURLAnalyzerNoURLs(t *testing.T) {
+	ctx := core.NewContext(&Content{Text: "no links here, just scam.com in text"})
+	NewURLAnalyzerPlugin([]string{"scam.com"}, nil).Execute(ctx)
+
+	urls, ok := core.Value[[]ExtractedURL](ctx, "extracted_urls")
+	if !ok || len(urls) != 0 {
+		t.Errorf("extracted_urls = %v, want an empty list", urls)
+	}
+	if score, _ := core.Value[float64](ctx, "url_risk_score"); score != 0 {
+		t.Errorf("url_risk_score = %v, want 0", score)
+	}
+}
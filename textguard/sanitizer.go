@@ -0,0 +1,100 @@
+// Package textguard provides plugins that inspect and clean the text of both chat
+// messages (chatbot.Message) and moderated content (moderation.Content).
+package textguard
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+
+	"github.com/dvictor357/pipeline-plugin-system/chatbot"
+	"github.com/dvictor357/pipeline-plugin-system/core"
+	"github.com/dvictor357/pipeline-plugin-system/moderation"
+)
+
+// SanitizePolicy selects how SanitizerPlugin neutralizes HTML
+type SanitizePolicy int
+
+const (
+	// Strip removes tags, and the contents of script and style elements, keeping the
+	// surrounding text
+	Strip SanitizePolicy = iota
+	// Escape HTML-escapes the whole text, so markup is displayed rather than rendered
+	Escape
+)
+
+var (
+	// blockPattern matches elements whose content is code rather than text
+	blockPattern = regexp.MustCompile(`(?is)<(script|style)\b[^>]*>.*?</(?:script|style)\s*>|<(?:script|style)\b[^>]*>`)
+	// tagPattern matches tags and comments, but not a bare "<" as in "a < b"
+	tagPattern = regexp.MustCompile(`(?s)<!--.*?-->|</?[a-zA-Z][^>]*>`)
+	// dangerousPattern matches markup that can run code: script-capable elements, event
+	// handler attributes and javascript: URLs
+	dangerousPattern = regexp.MustCompile(`(?i)<\s*/?\s*(?:script|iframe|object|embed|style|svg|meta|link|base|form)\b|<[^>]*\son[a-z]+\s*=|javascript\s*:`)
+)
+
+// SanitizerPlugin neutralizes HTML and script injection in the text of a chatbot.Message
+// or *moderation.Content, so the text is safe to render later. It replaces the text with
+// the sanitized text and sets "unsafe_markup" when the original held markup that can run
+// code, such as a script element or an onclick attribute, and then also adds a full
+// "spam" score for the moderation ScoringPlugin. Place it first.
+type SanitizerPlugin struct {
+	policy SanitizePolicy
+}
+
+// NewSanitizerPlugin creates a sanitizer with the given policy
+func NewSanitizerPlugin(policy SanitizePolicy) *SanitizerPlugin {
+	return &SanitizerPlugin{
+		policy: policy,
+	}
+}
+
+// Execute sanitizes the text and sets "unsafe_markup"
+func (p *SanitizerPlugin) Execute(ctx *core.Context) error {
+	var unsafe bool
+	switch data := ctx.GetData().(type) {
+	case chatbot.Message:
+		unsafe = dangerousPattern.MatchString(data.Text)
+		data.Text = p.Sanitize(data.Text)
+		ctx.SetData(data)
+	case *moderation.Content:
+		unsafe = dangerousPattern.MatchString(data.Text)
+		// Copy rather than modify the caller's content
+		sanitized := *data
+		sanitized.Text = p.Sanitize(data.Text)
+		ctx.SetData(&sanitized)
+	default:
+		return fmt.Errorf("expected chatbot.Message or *moderation.Content, got %T", ctx.GetData())
+	}
+
+	ctx.Set("unsafe_markup", unsafe)
+	// An injection attempt is treated as spam
+	if unsafe {
+		ctx.AddScore(moderation.ScoreCategorySpam, 1.0)
+	}
+	return nil
+}
+
+// Sanitize returns text with HTML neutralized according to the policy
+func (p *SanitizerPlugin) Sanitize(text string) string {
+	if p.policy == Escape {
+		return html.EscapeString(text)
+	}
+	// Repeat until nothing changes, since removing a tag can join the text around it into
+	// a new one, as in "<scr<b>ipt>"
+	for {
+		stripped := tagPattern.ReplaceAllString(blockPattern.ReplaceAllString(text, ""), "")
+		if stripped == text {
+			return text
+		}
+		text = stripped
+	}
+}
+
+// Requires returns the metadata keys SanitizerPlugin reads
+func (p *SanitizerPlugin) Requires() []string { return nil }
+
+// Provides returns the metadata keys SanitizerPlugin sets
+func (p *SanitizerPlugin) Provides() []string {
+	return []string{"unsafe_markup", core.ScoresKey}
+}
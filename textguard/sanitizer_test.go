@@ -0,0 +1,70 @@
+package textguard
+
+import (
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/chatbot"
+	"github.com/dvictor357/pipeline-plugin-system/core"
+	"github.com/dvictor357/pipeline-plugin-system/moderation"
+)
+
+func TestSanitizeStrip(t *testing.T) {
+	plugin := NewSanitizerPlugin(Strip)
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"<b>bold</b> move", "bold move"},
+		{"hi<script>alert(1)</script> there", "hi there"},
+		{"<style>p{}</style>text<!-- note -->", "text"},
+		{"<<b>script>alert(1)</script>", "alert(1)"},
+		{"if a < b and b > c", "if a < b and b > c"},
+	}
+
+	for _, test := range tests {
+		if got := plugin.Sanitize(test.text); got != test.want {
+			t.Errorf("Sanitize(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestSanitizeEscape(t *testing.T) {
+	got := NewSanitizerPlugin(Escape).Sanitize(`<a href="x">hi</a>`)
+	if want := "&lt;a href=&#34;x&#34;&gt;hi&lt;/a&gt;"; got != want {
+		t.Errorf("Sanitize = %q, want %q", got, want)
+	}
+}
+
+func TestSanitizerExecute(t *testing.T) {
+	plugin := NewSanitizerPlugin(Strip)
+
+	original := &moderation.Content{Text: `<img src=x onerror="alert(1)">hello`}
+	ctx := core.NewContext(original)
+	if err := plugin.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if text := ctx.GetData().(*moderation.Content).Text; text != "hello" {
+		t.Errorf("text = %q, want hello", text)
+	}
+	if original.Text == "hello" {
+		t.Error("caller's content was modified")
+	}
+	if unsafe, _ := core.Value[bool](ctx, "unsafe_markup"); !unsafe {
+		t.Error("unsafe_markup = false for an event handler attribute")
+	}
+	if score := ctx.Scores()[moderation.ScoreCategorySpam]; score != 1.0 {
+		t.Errorf("spam score = %v, want 1.0", score)
+	}
+
+	ctx = core.NewContext(chatbot.Message{Text: "<i>safe</i> text"})
+	if err := plugin.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if unsafe, _ := core.Value[bool](ctx, "unsafe_markup"); unsafe || ctx.GetData().(chatbot.Message).Text != "safe text" {
+		t.Errorf("message = %+v, unsafe = %v, want safe text without unsafe markup", ctx.GetData(), unsafe)
+	}
+
+	if err := plugin.Execute(core.NewContext("plain string")); err == nil {
+		t.Error("Execute with a string succeeded, want an error")
+	}
+}
@@ -0,0 +1,48 @@
+package textguard
+
+import (
+	"github.com/dvictor357/pipeline-plugin-system/core"
+	"github.com/dvictor357/pipeline-plugin-system/moderation"
+)
+
+// SentimentPlugin scores the sentiment and toxicity of a chatbot.Message or
+// *moderation.Content with a moderation.SentimentAnalyzerPlugin, setting "sentiment_score"
+// and "toxicity_score". Place it before a chatbot.ContextManagerPlugin with a TrendWindow
+// to track the sentiment trend of a conversation.
+type SentimentPlugin struct {
+	analyzer *moderation.SentimentAnalyzerPlugin
+}
+
+// NewSentimentPlugin creates a sentiment plugin with the default lexicons
+func NewSentimentPlugin() *SentimentPlugin {
+	return NewSentimentPluginWithAnalyzer(moderation.NewSentimentAnalyzerPlugin())
+}
+
+// NewSentimentPluginWithAnalyzer creates a sentiment plugin that scores text with analyzer,
+// such as one with a custom lexicon
+func NewSentimentPluginWithAnalyzer(analyzer *moderation.SentimentAnalyzerPlugin) *SentimentPlugin {
+	return &SentimentPlugin{
+		analyzer: analyzer,
+	}
+}
+
+// Execute scores the text and sets "sentiment_score" and "toxicity_score"
+func (p *SentimentPlugin) Execute(ctx *core.Context) error {
+	text, err := dataText(ctx)
+	if err != nil {
+		return err
+	}
+
+	analysis := p.analyzer.Analyze(text)
+	ctx.Set("sentiment_score", analysis.Sentiment)
+	ctx.Set("toxicity_score", analysis.Toxicity)
+	return nil
+}
+
+// Requires returns the metadata keys SentimentPlugin reads
+func (p *SentimentPlugin) Requires() []string { return nil }
+
+// Provides returns the metadata keys SentimentPlugin sets
+func (p *SentimentPlugin) Provides() []string {
+	return []string{"sentiment_score", "toxicity_score"}
+}
@@ -0,0 +1,56 @@
+package textguard
+
+import (
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/chatbot"
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+func TestSentimentTrendNegative(t *testing.T) {
+	pipeline := core.NewPipeline(core.AbortOnError).
+		Use(NewSentimentPlugin()).
+		Use(chatbot.NewContextManagerPluginWithOptions(chatbot.ContextManagerOptions{
+			MaxHistorySize: 10,
+			TrendWindow:    3,
+		}))
+	if err := pipeline.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	var ctx *core.Context
+	for _, text := range []string{"I love this, it is great", "it is ok I guess", "this is terrible, I hate it"} {
+		ctx = core.NewContext(chatbot.Message{SessionID: "s1", Text: text})
+		if err := pipeline.Execute(ctx); err != nil {
+			t.Fatalf("Execute(%q): %v", text, err)
+		}
+	}
+
+	trend, ok := core.Value[float64](ctx, "sentiment_trend")
+	if !ok || trend >= 0 {
+		t.Errorf("sentiment_trend = %v, %v, want a negative trend", trend, ok)
+	}
+}
+
+func TestSentimentTrendRequiresScore(t *testing.T) {
+	withTrend := core.NewPipeline(core.AbortOnError).
+		Use(chatbot.NewContextManagerPluginWithOptions(chatbot.ContextManagerOptions{TrendWindow: 3}))
+	if err := withTrend.Validate(); err == nil {
+		t.Error("Validate of a trend without a sentiment plugin succeeded, want an error")
+	}
+
+	withoutTrend := core.NewPipeline(core.AbortOnError).
+		Use(chatbot.NewContextManagerPlugin(10))
+	if err := withoutTrend.Validate(); err != nil {
+		t.Errorf("Validate without a trend: %v", err)
+	}
+
+	ctx := core.NewContext(chatbot.Message{SessionID: "s1", Text: "hi"})
+	ctx.Set("sentiment_score", 0.5)
+	if err := withoutTrend.Execute(ctx); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if _, ok := ctx.Get("sentiment_trend"); ok {
+		t.Error("sentiment_trend set without a trend window")
+	}
+}
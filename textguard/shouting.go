@@ -0,0 +1,95 @@
+package textguard
+
+import (
+	"unicode"
+
+	"github.com/dvictor357/pipeline-plugin-system/core"
+)
+
+// Defaults for ShoutingConfig
+const (
+	DefaultShoutingThreshold  = 0.7
+	DefaultShoutingMinLetters = 4
+)
+
+// ShoutingConfig configures ShoutingDetectorPlugin
+type ShoutingConfig struct {
+	// Threshold is the uppercase ratio at or above which text counts as shouting
+	// (default: DefaultShoutingThreshold)
+	Threshold float64
+	// MinLetters is the number of letters text needs before it can count as shouting, so
+	// short text such as "OK" or "USA" isn't flagged (default: DefaultShoutingMinLetters)
+	MinLetters int
+}
+
+// ShoutingDetectorPlugin detects all-caps shouting in the text of a chatbot.Message or
+// *moderation.Content. It sets "shouting_ratio", the share of letters that are uppercase,
+// and "shouting". Digits, punctuation, emoji and spaces are ignored, so "OMG!!! 100%"
+// is judged on its letters alone. Unlike the spam "caps" signal, it doesn't affect any
+// score; chatbot plugins can use it to adjust their tone.
+type ShoutingDetectorPlugin struct {
+	threshold  float64
+	minLetters int
+}
+
+// NewShoutingDetectorPlugin creates a shouting detector with default settings
+func NewShoutingDetectorPlugin() *ShoutingDetectorPlugin {
+	return NewShoutingDetectorPluginWithConfig(ShoutingConfig{})
+}
+
+// NewShoutingDetectorPluginWithConfig creates a shouting detector with custom settings
+func NewShoutingDetectorPluginWithConfig(config ShoutingConfig) *ShoutingDetectorPlugin {
+	if config.Threshold <= 0 {
+		config.Threshold = DefaultShoutingThreshold
+	}
+	if config.MinLetters <= 0 {
+		config.MinLetters = DefaultShoutingMinLetters
+	}
+	return &ShoutingDetectorPlugin{
+		threshold:  config.Threshold,
+		minLetters: config.MinLetters,
+	}
+}
+
+// Execute measures the uppercase ratio and sets "shouting" and "shouting_ratio"
+func (p *ShoutingDetectorPlugin) Execute(ctx *core.Context) error {
+	text, err := dataText(ctx)
+	if err != nil {
+		return err
+	}
+
+	shouting, ratio := p.Detect(text)
+	ctx.Set("shouting", shouting)
+	ctx.Set("shouting_ratio", ratio)
+	return nil
+}
+
+// Detect reports whether text is shouting, along with the share of its letters that are
+// uppercase. Text without letters has a ratio of 0.
+func (p *ShoutingDetectorPlugin) Detect(text string) (bool, float64) {
+	upperCount := 0
+	letterCount := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letterCount++
+		if unicode.IsUpper(r) {
+			upperCount++
+		}
+	}
+	if letterCount == 0 {
+		return false, 0
+	}
+
+	ratio := float64(upperCount) / float64(letterCount)
+	return letterCount >= p.minLetters && ratio >= p.threshold, ratio
+}
+
+// Requires returns the metadata keys ShoutingDetectorPlugin reads
+func (p *ShoutingDetectorPlugin) Requires() []string { return nil }
+
+// Provides returns the metadata keys ShoutingDetectorPlugin sets
+func (p *ShoutingDetectorPlugin) Provides() []string {
+	return []string{"shouting", "shouting_ratio"}
+}
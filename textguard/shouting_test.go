@@ -0,0 +1,53 @@
+package textguard
+
+import (
+	"testing"
+
+	"github.com/dvictor357/pipeline-plugin-system/chatbot"
+	"github.com/dvictor357/pipeline-plugin-system/core"
+	"github.com/dvictor357/pipeline-plugin-system/moderation"
+)
+
+func TestShoutingDetect(t *testing.T) {
+	plugin := NewShoutingDetectorPlugin()
+	tests := []struct {
+		text     string
+		shouting bool
+		ratio    float64
+	}{
+		{"WHERE IS MY ORDER", true, 1},
+		{"OMG!!! 100% 🎉 WOW", true, 1},
+		{"OK", false, 1},
+		{"where IS my ORDER", false, 0.5},
+		{"ÉCOUTEZ MOI", true, 1},
+		{"123 !!!", false, 0},
+	}
+
+	for _, test := range tests {
+		shouting, ratio := plugin.Detect(test.text)
+		if shouting != test.shouting || ratio != test.ratio {
+			t.Errorf("Detect(%q) = %v, %v, want %v, %v", test.text, shouting, ratio, test.shouting, test.ratio)
+		}
+	}
+
+	lenient := NewShoutingDetectorPluginWithConfig(ShoutingConfig{Threshold: 0.4, MinLetters: 2})
+	if shouting, _ := lenient.Detect("where IS my ORDER"); !shouting {
+		t.Error("custom threshold not applied")
+	}
+}
+
+func TestShoutingDetectorExecute(t *testing.T) {
+	plugin := NewShoutingDetectorPlugin()
+	for _, data := range []any{chatbot.Message{Text: "HELP ME NOW"}, &moderation.Content{Text: "HELP ME NOW"}} {
+		ctx := core.NewContext(data)
+		if err := plugin.Execute(ctx); err != nil {
+			t.Fatalf("Execute(%T): %v", data, err)
+		}
+		if shouting, _ := core.Value[bool](ctx, "shouting"); !shouting {
+			t.Errorf("%T: shouting = false, want true", data)
+		}
+	}
+	if err := plugin.Execute(core.NewContext(42)); err == nil {
+		t.Error("Execute with an int succeeded, want an error")
+	}
+}
@@ -0,0 +1,21 @@
+package textguard
+
+import (
+	"fmt"
+
+	"github.com/dvictor357/pipeline-plugin-system/chatbot"
+	"github.com/dvictor357/pipeline-plugin-system/core"
+	"github.com/dvictor357/pipeline-plugin-system/moderation"
+)
+
+// dataText returns the text of the chatbot.Message or *moderation.Content in ctx
+func dataText(ctx *core.Context) (string, error) {
+	switch data := ctx.GetData().(type) {
+	case chatbot.Message:
+		return data.Text, nil
+	case *moderation.Content:
+		return data.Text, nil
+	default:
+		return "", fmt.Errorf("expected chatbot.Message or *moderation.Content, got %T", ctx.GetData())
+	}
+}